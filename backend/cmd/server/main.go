@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
 	"orders/internal/api"
 	"orders/internal/config"
+	"orders/internal/jobs"
+	"orders/internal/mailer"
 	"orders/internal/migrations"
 	"orders/internal/repository"
 	"orders/internal/seeds"
 	"orders/internal/service"
+	"orders/internal/storage"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
@@ -34,6 +39,11 @@ func main() {
 	}
 	log.Println("✅ Migration completed successfully")
 
+	// One-off data migrations (before orphaned columns are dropped)
+	if err := migrations.RunDataMigrations(db); err != nil {
+		log.Fatal("data migration failed:", err)
+	}
+
 	// Analyze schema differences
 	migrations.AnalyzeSchemaSync(db)
 	migrations.PrintSyncCommands(db)
@@ -48,11 +58,70 @@ func main() {
 	seeds.RunAllSeeds(db)
     log.Println("✅ Seeding completed")
 
+	// File storage for attachments
+	store, err := storage.NewLocalStorage(cfg.StoragePath)
+	if err != nil {
+		log.Fatal("storage init failed:", err)
+	}
+	log.Println("✅ Storage ready:", cfg.StoragePath)
+
+	// Outgoing mail: SMTP in production, .eml files in the outbox directory otherwise
+	var mail mailer.Mailer
+	switch cfg.Mail.Driver {
+	case "smtp":
+		mail = mailer.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+		log.Println("✅ Mail via SMTP:", cfg.Mail.SMTPHost)
+	case "outbox":
+		outbox, err := mailer.NewOutboxMailer(cfg.Mail.OutboxPath, cfg.Mail.From)
+		if err != nil {
+			log.Fatal("mailer init failed:", err)
+		}
+		mail = outbox
+		log.Println("✅ Mail written to outbox:", cfg.Mail.OutboxPath)
+	default:
+		log.Fatal("unknown MAIL_DRIVER: ", cfg.Mail.Driver)
+	}
+
 	// Repository and Service
 	repo := repository.NewRepository(db)
-	svc := service.NewService(repo, cfg.JWTSecret)
+	svc := service.NewService(repo, cfg.JWTSecret, store, mail)
 	log.Println("✅ Services initialized")
 
+	// Bootstrap admin: open signup is gone, everyone else joins by invitation
+	if cfg.AdminEmail != "" {
+		created, err := svc.CreateInitialAdmin(cfg.AdminEmail, cfg.AdminPassword)
+		if err != nil {
+			log.Fatal("initial admin failed:", err)
+		}
+		if created {
+			log.Println("👤 Initial admin created:", cfg.AdminEmail)
+		}
+	}
+
+	// Background jobs
+	go jobs.Every(context.Background(), "duplicate clients", time.Duration(cfg.DuplicateScanHours)*time.Hour, func() error {
+		found, err := svc.DetectDuplicateClients()
+		if err == nil {
+			log.Println("🔎 Duplicate client pairs:", found)
+		}
+		return err
+	})
+	go jobs.Every(context.Background(), "contract lifecycle", time.Duration(cfg.ContractCheckHours)*time.Hour, func() error {
+		expired, notified, err := svc.RunContractLifecycle()
+		if err == nil {
+			log.Println("📄 Contracts expired:", expired, "expiry notices:", notified)
+		}
+		return err
+	})
+
+	go jobs.Every(context.Background(), "expired sessions", 24*time.Hour, func() error {
+		deleted, err := svc.DeleteExpiredSessions()
+		if err == nil {
+			log.Println("🔑 Expired sessions deleted:", deleted)
+		}
+		return err
+	})
+
 	// Router
 	r := gin.Default()
 	api.SetupRoutes(r, svc)
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"orders/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cererea de emitere a cheii API
+type APIKeyReq struct {
+	Name        string   `json:"name" binding:"required"`
+	Permissions []string `json:"permissions" binding:"required"`
+	ChannelIDs  []uint   `json:"channel_ids"`
+	ExpiresAt   string   `json:"expires_at" binding:"required"` // YYYY-MM-DD, cheia este valabilă inclusiv în această zi
+}
+
+// respondAPIKeyError întoarce statutul HTTP pentru erorile cheilor API
+func respondAPIKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAPIKeyNameRequired), errors.Is(err, service.ErrAPIKeyPermissions),
+		errors.Is(err, service.ErrAPIKeyExpiry), errors.Is(err, service.ErrChannelNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAPIKeyRevoked):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case isNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Handler pentru emiterea cheii API (POST /api-keys). Cheia se întoarce o singură dată.
+func CreateAPIKeyHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req APIKeyReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		expires, err := time.Parse("2006-01-02", req.ExpiresAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expires_at, expected YYYY-MM-DD"})
+			return
+		}
+		key, secret, err := s.CreateAPIKey(c.GetUint("user_id"), req.Name, req.Permissions, req.ChannelIDs, expires.AddDate(0, 0, 1))
+		if err != nil {
+			respondAPIKeyError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"api_key": key, "key": secret})
+	}
+}
+
+// Handler pentru lista cheilor API cu ultima folosire (GET /api-keys)
+func ListAPIKeysHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keys, err := s.FindAPIKeys()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, keys)
+	}
+}
+
+// Handler pentru anularea cheii API (DELETE /api-keys/:id)
+func RevokeAPIKeyHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		key, err := s.RevokeAPIKey(c.GetUint("user_id"), uint(id))
+		if err != nil {
+			respondAPIKeyError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, key)
+	}
+}
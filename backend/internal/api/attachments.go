@@ -0,0 +1,235 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"orders/internal/models"
+	"orders/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cât timp este valabil un link de descărcare semnat
+const attachmentURLTTL = 15 * time.Minute
+
+// AttachmentResponse - metadatele fișierului împreună cu linkurile de descărcare semnate
+type AttachmentResponse struct {
+	models.Attachment
+	DownloadURL  string `json:"download_url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
+
+func newAttachmentResponse(s Service, a models.Attachment) AttachmentResponse {
+	res := AttachmentResponse{Attachment: a, DownloadURL: s.SignAttachmentURL(a.ID, false, attachmentURLTTL)}
+	if a.ThumbnailKey != "" {
+		res.ThumbnailURL = s.SignAttachmentURL(a.ID, true, attachmentURLTTL)
+	}
+	return res
+}
+
+// UploadAttachmentHandler gestionează POST /{products|contracts|orders}/:id/attachments (multipart, câmpul "file")
+func UploadAttachmentHandler(s Service, ownerType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ownerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		file, header, err := c.Request.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "multipart field 'file' is required"})
+			return
+		}
+		defer file.Close()
+
+		userID := c.GetUint("user_id")
+		attachment, err := s.UploadAttachment(ownerType, uint(ownerID), userID, header.Filename, file)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrFileTooLarge):
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			case errors.Is(err, service.ErrUnsupportedContentType), errors.Is(err, service.ErrEmptyFile):
+				c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+			case isNotFound(err):
+				c.JSON(http.StatusNotFound, gin.H{"error": ownerType + " not found"})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusCreated, newAttachmentResponse(s, *attachment))
+	}
+}
+
+// Permisiunea necesară pentru ștergerea fișierului, după tipul entității (la încărcare se verifică pe rută)
+var attachmentEditPermissions = map[string]string{
+	"product":  service.PermProductsEdit,
+	"contract": service.PermContractsEdit,
+	"order":    service.PermOrdersCreate,
+}
+
+// Permisiunea necesară pentru citirea fișierului după ID, după tipul entității (la listă se verifică pe rută)
+var attachmentReadPermissions = map[string]string{
+	"product":  service.PermProductsRead,
+	"contract": service.PermContractsRead,
+	"order":    service.PermOrdersRead,
+}
+
+// findReadableAttachment citește fișierul și verifică permisiunea de citire; la eroare răspunde și întoarce false
+func findReadableAttachment(c *gin.Context, s Service, id uint) (*models.Attachment, bool) {
+	attachment, err := s.FindAttachmentByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return nil, false
+	}
+	permission := attachmentReadPermissions[attachment.OwnerType]
+	if !hasPermission(c, permission) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions", "permission": permission})
+		return nil, false
+	}
+	return attachment, true
+}
+
+// forOwner leagă handler-ul fișierelor atașate de tipul entității (pentru scoped)
+func forOwner(handler func(s Service, ownerType string) gin.HandlerFunc, ownerType string) func(s Service) gin.HandlerFunc {
+	return func(s Service) gin.HandlerFunc {
+		return handler(s, ownerType)
+	}
+}
+
+// ListAttachmentsHandler gestionează GET /{products|contracts|orders}/:id/attachments
+func ListAttachmentsHandler(s Service, ownerType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ownerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		attachments, err := s.FindAttachmentsByOwner(ownerType, uint(ownerID))
+		if err != nil {
+			if isNotFound(err) {
+				c.JSON(http.StatusNotFound, gin.H{"error": ownerType + " not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		res := make([]AttachmentResponse, 0, len(attachments))
+		for _, a := range attachments {
+			res = append(res, newAttachmentResponse(s, a))
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+// GetAttachmentHandler gestionează GET /attachments/:id (metadate + linkuri semnate)
+func GetAttachmentHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		attachment, ok := findReadableAttachment(c, s, uint(id))
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, newAttachmentResponse(s, *attachment))
+	}
+}
+
+// DownloadAttachmentHandler gestionează GET /attachments/:id/download (autentificat prin token)
+func DownloadAttachmentHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		attachment, ok := findReadableAttachment(c, s, uint(id))
+		if !ok {
+			return
+		}
+		serveAttachment(c, s, attachment, c.Query("variant") == "thumbnail")
+	}
+}
+
+// SignedDownloadHandler gestionează GET /files/attachments/:id?expires=&signature=[&variant=thumbnail]
+// Ruta nu cere header-ul Authorization; accesul este dat de semnătura emisă pentru un utilizator autentificat.
+func SignedDownloadHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		thumbnail := c.Query("variant") == "thumbnail"
+		if !s.VerifyAttachmentSignature(uint(id), thumbnail, c.Query("expires"), c.Query("signature")) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "invalid or expired signature"})
+			return
+		}
+		attachment, err := s.FindAttachmentByID(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+			return
+		}
+		serveAttachment(c, s, attachment, thumbnail)
+	}
+}
+
+// DeleteAttachmentHandler gestionează DELETE /attachments/:id
+func DeleteAttachmentHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		attachment, err := s.FindAttachmentByID(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+			return
+		}
+		permission := attachmentEditPermissions[attachment.OwnerType]
+		if !hasPermission(c, permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions", "permission": permission})
+			return
+		}
+		if err := s.DeleteAttachment(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func serveAttachment(c *gin.Context, s Service, attachment *models.Attachment, thumbnail bool) {
+	reader, err := s.OpenAttachment(attachment, thumbnail)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+		return
+	}
+	defer reader.Close()
+
+	contentType := attachment.ContentType
+	if thumbnail {
+		contentType = "image/jpeg"
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": attachment.FileName}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	io.Copy(c.Writer, reader)
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"orders/internal/models"
+	"orders/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Contul bancar al clientului
+type ClientBankAccountReq struct {
+	IBAN      string `json:"iban" xml:"iban" binding:"required"`
+	BankCode  string `json:"bank_code" xml:"bank_code"` // BIC / SWIFT, ex: AGRNMD2X
+	BankName  string `json:"bank_name" xml:"bank_name"`
+	Currency  string `json:"currency" xml:"currency"` // ISO 4217, implicit MDL
+	IsDefault bool   `json:"is_default" xml:"is_default"`
+}
+
+// bankAccountErrorBody întoarce corpul răspunsului pentru erorile de validare ale contului sau nil pentru alte erori
+func bankAccountErrorBody(err error) gin.H {
+	var ibanErr *service.IBANError
+	switch {
+	case errors.As(err, &ibanErr):
+		return gin.H{"error": service.ErrInvalidIBAN.Error(), "detail": ibanErr.Detail}
+	case errors.Is(err, service.ErrInvalidBIC), errors.Is(err, service.ErrInvalidCurrency), errors.Is(err, service.ErrDuplicateIBAN):
+		return gin.H{"error": err.Error()}
+	}
+	return nil
+}
+
+// Handler pentru adăugarea conturilor bancare (POST /clients/:id/bank-accounts)
+func CreateClientBankAccountHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		if _, err := s.FindClientByID(uint(id)); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		requests, err := ParseBody[ClientBankAccountReq](c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalid: " + err.Error()})
+			return
+		}
+
+		created := make([]*models.ClientBankAccount, 0)
+		skipped := make([]map[string]string, 0)
+		for _, req := range requests {
+			account := &models.ClientBankAccount{
+				ClientID:  uint(id),
+				IBAN:      req.IBAN,
+				BankCode:  req.BankCode,
+				BankName:  req.BankName,
+				Currency:  req.Currency,
+				IsDefault: req.IsDefault,
+			}
+			if err := s.CreateClientBankAccount(account); err != nil {
+				body := bankAccountErrorBody(err)
+				if body == nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error: " + err.Error()})
+					return
+				}
+				entry := map[string]string{"iban": req.IBAN, "reason": body["error"].(string)}
+				if detail, ok := body["detail"].(string); ok {
+					entry["detail"] = detail
+				}
+				skipped = append(skipped, entry)
+				continue
+			}
+			created = append(created, account)
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"created": created, "skipped": skipped})
+	}
+}
+
+// Cererea de modificare a contului bancar
+type ClientBankAccountUpdateReq struct {
+	IBAN      *string `json:"iban"`
+	BankCode  *string `json:"bank_code"`
+	BankName  *string `json:"bank_name"`
+	Currency  *string `json:"currency"`
+	IsDefault *bool   `json:"is_default"` // true - devine contul implicit al clientului
+}
+
+// findClientBankAccount citește contul bancar din URL și verifică că aparține clientului
+func findClientBankAccount(c *gin.Context, s Service) (*models.ClientBankAccount, bool) {
+	clientID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	accountID, err := strconv.ParseUint(c.Param("account_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return nil, false
+	}
+	// Clientul trebuie să fie vizibil utilizatorului (canalele de vânzări)
+	if _, err := s.FindClientByID(uint(clientID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return nil, false
+	}
+
+	account, err := s.FindClientBankAccountByID(uint(accountID))
+	if err != nil || account.ClientID != uint(clientID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bank account not found"})
+		return nil, false
+	}
+	return account, true
+}
+
+// Handler pentru modificarea contului bancar (PATCH /clients/:id/bank-accounts/:account_id)
+func UpdateClientBankAccountHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, ok := findClientBankAccount(c, s)
+		if !ok {
+			return
+		}
+
+		var req ClientBankAccountUpdateReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalid: " + err.Error()})
+			return
+		}
+
+		if req.IBAN != nil {
+			account.IBAN = *req.IBAN
+		}
+		if req.BankCode != nil {
+			account.BankCode = *req.BankCode
+		}
+		if req.BankName != nil {
+			account.BankName = *req.BankName
+		}
+		if req.Currency != nil {
+			account.Currency = *req.Currency
+		}
+		if req.IsDefault != nil && *req.IsDefault {
+			account.IsDefault = true
+		}
+
+		if err := s.UpdateClientBankAccount(account); err != nil {
+			if body := bankAccountErrorBody(err); body != nil {
+				status := http.StatusBadRequest
+				if errors.Is(err, service.ErrDuplicateIBAN) {
+					status = http.StatusConflict
+				}
+				c.JSON(status, body)
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, account)
+	}
+}
+
+// Handler pentru ștergerea contului bancar (DELETE /clients/:id/bank-accounts/:account_id)
+func DeleteClientBankAccountHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, ok := findClientBankAccount(c, s)
+		if !ok {
+			return
+		}
+
+		if err := s.DeleteClientBankAccount(account); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// Handler pentru identificarea clienților din extrasul bancar (POST /payments/statement/match)
+// Primește rândurile extrasului și întoarce, pentru fiecare, clientul găsit după IBAN sau cod fiscal.
+func MatchStatementHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lines, err := ParseBody[models.StatementLine](c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalid: " + err.Error()})
+			return
+		}
+
+		matches, err := s.MatchStatementLines(lines)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		matched := 0
+		for _, m := range matches {
+			if m.Status == service.StatementMatched {
+				matched++
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"lines": matches, "matched": matched, "total": len(matches)})
+	}
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"orders/internal/models"
+	"orders/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Canalul de vânzări
+type ChannelReq struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// Cererea de modificare a canalului; se schimbă doar câmpurile transmise
+type ChannelUpdateReq struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// Utilizatorii adăugați în canal
+type ChannelUsersReq struct {
+	UserIDs []uint `json:"user_ids" binding:"required"`
+}
+
+// channelError întoarce statutul HTTP pentru erorile canalelor de vânzări (0 - altă eroare)
+func channelError(err error) int {
+	switch {
+	case errors.Is(err, service.ErrChannelRequired), errors.Is(err, service.ErrChannelNotFound),
+		errors.Is(err, service.ErrChannelNameRequired), errors.Is(err, service.ErrUserNotFound):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrChannelForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrDuplicateChannel), errors.Is(err, service.ErrChannelInUse):
+		return http.StatusConflict
+	case isNotFound(err):
+		return http.StatusNotFound
+	}
+	return 0
+}
+
+func respondChannelError(c *gin.Context, err error) {
+	if status := channelError(err); status != 0 {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// findChannel citește canalul din URL; la eroare răspunde și întoarce false
+func findChannel(c *gin.Context, s Service) (*models.Channel, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	channel, err := s.FindChannelByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Channel not found"})
+		return nil, false
+	}
+	return channel, true
+}
+
+// Handler pentru canalele de vânzări (GET /channels): administratorul vede toate canalele, ceilalți doar canalele lor
+func ListChannelsHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channels, err := s.FindChannels()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, channels)
+	}
+}
+
+// Handler pentru crearea canalului (POST /channels)
+func CreateChannelHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ChannelReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		channel := &models.Channel{Name: req.Name, Description: req.Description}
+		if err := s.CreateChannel(channel); err != nil {
+			respondChannelError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, channel)
+	}
+}
+
+// Handler pentru canal după ID (GET /channels/:id)
+func GetChannelHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channel, ok := findChannel(c, s)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, channel)
+	}
+}
+
+// Handler pentru modificarea canalului (PATCH /channels/:id)
+func UpdateChannelHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channel, ok := findChannel(c, s)
+		if !ok {
+			return
+		}
+		var req ChannelUpdateReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Name != nil {
+			channel.Name = *req.Name
+		}
+		if req.Description != nil {
+			channel.Description = *req.Description
+		}
+		if err := s.UpdateChannel(channel); err != nil {
+			respondChannelError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, channel)
+	}
+}
+
+// Handler pentru ștergerea canalului fără clienți, contracte și comenzi (DELETE /channels/:id)
+func DeleteChannelHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channel, ok := findChannel(c, s)
+		if !ok {
+			return
+		}
+		if err := s.DeleteChannel(channel.ID); err != nil {
+			respondChannelError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// Handler pentru utilizatorii canalului (GET /channels/:id/users)
+func GetChannelUsersHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channel, ok := findChannel(c, s)
+		if !ok {
+			return
+		}
+		users, err := s.FindChannelUsers(channel.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, users)
+	}
+}
+
+// Handler pentru adăugarea utilizatorilor în canal (POST /channels/:id/users, {"user_ids": [..]})
+func AddChannelUsersHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channel, ok := findChannel(c, s)
+		if !ok {
+			return
+		}
+		var req ChannelUsersReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.AddChannelUsers(channel.ID, req.UserIDs); err != nil {
+			respondChannelError(c, err)
+			return
+		}
+		users, err := s.FindChannelUsers(channel.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, users)
+	}
+}
+
+// Handler pentru scoaterea utilizatorului din canal (DELETE /channels/:id/users/:user_id)
+func RemoveChannelUserHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channel, ok := findChannel(c, s)
+		if !ok {
+			return
+		}
+		userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+		if err := s.RemoveChannelUser(channel.ID, uint(userID)); err != nil {
+			respondChannelError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
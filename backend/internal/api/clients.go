@@ -1,9 +1,13 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"orders/internal/models"
+	"orders/internal/service"
+	"orders/internal/validation"
 	"strconv"
 	"strings"
 
@@ -16,10 +20,10 @@ type ClientCreateReq struct {
 	ClientTypeID uint   `json:"client_type" xml:"client_type" binding:"required"`
 	Name         string `json:"name" xml:"name" binding:"required"`
 	FiscalID     string `json:"fiscal_code" xml:"fiscal_code" binding:"required"`
-	// Email is optional for now; accept empty or placeholder values until the DB holds actual emails
-	Email   string `json:"email" xml:"email" binding:"omitempty"`
-	Phone   string `json:"phone" xml:"phone"`
-	Address string `json:"address" xml:"address"`
+	Email        string `json:"email" xml:"email" binding:"omitempty"` // Opțional; valorile "n/a", "none" etc. se salvează ca null
+	Phone        string `json:"phone" xml:"phone"`
+	Address      string `json:"address" xml:"address"`
+	ChannelID    *uint  `json:"channel_id" xml:"channel_id"` // Canalul de vânzări (opțional)
 }
 
 func CreateClientHandler(s Service) gin.HandlerFunc {
@@ -37,6 +41,8 @@ func CreateClientHandler(s Service) gin.HandlerFunc {
 
 		// PASUL 2: Logica ta specifică de Business
 		for _, req := range requests {
+			req.FiscalID = validation.NormalizeFiscalID(req.FiscalID)
+
 			// A. Validare de bază
 			if req.ClientTypeID == 0 || strings.TrimSpace(req.Name) == "" || strings.TrimSpace(req.FiscalID) == "" {
 				skipped = append(skipped, map[string]string{"fiscal_id": req.FiscalID, "reason": "missing_required_fields"})
@@ -55,26 +61,28 @@ func CreateClientHandler(s Service) gin.HandlerFunc {
 				return
 			}
 
-			// C. Sanitizarea email-ului (Asta e foarte deșteaptă!)
-			email := strings.TrimSpace(req.Email)
-			lowEmail := strings.ToLower(email)
-			if lowEmail == "not inserted" || lowEmail == "n/a" || lowEmail == "none" || lowEmail == "" {
-				email = "" // O lăsăm goală dacă e placeholder
-			}
-
-			// D. Conversia de la REQ la MODEL
+			// C. Conversia de la REQ la MODEL; telefonul și emailul le normalizează serviciul
 			client := &models.Client{
 				ClientTypeID: req.ClientTypeID,
 				Name:         req.Name,
 				FiscalID:     req.FiscalID,
-				Email:        email,
+				Email:        &req.Email,
 				Phone:        req.Phone,
 				Address:      req.Address,
+				ChannelID:    req.ChannelID,
 			}
 
-			// E. Salvarea efectivă
+			// D. Salvarea efectivă
 			if err := s.CreateClient(client); err != nil {
-				skipped = append(skipped, map[string]string{"fiscal_id": req.FiscalID, "reason": err.Error()})
+				var fiscalErr *service.FiscalIDError
+				switch {
+				case errors.As(err, &fiscalErr):
+					skipped = append(skipped, map[string]string{"fiscal_id": req.FiscalID, "reason": service.ErrInvalidFiscalID.Error(), "detail": fiscalErr.Detail, "expected": fiscalErr.ExpectedKind})
+				case isNotFound(err):
+					skipped = append(skipped, map[string]string{"fiscal_id": req.FiscalID, "reason": "client_type_not_found"})
+				default:
+					skipped = append(skipped, map[string]string{"fiscal_id": req.FiscalID, "reason": err.Error()})
+				}
 				continue
 			}
 			created = append(created, client)
@@ -89,20 +97,113 @@ func CreateClientHandler(s Service) gin.HandlerFunc {
 	}
 }
 
-// Handler pentru obținerea primilor 1000 de clienți
-func GetFirst1000Clients(s Service) gin.HandlerFunc {
+// Handler pentru lista clienților (GET /clients?sort=&client_type=&channel=&has_active_contract=&limit=&cursor=)
+// Paginare după cursor: răspunsul conține "total" și "links.next" (null pe ultima pagină).
+// Clienții se scriu în răspuns pe măsură ce sunt citiți din baza de date.
+func ListClientsHandler(s Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clients, err := s.GetFirst1000Clients()
+		filter := models.ClientListFilter{Sort: strings.TrimPrefix(c.DefaultQuery("sort", "name"), "-")}
+		filter.Desc = strings.HasPrefix(c.Query("sort"), "-")
+		if filter.Sort != "name" && filter.Sort != "created_at" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be one of: name, -name, created_at, -created_at"})
+			return
+		}
+
+		var ok bool
+		if filter.ClientTypeID, ok = queryUint(c, "client_type"); !ok {
+			return
+		}
+		if filter.ChannelID, ok = queryUint(c, "channel"); !ok {
+			return
+		}
+		if v := c.Query("has_active_contract"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid has_active_contract"})
+				return
+			}
+			filter.HasActiveContract = &b
+		}
+		if v := c.Query("limit"); v != "" {
+			limit, err := strconv.Atoi(v)
+			if err != nil || limit < 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+				return
+			}
+			filter.Limit = limit
+		}
+
+		total, err := s.CountClients(filter)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, clients)
+		// Începutul răspunsului se scrie la primul client, ca erorile de dinainte să poată fi întoarse ca JSON
+		encoder := json.NewEncoder(c.Writer)
+		written := 0
+		begin := func() {
+			c.Header("Content-Type", "application/json; charset=utf-8")
+			c.Status(http.StatusOK)
+			fmt.Fprintf(c.Writer, `{"total":%d,"data":[`, total)
+		}
+
+		next, err := s.ListClients(filter, c.Query("cursor"), func(client *models.Client) error {
+			if written == 0 {
+				begin()
+			} else if _, err := c.Writer.WriteString(","); err != nil {
+				return err
+			}
+			written++
+			return encoder.Encode(client)
+		})
+		if err != nil {
+			if written > 0 {
+				// Antetul a fost deja trimis: răspunsul rămâne incomplet, eroarea ajunge în log
+				_ = c.Error(err)
+				return
+			}
+			if errors.Is(err, service.ErrInvalidCursor) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if written == 0 {
+			begin()
+		}
+
+		var nextLink interface{}
+		if next != "" {
+			u := *c.Request.URL
+			q := u.Query()
+			q.Set("cursor", next)
+			u.RawQuery = q.Encode()
+			nextLink = u.RequestURI()
+		}
+		tail, _ := json.Marshal(gin.H{"count": written, "next_cursor": next, "links": gin.H{"next": nextLink}})
+		// tail începe cu "{": îl lipim de obiectul deja deschis
+		c.Writer.WriteString("]," + string(tail[1:]))
+	}
+}
+
+// queryUint citește un parametru numeric opțional din query (0 dacă lipsește)
+func queryUint(c *gin.Context, name string) (uint, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return 0, true
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return 0, false
 	}
+	return uint(n), true
 }
 
-// Handler pentru căutarea clienților după query
+// Handler pentru căutarea clienților (GET /clients/search?q=&page=&page_size=)
+// Caută după nume, cod fiscal, telefon, adresă și persoanele de contact, tolerând greșelile de tipar.
 func SearchClientsHandler(s Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		query := c.Query("q")
@@ -110,14 +211,32 @@ func SearchClientsHandler(s Service) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter 'q' is required"})
 			return
 		}
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if page < 1 {
+			page = 1
+		}
+		pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+		if pageSize < 1 || pageSize > 100 {
+			pageSize = 20
+		}
 
-		clients, err := s.FindClientsByQuery(query)
+		hits, total, err := s.SearchClients(query, page, pageSize)
 		if err != nil {
+			if errors.Is(err, service.ErrSearchQueryTooShort) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, clients)
+		c.JSON(http.StatusOK, gin.H{
+			"query":     query,
+			"page":      page,
+			"page_size": pageSize,
+			"total":     total,
+			"results":   hits,
+		})
 	}
 }
 
@@ -160,3 +279,301 @@ func GetClientByIDHandler(s Service) gin.HandlerFunc {
 		c.JSON(http.StatusOK, client)
 	}
 }
+
+// Cererea de modificare a clientului: se actualizează doar câmpurile transmise
+type ClientUpdateReq struct {
+	ClientTypeID *uint   `json:"client_type"`
+	Name         *string `json:"name"`
+	FiscalID     *string `json:"fiscal_code"`
+	Email        *string `json:"email"`
+	Phone        *string `json:"phone"`
+	Address      *string `json:"address"`
+	ChannelID    *uint   `json:"channel_id"` // 0 = fără canal
+}
+
+// Handler pentru modificarea clientului (PATCH /clients/:id)
+func UpdateClientHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		var req ClientUpdateReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalid: " + err.Error()})
+			return
+		}
+
+		client, err := s.FindClientByID(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		if req.ClientTypeID != nil {
+			client.ClientTypeID = *req.ClientTypeID
+			client.ClientType = models.ClientType{}
+		}
+		if req.Name != nil {
+			if strings.TrimSpace(*req.Name) == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "missing_required_fields"})
+				return
+			}
+			client.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.FiscalID != nil {
+			fiscalID := validation.NormalizeFiscalID(*req.FiscalID)
+			if fiscalID == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "missing_required_fields"})
+				return
+			}
+			// Codul fiscal rămâne unic
+			existing, err := s.FindClientByFiscalID(fiscalID)
+			if err == nil && existing.ID != client.ID {
+				c.JSON(http.StatusConflict, gin.H{"error": "duplicate"})
+				return
+			}
+			if err != nil && !isNotFound(err) {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error: " + err.Error()})
+				return
+			}
+			client.FiscalID = fiscalID
+		}
+		if req.Email != nil {
+			client.Email = req.Email
+		}
+		if req.Phone != nil {
+			client.Phone = *req.Phone
+		}
+		if req.Address != nil {
+			client.Address = *req.Address
+		}
+		if req.ChannelID != nil {
+			client.ChannelID = req.ChannelID
+			if *req.ChannelID == 0 {
+				client.ChannelID = nil
+			}
+			client.Channel = nil
+		}
+
+		if err := s.UpdateClient(client); err != nil {
+			var fiscalErr *service.FiscalIDError
+			if errors.As(err, &fiscalErr) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": service.ErrInvalidFiscalID.Error(), "detail": fiscalErr.Detail, "expected": fiscalErr.ExpectedKind})
+				return
+			}
+			if isNotFound(err) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "client_type_not_found"})
+				return
+			}
+			if errors.Is(err, service.ErrInvalidPhone) || errors.Is(err, service.ErrInvalidEmail) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, service.ErrDuplicateEmail) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			if status := channelError(err); status != 0 {
+				c.JSON(status, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Recitim clientul pentru a întoarce tipul și contactele actualizate
+		client, err = s.FindClientByID(client.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, client)
+	}
+}
+
+// Handler pentru ștergerea (soft) clientului (DELETE /clients/:id)
+func DeleteClientHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		if err := s.DeleteClient(uint(id)); err != nil {
+			switch {
+			case isNotFound(err):
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case errors.Is(err, service.ErrClientHasActiveContracts), errors.Is(err, service.ErrClientHasOpenOrders):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// Persoana de contact a clientului
+type ClientContactReq struct {
+	Name             string `json:"name" xml:"name" binding:"required"`
+	Position         string `json:"position" xml:"position"`
+	Phone            string `json:"phone" xml:"phone"`
+	Email            string `json:"email" xml:"email"`
+	PreferredChannel string `json:"preferred_channel" xml:"preferred_channel"` // phone, email, sms, viber, telegram, whatsapp
+}
+
+// Handler pentru adăugarea persoanelor de contact (POST /clients/:id/contacts)
+func CreateClientContactHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		if _, err := s.FindClientByID(uint(id)); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		requests, err := ParseBody[ClientContactReq](c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalid: " + err.Error()})
+			return
+		}
+
+		created := make([]*models.ClientContact, 0)
+		skipped := make([]map[string]string, 0)
+		for _, req := range requests {
+			if strings.TrimSpace(req.Name) == "" {
+				skipped = append(skipped, map[string]string{"name": req.Name, "reason": "missing_required_fields"})
+				continue
+			}
+
+			contact := &models.ClientContact{
+				ClientID:         uint(id),
+				Name:             strings.TrimSpace(req.Name),
+				Position:         req.Position,
+				Phone:            req.Phone,
+				Email:            strings.TrimSpace(req.Email),
+				PreferredChannel: req.PreferredChannel,
+			}
+			if err := s.CreateClientContact(contact); err != nil {
+				if !errors.Is(err, service.ErrInvalidContactChannel) && !errors.Is(err, service.ErrInvalidPhone) && !errors.Is(err, service.ErrInvalidEmail) {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error: " + err.Error()})
+					return
+				}
+				skipped = append(skipped, map[string]string{"name": req.Name, "reason": err.Error()})
+				continue
+			}
+			created = append(created, contact)
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"created": created, "skipped": skipped})
+	}
+}
+
+// Cererea de modificare a persoanei de contact
+type ClientContactUpdateReq struct {
+	Name             *string `json:"name"`
+	Position         *string `json:"position"`
+	Phone            *string `json:"phone"`
+	Email            *string `json:"email"`
+	PreferredChannel *string `json:"preferred_channel"`
+}
+
+// findClientContact citește persoana de contact din URL și verifică că aparține clientului
+func findClientContact(c *gin.Context, s Service) (*models.ClientContact, bool) {
+	clientID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	contactID, err := strconv.ParseUint(c.Param("contact_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid contact id"})
+		return nil, false
+	}
+	// Clientul trebuie să fie vizibil utilizatorului (canalele de vânzări)
+	if _, err := s.FindClientByID(uint(clientID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return nil, false
+	}
+
+	contact, err := s.FindClientContactByID(uint(contactID))
+	if err != nil || contact.ClientID != uint(clientID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "contact not found"})
+		return nil, false
+	}
+	return contact, true
+}
+
+// Handler pentru modificarea persoanei de contact (PATCH /clients/:id/contacts/:contact_id)
+func UpdateClientContactHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		contact, ok := findClientContact(c, s)
+		if !ok {
+			return
+		}
+
+		var req ClientContactUpdateReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalid: " + err.Error()})
+			return
+		}
+
+		if req.Name != nil {
+			if strings.TrimSpace(*req.Name) == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "missing_required_fields"})
+				return
+			}
+			contact.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.Position != nil {
+			contact.Position = *req.Position
+		}
+		if req.Phone != nil {
+			contact.Phone = *req.Phone
+		}
+		if req.Email != nil {
+			contact.Email = strings.TrimSpace(*req.Email)
+		}
+		if req.PreferredChannel != nil {
+			contact.PreferredChannel = *req.PreferredChannel
+		}
+
+		if err := s.UpdateClientContact(contact); err != nil {
+			if errors.Is(err, service.ErrInvalidContactChannel) || errors.Is(err, service.ErrInvalidPhone) || errors.Is(err, service.ErrInvalidEmail) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, contact)
+	}
+}
+
+// Handler pentru ștergerea persoanei de contact (DELETE /clients/:id/contacts/:contact_id)
+func DeleteClientContactHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		contact, ok := findClientContact(c, s)
+		if !ok {
+			return
+		}
+
+		if err := s.DeleteClientContact(contact.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,292 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"orders/internal/models"
+	"orders/internal/service"
+	"orders/internal/spreadsheet"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Adresa contractului: localitatea din clasificatorul CUATM, strada, numărul și detaliile de livrare.
+// Fără localitate (doar la adresele billing / legal) se acceptă adresa în text liber.
+type AddressReq struct {
+	ContractID    uint     `json:"contract_id" xml:"contract_id" binding:"required"`
+	Type          string   `json:"type" xml:"type"`                   // billing, shipping (implicit), legal
+	LocalityCode  string   `json:"locality_code" xml:"locality_code"` // Codul CUATM (obligatoriu la shipping)
+	Street        string   `json:"street" xml:"street"`
+	Building      string   `json:"building" xml:"building"`
+	PostalCode    string   `json:"postal_code" xml:"postal_code"` // MD-2001
+	Latitude      *float64 `json:"latitude" xml:"latitude"`
+	Longitude     *float64 `json:"longitude" xml:"longitude"`
+	ContactName   string   `json:"contact_name" xml:"contact_name"`
+	ContactPhone  string   `json:"contact_phone" xml:"contact_phone"`
+	OpeningHours  string   `json:"opening_hours" xml:"opening_hours"`
+	DeliveryNotes string   `json:"delivery_notes" xml:"delivery_notes"`
+	Address       string   `json:"address" xml:"address"` // Text liber, doar pentru adresele fără localitate
+}
+
+// Cererea de modificare a adresei; se schimbă doar câmpurile transmise
+type AddressUpdateReq struct {
+	Type          *string  `json:"type"`
+	LocalityCode  *string  `json:"locality_code"` // "" - fără localitate
+	Street        *string  `json:"street"`
+	Building      *string  `json:"building"`
+	PostalCode    *string  `json:"postal_code"`
+	Latitude      *float64 `json:"latitude"`
+	Longitude     *float64 `json:"longitude"`
+	ClearGPS      bool     `json:"clear_gps"` // Șterge coordonatele GPS
+	ContactName   *string  `json:"contact_name"`
+	ContactPhone  *string  `json:"contact_phone"`
+	OpeningHours  *string  `json:"opening_hours"`
+	DeliveryNotes *string  `json:"delivery_notes"`
+	Address       *string  `json:"address"`
+}
+
+// contractAddressError întoarce statutul HTTP pentru erorile de validare ale adresei (0 - altă eroare)
+func contractAddressError(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidAddressType), errors.Is(err, service.ErrLocalityNotFound),
+		errors.Is(err, service.ErrLocalityRequired), errors.Is(err, service.ErrAddressRequired),
+		errors.Is(err, service.ErrInvalidPostalCode), errors.Is(err, service.ErrInvalidCoordinates),
+		errors.Is(err, service.ErrInvalidPhone):
+		return http.StatusBadRequest
+	case isNotFound(err):
+		return http.StatusNotFound
+	}
+	return 0
+}
+
+// Handler pentru adăugarea adreselor contractelor (POST /contract_addresses)
+func CreateContractAddressHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requests, err := ParseBody[AddressReq](c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format"})
+			return
+		}
+
+		created := make([]*models.ContractAddress, 0)
+		skipped := make([]map[string]interface{}, 0)
+		for _, req := range requests {
+			addr := &models.ContractAddress{
+				ContractID:    req.ContractID,
+				Type:          req.Type,
+				Street:        req.Street,
+				Building:      req.Building,
+				PostalCode:    req.PostalCode,
+				Latitude:      req.Latitude,
+				Longitude:     req.Longitude,
+				ContactName:   req.ContactName,
+				ContactPhone:  req.ContactPhone,
+				OpeningHours:  req.OpeningHours,
+				DeliveryNotes: req.DeliveryNotes,
+				Address:       req.Address,
+				OwnerID:       c.GetUint("user_id"),
+			}
+			if req.LocalityCode != "" {
+				addr.LocalityCode = &req.LocalityCode
+			}
+
+			if err := s.CreateContractAddress(addr); err != nil {
+				reason := err.Error()
+				if isNotFound(err) {
+					reason = "contract_not_found"
+				} else if contractAddressError(err) == 0 {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error: " + err.Error()})
+					return
+				}
+				skipped = append(skipped, map[string]interface{}{"contract_id": req.ContractID, "reason": reason})
+				continue
+			}
+			created = append(created, addr)
+		}
+		c.JSON(http.StatusCreated, gin.H{"created": created, "skipped": skipped})
+	}
+}
+
+func GetContractAddressByIDHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, _ := strconv.Atoi(c.Param("id"))
+		res, err := s.FindContractAddressByID(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Address not found"})
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+// Handler pentru adresele contractului (GET /contracts/:id/addresses)
+func GetContractAddressesHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		contract, ok := findContract(c, s)
+		if !ok {
+			return
+		}
+		addresses, err := s.FindContractAddresses(contract.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, addresses)
+	}
+}
+
+// findContractAddress citește adresa din URL și verifică că aparține contractului; la eroare răspunde și întoarce false
+func findContractAddress(c *gin.Context, s Service) (*models.ContractAddress, bool) {
+	contract, ok := findContract(c, s)
+	if !ok {
+		return nil, false
+	}
+	addressID, err := strconv.ParseUint(c.Param("address_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid address id"})
+		return nil, false
+	}
+	addr, err := s.FindContractAddressByID(uint(addressID))
+	if err != nil || addr.ContractID != contract.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Address not found"})
+		return nil, false
+	}
+	return addr, true
+}
+
+// Handler pentru modificarea adresei contractului (PATCH /contracts/:id/addresses/:address_id)
+func UpdateContractAddressHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		addr, ok := findContractAddress(c, s)
+		if !ok {
+			return
+		}
+		var req AddressUpdateReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if req.Type != nil {
+			addr.Type = *req.Type
+		}
+		if req.Street != nil {
+			addr.Street = *req.Street
+		}
+		if req.Building != nil {
+			addr.Building = *req.Building
+		}
+		if req.PostalCode != nil {
+			addr.PostalCode = *req.PostalCode
+		}
+		if req.ContactName != nil {
+			addr.ContactName = *req.ContactName
+		}
+		if req.ContactPhone != nil {
+			addr.ContactPhone = *req.ContactPhone
+		}
+		if req.OpeningHours != nil {
+			addr.OpeningHours = *req.OpeningHours
+		}
+		if req.DeliveryNotes != nil {
+			addr.DeliveryNotes = *req.DeliveryNotes
+		}
+		if req.Address != nil {
+			addr.Address = *req.Address
+		}
+		if req.LocalityCode != nil {
+			addr.LocalityCode = req.LocalityCode
+		}
+		if req.ClearGPS {
+			addr.Latitude, addr.Longitude = nil, nil
+		}
+		if req.Latitude != nil {
+			addr.Latitude = req.Latitude
+		}
+		if req.Longitude != nil {
+			addr.Longitude = req.Longitude
+		}
+
+		if err := s.UpdateContractAddress(addr); err != nil {
+			if status := contractAddressError(err); status != 0 {
+				c.JSON(status, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, addr)
+	}
+}
+
+// Handler pentru ștergerea adresei contractului (DELETE /contracts/:id/addresses/:address_id)
+func DeleteContractAddressHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		addr, ok := findContractAddress(c, s)
+		if !ok {
+			return
+		}
+		if err := s.DeleteContractAddress(addr.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// Handler pentru importul clasificatorului localităților CUATM (POST /localities/import, multipart, câmpul "file")
+// Fișierul CSV sau XLSX are coloanele "Cod", "Denumirea" și, opțional, "Statutul".
+func ImportLocalitiesHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		file, header, err := c.Request.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "multipart field 'file' is required"})
+			return
+		}
+		defer file.Close()
+
+		result, err := s.ImportLocalities(header.Filename, file)
+		if err != nil {
+			var missing *service.MissingColumnsError
+			switch {
+			case errors.As(err, &missing):
+				c.JSON(http.StatusBadRequest, gin.H{"error": "missing_columns", "columns": missing.Columns})
+			case errors.Is(err, service.ErrFileTooLarge):
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			case errors.Is(err, service.ErrEmptyFile), errors.Is(err, spreadsheet.ErrUnknownFormat):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// Handler pentru căutarea localităților (GET /localities?q=&limit=20)
+func SearchLocalitiesHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.Query("limit"))
+		localities, err := s.SearchLocalities(strings.TrimSpace(c.Query("q")), limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, localities)
+	}
+}
+
+// Handler pentru localitate după codul CUATM (GET /localities/:code)
+func GetLocalityHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locality, err := s.FindLocalityByCode(c.Param("code"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Locality not found"})
+			return
+		}
+		c.JSON(http.StatusOK, locality)
+	}
+}
@@ -0,0 +1,271 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"orders/internal/models"
+	"orders/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Acordul de preț: prețul fix (pentru unitatea de bază, în moneda contractului) sau reducerea, %
+type ContractPriceReq struct {
+	ProductID uint     `json:"product_id" xml:"product_id" binding:"required"`
+	Price     *float64 `json:"price" xml:"price"`
+	Discount  *float64 `json:"discount" xml:"discount"`
+	ValidFrom string   `json:"valid_from" xml:"valid_from"` // YYYY-MM-DD, implicit începutul contractului
+	ValidTo   string   `json:"valid_to" xml:"valid_to"`     // YYYY-MM-DD, implicit până la sfârșitul contractului
+}
+
+// contractPriceError întoarce statutul HTTP pentru erorile de validare ale acordului de preț (0 - altă eroare)
+func contractPriceError(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidContractPrice), errors.Is(err, service.ErrInvalidContractPeriod),
+		errors.Is(err, service.ErrPriceOutsideContract):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrContractPriceOverlap), errors.Is(err, service.ErrContractClosed):
+		return http.StatusConflict
+	case isNotFound(err):
+		return http.StatusNotFound
+	}
+	return 0
+}
+
+// parsePriceValidity citește perioada acordului de preț (YYYY-MM-DD, ambele opționale)
+func parsePriceValidity(from, to string) (time.Time, *time.Time, error) {
+	var validFrom time.Time
+	var err error
+	if from != "" {
+		if validFrom, err = time.Parse("2006-01-02", from); err != nil {
+			return time.Time{}, nil, errInvalidDate("valid_from")
+		}
+	}
+	if to == "" {
+		return validFrom, nil, nil
+	}
+	validTo, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return time.Time{}, nil, errInvalidDate("valid_to")
+	}
+	return validFrom, &validTo, nil
+}
+
+// findContract citește contractul din URL; la eroare răspunde și întoarce false
+func findContract(c *gin.Context, s Service) (*models.Contract, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	contract, err := s.FindContractByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+		return nil, false
+	}
+	return contract, true
+}
+
+// Handler pentru adăugarea acordurilor de preț (POST /contracts/:id/prices)
+func CreateContractPricesHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		contract, ok := findContract(c, s)
+		if !ok {
+			return
+		}
+
+		requests, err := ParseBody[ContractPriceReq](c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalid: " + err.Error()})
+			return
+		}
+
+		created := make([]*models.ContractPrice, 0)
+		skipped := make([]map[string]interface{}, 0)
+		for _, req := range requests {
+			price := &models.ContractPrice{
+				ProductID: req.ProductID,
+				Price:     req.Price,
+				Discount:  req.Discount,
+				OwnerID:   c.GetUint("user_id"),
+			}
+			validFrom, validTo, err := parsePriceValidity(req.ValidFrom, req.ValidTo)
+			if err != nil {
+				skipped = append(skipped, map[string]interface{}{"product_id": req.ProductID, "reason": "invalid_date", "detail": err.Error()})
+				continue
+			}
+			price.ValidFrom, price.ValidTo = validFrom, validTo
+
+			if err := s.CreateContractPrice(contract, price); err != nil {
+				reason := err.Error()
+				if isNotFound(err) {
+					reason = "product_not_found"
+				} else if contractPriceError(err) == 0 {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error: " + err.Error()})
+					return
+				}
+				skipped = append(skipped, map[string]interface{}{"product_id": req.ProductID, "reason": reason})
+				continue
+			}
+			created = append(created, price)
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"created": created, "skipped": skipped})
+	}
+}
+
+// Handler pentru acordurile de preț ale contractului (GET /contracts/:id/prices?date=YYYY-MM-DD)
+// Cu date - doar acordurile valabile la acea dată.
+func GetContractPricesHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		contract, ok := findContract(c, s)
+		if !ok {
+			return
+		}
+		var date time.Time
+		if !parseOptionalDate(c, "date", c.Query("date"), &date) {
+			return
+		}
+		var at *time.Time
+		if !date.IsZero() {
+			at = &date
+		}
+
+		prices, err := s.FindContractPrices(contract.ID, at)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, prices)
+	}
+}
+
+// Cererea de modificare a acordului de preț; price și discount se exclud reciproc
+type ContractPriceUpdateReq struct {
+	Price     *float64 `json:"price"`      // Trece acordul la preț fix
+	Discount  *float64 `json:"discount"`   // Trece acordul la reducere, %
+	ValidFrom *string  `json:"valid_from"` // YYYY-MM-DD
+	ValidTo   *string  `json:"valid_to"`   // YYYY-MM-DD, "" - până la sfârșitul contractului
+}
+
+// findContractPrice citește contractul și acordul de preț din URL; la eroare răspunde și întoarce false
+func findContractPrice(c *gin.Context, s Service) (*models.Contract, *models.ContractPrice, bool) {
+	contract, ok := findContract(c, s)
+	if !ok {
+		return nil, nil, false
+	}
+	priceID, err := strconv.ParseUint(c.Param("price_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid price id"})
+		return nil, nil, false
+	}
+	price, err := s.FindContractPrice(contract.ID, uint(priceID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "price agreement not found"})
+		return nil, nil, false
+	}
+	return contract, price, true
+}
+
+// Handler pentru modificarea acordului de preț (PATCH /contracts/:id/prices/:price_id)
+func UpdateContractPriceHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		contract, price, ok := findContractPrice(c, s)
+		if !ok {
+			return
+		}
+
+		var req ContractPriceUpdateReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalid: " + err.Error()})
+			return
+		}
+
+		if req.Price != nil && req.Discount != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": service.ErrInvalidContractPrice.Error()})
+			return
+		}
+		if req.Price != nil {
+			price.Price, price.Discount = req.Price, nil
+		}
+		if req.Discount != nil {
+			price.Price, price.Discount = nil, req.Discount
+		}
+		if req.ValidFrom != nil && !parseOptionalDate(c, "valid_from", *req.ValidFrom, &price.ValidFrom) {
+			return
+		}
+		if req.ValidTo != nil {
+			var validTo time.Time
+			if !parseOptionalDate(c, "valid_to", *req.ValidTo, &validTo) {
+				return
+			}
+			price.ValidTo = nil
+			if !validTo.IsZero() {
+				price.ValidTo = &validTo
+			}
+		}
+
+		if err := s.UpdateContractPrice(contract, price); err != nil {
+			if status := contractPriceError(err); status != 0 {
+				c.JSON(status, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, price)
+	}
+}
+
+// Handler pentru ștergerea acordului de preț (DELETE /contracts/:id/prices/:price_id)
+// Comenzile deja plasate își păstrează prețurile.
+func DeleteContractPriceHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, price, ok := findContractPrice(c, s)
+		if !ok {
+			return
+		}
+		if err := s.DeleteContractPrice(price.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// Handler pentru raportul prețurilor convenite față de prețurile de listă
+// (GET /reports/contract-prices?contract_id=&client_id=&price_type_id=&date=YYYY-MM-DD, implicit azi)
+func ContractPriceReportHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		contractID, ok := queryUint(c, "contract_id")
+		if !ok {
+			return
+		}
+		clientID, ok := queryUint(c, "client_id")
+		if !ok {
+			return
+		}
+		priceTypeID, ok := queryUint(c, "price_type_id")
+		if !ok {
+			return
+		}
+		now := time.Now()
+		date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		if !parseOptionalDate(c, "date", c.Query("date"), &date) {
+			return
+		}
+
+		rows, err := s.ContractPriceReport(contractID, clientID, priceTypeID, date)
+		if err != nil {
+			if errors.Is(err, service.ErrExchangeRateNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"date": date.Format("2006-01-02"), "rows": rows})
+	}
+}
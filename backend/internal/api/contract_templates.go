@@ -0,0 +1,244 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"orders/internal/documents"
+	"orders/internal/models"
+	"orders/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cererea de creare a șablonului de contract; textul folosește câmpuri {{nume}} și titluri "# " / "## "
+type ContractTemplateReq struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Body        string `json:"body" binding:"required"`
+}
+
+// Cererea de modificare a șablonului; un text nou creează o versiune nouă
+type ContractTemplateUpdateReq struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Body        *string `json:"body"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// Cererea de generare a documentului contractului
+type ContractDocumentReq struct {
+	TemplateID      uint   `json:"template_id" binding:"required"`
+	TemplateVersion int    `json:"template_version"` // 0 - versiunea curentă a șablonului
+	Format          string `json:"format"`           // "pdf" (implicit) sau "docx"
+}
+
+// ContractDocumentResponse - documentul generat împreună cu linkurile de descărcare ale fișierului
+type ContractDocumentResponse struct {
+	models.ContractDocument
+	Attachment AttachmentResponse `json:"Attachment"`
+}
+
+// contractTemplateErrorBody întoarce statutul și răspunsul pentru erorile de validare ale șablonului (0 - altă eroare)
+func contractTemplateErrorBody(err error) (int, gin.H) {
+	var placeholders *service.PlaceholderError
+	var characters *documents.CharactersError
+	switch {
+	case errors.As(err, &placeholders):
+		return http.StatusBadRequest, gin.H{"error": service.ErrUnknownPlaceholders.Error(), "unknown": placeholders.Unknown}
+	case errors.As(err, &characters):
+		return http.StatusUnprocessableEntity, gin.H{"error": documents.ErrUnsupportedCharacters.Error(), "characters": characters.Characters,
+			"hint": "the PDF fonts cover Latin and Romanian letters only, generate the document as docx"}
+	case errors.Is(err, service.ErrEmptyTemplate), errors.Is(err, service.ErrInvalidDocumentFormat):
+		return http.StatusBadRequest, gin.H{"error": err.Error()}
+	case errors.Is(err, service.ErrTemplateInactive):
+		return http.StatusConflict, gin.H{"error": err.Error()}
+	case isNotFound(err):
+		return http.StatusNotFound, gin.H{"error": "not found"}
+	}
+	return 0, nil
+}
+
+// Handler pentru câmpurile disponibile în șabloane (GET /contract-templates/placeholders)
+func GetContractPlaceholdersHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, documents.ContractPlaceholders)
+	}
+}
+
+// Handler pentru crearea șablonului de contract (POST /contract-templates)
+func CreateContractTemplateHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ContractTemplateReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		template := &models.ContractTemplate{Name: req.Name, Description: req.Description, Body: req.Body}
+		if err := s.CreateContractTemplate(c.GetUint("user_id"), template); err != nil {
+			if status, body := contractTemplateErrorBody(err); status != 0 {
+				c.JSON(status, body)
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, template)
+	}
+}
+
+// Handler pentru lista șabloanelor (GET /contract-templates?active=true)
+func ListContractTemplatesHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		templates, err := s.FindContractTemplates(c.Query("active") == "true")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, templates)
+	}
+}
+
+// findContractTemplate citește șablonul din URL; la eroare răspunde și întoarce false
+func findContractTemplate(c *gin.Context, s Service) (*models.ContractTemplate, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	template, err := s.FindContractTemplateByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return nil, false
+	}
+	return template, true
+}
+
+// Handler pentru șablon, cu toate versiunile lui (GET /contract-templates/:id)
+func GetContractTemplateHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		template, ok := findContractTemplate(c, s)
+		if !ok {
+			return
+		}
+		versions, err := s.FindContractTemplateVersions(template.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		template.Versions = versions
+		c.JSON(http.StatusOK, template)
+	}
+}
+
+// Handler pentru modificarea șablonului (PATCH /contract-templates/:id)
+func UpdateContractTemplateHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		template, ok := findContractTemplate(c, s)
+		if !ok {
+			return
+		}
+		var req ContractTemplateUpdateReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if req.Name != nil {
+			template.Name = *req.Name
+		}
+		if req.Description != nil {
+			template.Description = *req.Description
+		}
+		if req.Body != nil {
+			template.Body = *req.Body
+		}
+		if req.IsActive != nil {
+			template.IsActive = *req.IsActive
+		}
+
+		if err := s.UpdateContractTemplate(c.GetUint("user_id"), template); err != nil {
+			if status, body := contractTemplateErrorBody(err); status != 0 {
+				c.JSON(status, body)
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, template)
+	}
+}
+
+// Handler pentru o versiune a șablonului (GET /contract-templates/:id/versions/:version)
+func GetContractTemplateVersionHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		version, err := strconv.Atoi(c.Param("version"))
+		if err != nil || version < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+			return
+		}
+		v, err := s.FindContractTemplateVersion(uint(id), version)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "version not found"})
+			return
+		}
+		c.JSON(http.StatusOK, v)
+	}
+}
+
+// Handler pentru generarea documentului contractului din șablon (POST /contracts/:id/documents)
+// Fișierul se atașează contractului; se păstrează versiunile șablonului și contractului folosite.
+func CreateContractDocumentHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		contract, ok := findContract(c, s)
+		if !ok {
+			return
+		}
+		var req ContractDocumentReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.TemplateVersion < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template_version"})
+			return
+		}
+
+		document, err := s.RenderContractDocument(c.GetUint("user_id"), contract.ID, req.TemplateID, req.TemplateVersion, req.Format)
+		if err != nil {
+			if status, body := contractTemplateErrorBody(err); status != 0 {
+				c.JSON(status, body)
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, ContractDocumentResponse{ContractDocument: *document, Attachment: newAttachmentResponse(s, document.Attachment)})
+	}
+}
+
+// Handler pentru documentele generate ale contractului (GET /contracts/:id/documents)
+func GetContractDocumentsHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		contract, ok := findContract(c, s)
+		if !ok {
+			return
+		}
+		list, err := s.FindContractDocuments(contract.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		res := make([]ContractDocumentResponse, 0, len(list))
+		for _, document := range list {
+			res = append(res, ContractDocumentResponse{ContractDocument: document, Attachment: newAttachmentResponse(s, document.Attachment)})
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
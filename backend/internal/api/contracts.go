@@ -1,9 +1,12 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"orders/internal/models"
+	"orders/internal/service"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,18 +14,19 @@ import (
 // --- DTOs (Data Transfer Objects) ---
 
 type ContractReq struct {
-	Number   string  `json:"number" xml:"number" binding:"required"`
-	Name     string  `json:"name" xml:"name" binding:"required"`
-	Date     string  `json:"date" xml:"date" binding:"required"` // Format YYYY-MM-DD
-	Amount   float64 `json:"amount" xml:"amount"`
-	ClientID uint    `json:"client_id" xml:"client_id" binding:"required"`
-	Status   string  `json:"status" xml:"status"`
-}
-
-type AddressReq struct {
-	ContractID uint   `json:"contract_id" xml:"contract_id" binding:"required"`
-	Address    string `json:"address" xml:"address" binding:"required"`
-	Type       string `json:"type" xml:"type"` // billing, shipping
+	Number string `json:"number" xml:"number" binding:"required"`
+	Name   string `json:"name" xml:"name" binding:"required"`
+	Date   string `json:"date" xml:"date" binding:"required"` // Data semnării, format YYYY-MM-DD
+	// Perioada de valabilitate (YYYY-MM-DD): începutul implicit este data semnării, fără sfârșit - nelimitat
+	StartDate string  `json:"start_date" xml:"start_date"`
+	EndDate   string  `json:"end_date" xml:"end_date"`
+	Amount    float64 `json:"amount" xml:"amount"`
+	ClientID  uint    `json:"client_id" xml:"client_id" binding:"required"`
+	Status    string  `json:"status" xml:"status"`     // draft (implicit) sau active
+	Currency  string  `json:"currency" xml:"currency"` // ISO 4217, implicit MDL
+	Terms     string  `json:"terms" xml:"terms"`       // Condițiile contractului
+	// Impozitul pe venit reținut la plățile pe acest contract (altfel din tipul clientului)
+	IncomeTaxID *uint `json:"income_tax_id" xml:"income_tax_id"`
 }
 
 // --- HANDLERS ---
@@ -45,14 +49,24 @@ func CreateContractHandler(s Service) gin.HandlerFunc {
 		errors := make([]map[string]string, 0)
 
 		for _, req := range requests {
+			date, startDate, endDate, err := parseContractDates(req.Date, req.StartDate, req.EndDate)
+			if err != nil {
+				errors = append(errors, map[string]string{"number": req.Number, "error": err.Error()})
+				continue
+			}
 			contract := &models.Contract{
-				Number:   req.Number,
-				Name:     req.Name,
-				Date:     req.Date,
-				Amount:   req.Amount,
-				ClientID: req.ClientID,
-				Status:   req.Status,
-				OwnerID:  ownerID, // Foarte important pentru baza de date!
+				Number:      req.Number,
+				Name:        req.Name,
+				Date:        date,
+				StartDate:   startDate,
+				EndDate:     endDate,
+				Amount:      req.Amount,
+				ClientID:    req.ClientID,
+				Status:      req.Status,
+				Currency:    req.Currency,
+				Terms:       req.Terms,
+				OwnerID:     ownerID, // Foarte important pentru baza de date!
+				IncomeTaxID: req.IncomeTaxID,
 			}
 
 			if err := s.CreateContract(contract); err != nil {
@@ -66,51 +80,411 @@ func CreateContractHandler(s Service) gin.HandlerFunc {
 	}
 }
 
-func CreateContractAddressHandler(s Service) gin.HandlerFunc {
+// parseContractDates citește data semnării și perioada contractului (YYYY-MM-DD; start și end sunt opționale)
+func parseContractDates(date, start, end string) (time.Time, time.Time, *time.Time, error) {
+	signed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return time.Time{}, time.Time{}, nil, errInvalidDate("date")
+	}
+	var startDate time.Time
+	if start != "" {
+		if startDate, err = time.Parse("2006-01-02", start); err != nil {
+			return time.Time{}, time.Time{}, nil, errInvalidDate("start_date")
+		}
+	}
+	var endDate *time.Time
+	if end != "" {
+		parsed, err := time.Parse("2006-01-02", end)
+		if err != nil {
+			return time.Time{}, time.Time{}, nil, errInvalidDate("end_date")
+		}
+		endDate = &parsed
+	}
+	return signed, startDate, endDate, nil
+}
+
+func errInvalidDate(field string) error {
+	return errors.New("invalid " + field + ", expected YYYY-MM-DD")
+}
+
+// contractError întoarce statutul HTTP pentru erorile de validare ale contractului (0 - altă eroare)
+func contractError(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidContractStatus), errors.Is(err, service.ErrInvalidContractPeriod),
+		errors.Is(err, service.ErrInvalidCurrency):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrInvalidEffectiveFrom):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrContractTransition), errors.Is(err, service.ErrContractEnded),
+		errors.Is(err, service.ErrAmendmentRequired), errors.Is(err, service.ErrContractClosed):
+		return http.StatusConflict
+	}
+	return 0
+}
+
+// Cererea de modificare a contractului; statutul se schimbă prin POST /contracts/:id/status.
+// Numele, suma, perioada și termenii se modifică direct doar la ciorne, altfel prin acord adițional.
+type ContractUpdateReq struct {
+	Name        *string  `json:"name"`
+	Amount      *float64 `json:"amount"`
+	Terms       *string  `json:"terms"`
+	StartDate   *string  `json:"start_date"` // YYYY-MM-DD
+	EndDate     *string  `json:"end_date"`   // YYYY-MM-DD, "" - pe termen nelimitat
+	IncomeTaxID *uint    `json:"income_tax_id"`
+}
+
+// Handler pentru modificarea contractului (PATCH /contracts/:id)
+func UpdateContractHandler(s Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		requests, err := ParseBody[AddressReq](c)
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		contract, err := s.FindContractByID(uint(id))
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format"})
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
 			return
 		}
 
-		val, _ := c.Get("user_id")
-		ownerID := val.(uint)
+		var req ContractUpdateReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalid: " + err.Error()})
+			return
+		}
 
-		created := make([]*models.ContractAddress, 0)
-		for _, req := range requests {
-			addr := &models.ContractAddress{
-				ContractID: req.ContractID,
-				Address:    req.Address,
-				Type:       req.Type,
-				OwnerID:    ownerID,
+		if req.Name != nil {
+			contract.Name = *req.Name
+		}
+		if req.Amount != nil {
+			contract.Amount = *req.Amount
+		}
+		if req.Terms != nil {
+			contract.Terms = *req.Terms
+		}
+		if !applyContractPeriod(c, &contract.StartDate, &contract.EndDate, req.StartDate, req.EndDate) {
+			return
+		}
+		if req.IncomeTaxID != nil {
+			contract.IncomeTaxID = req.IncomeTaxID
+		}
+
+		if err := s.UpdateContract(c.GetUint("user_id"), contract); err != nil {
+			if status := contractError(err); status != 0 {
+				c.JSON(status, gin.H{"error": err.Error()})
+				return
 			}
-			if err := s.CreateContractAddress(addr); err == nil {
-				created = append(created, addr)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, contract)
+	}
+}
+
+// applyContractPeriod aplică datele din cerere (nil - fără schimbare, end "" - nelimitat);
+// la o dată greșită răspunde cu 400 și întoarce false
+func applyContractPeriod(c *gin.Context, startDate *time.Time, endDate **time.Time, start, end *string) bool {
+	if start != nil {
+		parsed, err := time.Parse("2006-01-02", *start)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidDate("start_date").Error()})
+			return false
+		}
+		*startDate = parsed
+	}
+	if end != nil {
+		*endDate = nil
+		if *end != "" {
+			parsed, err := time.Parse("2006-01-02", *end)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidDate("end_date").Error()})
+				return false
 			}
+			*endDate = &parsed
 		}
-		c.JSON(http.StatusCreated, created)
 	}
+	return true
 }
 
-func GetContractByIDHandler(s Service) gin.HandlerFunc {
+// parseOptionalDate citește data value (YYYY-MM-DD) în target; valoarea goală nu schimbă target.
+// La o dată greșită răspunde cu 400 și întoarce false.
+func parseOptionalDate(c *gin.Context, name, value string, target *time.Time) bool {
+	if value == "" {
+		return true
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidDate(name).Error()})
+		return false
+	}
+	*target = parsed
+	return true
+}
+
+// contractListFilter citește filtrele listei de contracte din query; la o valoare greșită răspunde cu 400
+func contractListFilter(c *gin.Context) (models.ContractListFilter, bool) {
+	filter := models.ContractListFilter{Status: c.Query("status"), Number: c.Query("number")}
+	var ok bool
+	if filter.ClientID, ok = queryUint(c, "client_id"); !ok {
+		return filter, false
+	}
+	if filter.OwnerID, ok = queryUint(c, "owner_id"); !ok {
+		return filter, false
+	}
+	var activeOn, expiresBefore time.Time
+	if !parseOptionalDate(c, "active_on", c.Query("active_on"), &activeOn) ||
+		!parseOptionalDate(c, "expires_before", c.Query("expires_before"), &expiresBefore) {
+		return filter, false
+	}
+	if !activeOn.IsZero() {
+		filter.ActiveOn = &activeOn
+	}
+	if !expiresBefore.IsZero() {
+		filter.ExpiresBefore = &expiresBefore
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return filter, false
+		}
+		filter.Limit = n
+	}
+	if v := c.Query("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+			return filter, false
+		}
+		filter.Offset = n
+	}
+	return filter, true
+}
+
+// writeContractList răspunde cu pagina de contracte și numărul lor total
+func writeContractList(c *gin.Context, s Service, filter models.ContractListFilter) {
+	contracts, total, err := s.FindContracts(filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidContractStatus) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"total": total, "data": contracts})
+}
+
+// Handler pentru lista contractelor (GET /contracts?client_id=&owner_id=&status=&number=&active_on=&expires_before=&limit=&offset=)
+func ListContractsHandler(s Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id, _ := strconv.Atoi(c.Param("id"))
-		res, err := s.FindContractByID(uint(id))
+		filter, ok := contractListFilter(c)
+		if !ok {
+			return
+		}
+		writeContractList(c, s, filter)
+	}
+}
+
+// Handler pentru contractele clientului (GET /clients/:id/contracts), cu aceleași filtre ca lista contractelor
+func GetClientContractsHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		if _, err := s.FindClientByID(uint(id)); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		filter, ok := contractListFilter(c)
+		if !ok {
+			return
+		}
+		filter.ClientID = uint(id)
+		writeContractList(c, s, filter)
+	}
+}
+
+// Acordul adițional: câmpurile lipsă rămân ca în versiunea curentă a contractului
+type ContractAmendmentReq struct {
+	Number        string   `json:"number"`         // Numărul acordului adițional
+	Date          string   `json:"date"`           // Data semnării (YYYY-MM-DD, implicit azi)
+	EffectiveFrom string   `json:"effective_from"` // În vigoare de la (YYYY-MM-DD, implicit data semnării)
+	Name          *string  `json:"name"`
+	Amount        *float64 `json:"amount"`
+	StartDate     *string  `json:"start_date"` // YYYY-MM-DD
+	EndDate       *string  `json:"end_date"`   // YYYY-MM-DD, "" - pe termen nelimitat
+	Terms         *string  `json:"terms"`
+}
+
+// Handler pentru înregistrarea acordului adițional (POST /contracts/:id/amendments)
+func CreateContractAmendmentHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		contract, err := s.FindContractByID(uint(id))
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
 			return
 		}
-		c.JSON(http.StatusOK, res)
+
+		var req ContractAmendmentReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalid: " + err.Error()})
+			return
+		}
+
+		amendment := &models.ContractAmendment{
+			Number:    req.Number,
+			Name:      contract.Name,
+			Amount:    contract.Amount,
+			StartDate: contract.StartDate,
+			EndDate:   contract.EndDate,
+			Terms:     contract.Terms,
+		}
+		if !parseOptionalDate(c, "date", req.Date, &amendment.Date) ||
+			!parseOptionalDate(c, "effective_from", req.EffectiveFrom, &amendment.EffectiveFrom) {
+			return
+		}
+		if req.Name != nil {
+			amendment.Name = *req.Name
+		}
+		if req.Amount != nil {
+			amendment.Amount = *req.Amount
+		}
+		if req.Terms != nil {
+			amendment.Terms = *req.Terms
+		}
+		if !applyContractPeriod(c, &amendment.StartDate, &amendment.EndDate, req.StartDate, req.EndDate) {
+			return
+		}
+
+		if err := s.CreateContractAmendment(c.GetUint("user_id"), contract, amendment); err != nil {
+			if status := contractError(err); status != 0 {
+				c.JSON(status, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, amendment)
 	}
 }
 
-func GetContractAddressByIDHandler(s Service) gin.HandlerFunc {
+// Handler pentru versiunile contractului (GET /contracts/:id/amendments)
+func GetContractAmendmentsHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		// Versiunile se văd doar pentru contractele din canalele utilizatorului
+		if _, err := s.FindContractByID(uint(id)); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+			return
+		}
+		amendments, err := s.FindContractAmendments(uint(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if len(amendments) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+			return
+		}
+		c.JSON(http.StatusOK, amendments)
+	}
+}
+
+// Handler pentru o versiune a contractului (GET /contracts/:id/amendments/:version)
+func GetContractAmendmentHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		version, err := strconv.Atoi(c.Param("version"))
+		if err != nil || version < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+			return
+		}
+		if _, err := s.FindContractByID(uint(id)); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+			return
+		}
+		amendment, err := s.FindContractAmendment(uint(id), version)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "version not found"})
+			return
+		}
+		c.JSON(http.StatusOK, amendment)
+	}
+}
+
+// Cererea de schimbare a statutului contractului
+type ContractStatusReq struct {
+	Status string `json:"status" binding:"required"` // active, suspended, expired, closed
+	Reason string `json:"reason"`                    // Motivul, se păstrează în jurnalul de audit
+}
+
+// Handler pentru schimbarea statutului contractului (POST /contracts/:id/status)
+func ChangeContractStatusHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		var req ContractStatusReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalid: " + err.Error()})
+			return
+		}
+
+		contract, err := s.ChangeContractStatus(c.GetUint("user_id"), uint(id), req.Status, req.Reason)
+		if err != nil {
+			if isNotFound(err) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+				return
+			}
+			if status := contractError(err); status != 0 {
+				c.JSON(status, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, contract)
+	}
+}
+
+// Handler pentru rularea manuală a expirării contractelor și a anunțurilor (POST /contracts/lifecycle/run)
+func RunContractLifecycleHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		expired, notified, err := s.RunContractLifecycle()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"expired": expired, "notified": notified})
+	}
+}
+
+func GetContractByIDHandler(s Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, _ := strconv.Atoi(c.Param("id"))
-		res, err := s.FindContractAddressByID(uint(id))
+		res, err := s.FindContractByID(uint(id))
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Address not found"})
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
 			return
 		}
 		c.JSON(http.StatusOK, res)
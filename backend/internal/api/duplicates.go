@@ -0,0 +1,201 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"orders/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler pentru perechile de clienți posibil duplicați (GET /clients/duplicates?status=open&min_score=0.8&limit=100, doar admin)
+func ListDuplicateClientsHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := c.DefaultQuery("status", "open")
+		minScore := 0.0
+		if v := c.Query("min_score"); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil || f < 0 || f > 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid min_score"})
+				return
+			}
+			minScore = f
+		}
+		limit := 100
+		if v := c.Query("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+				return
+			}
+			limit = n
+		}
+
+		candidates, err := s.FindDuplicateCandidates(status, minScore, limit)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidDuplicateStatus) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, candidates)
+	}
+}
+
+// Handler pentru pornirea manuală a detectării duplicatelor (POST /clients/duplicates/scan, doar admin)
+func ScanDuplicateClientsHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		found, err := s.DetectDuplicateClients()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"candidates": found})
+	}
+}
+
+// Handler pentru respingerea unei perechi: clienții nu sunt duplicați (POST /clients/duplicates/:id/dismiss, doar admin)
+func DismissDuplicateClientsHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		if err := s.DismissDuplicateCandidate(uint(id)); err != nil {
+			switch {
+			case isNotFound(err):
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case errors.Is(err, service.ErrCandidateNotOpen):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// Cererea de unire a doi clienți
+type ClientMergeReq struct {
+	SurvivorID  uint  `json:"survivor_id" binding:"required"` // Clientul care rămâne
+	MergedID    uint  `json:"merged_id" binding:"required"`   // Clientul care se unește și se șterge
+	CandidateID *uint `json:"candidate_id"`                   // Perechea detectată (opțional)
+}
+
+// Handler pentru unirea clienților (POST /clients/merge, doar admin)
+func MergeClientsHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ClientMergeReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		merge, err := s.MergeClients(c.GetUint("user_id"), req.SurvivorID, req.MergedID, req.CandidateID)
+		if err != nil {
+			switch {
+			case isNotFound(err):
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case errors.Is(err, service.ErrSameClient), errors.Is(err, service.ErrCandidateMismatch):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case errors.Is(err, service.ErrCandidateNotOpen):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.JSON(http.StatusCreated, merge)
+	}
+}
+
+// Handler pentru istoricul unirilor unui client (GET /clients/:id/merges, doar admin)
+func GetClientMergesHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		merges, err := s.FindClientMerges(uint(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, merges)
+	}
+}
+
+// Handler pentru o unire (GET /client-merges/:id, doar admin)
+func GetClientMergeHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		merge, err := s.FindClientMergeByID(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Merge not found"})
+			return
+		}
+		c.JSON(http.StatusOK, merge)
+	}
+}
+
+// Handler pentru anularea unei uniri (POST /client-merges/:id/undo, doar admin)
+func UndoClientMergeHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		merge, err := s.UndoClientMerge(c.GetUint("user_id"), uint(id))
+		if err != nil {
+			switch {
+			case isNotFound(err):
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case errors.Is(err, service.ErrMergeAlreadyUndone), errors.Is(err, service.ErrMergeConflict):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, merge)
+	}
+}
+
+// Handler pentru jurnalul de audit (GET /audit-log?entity_type=client&entity_id=5&limit=100, doar admin)
+func GetAuditLogHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityID, ok := queryUint(c, "entity_id")
+		if !ok {
+			return
+		}
+		limit := 100
+		if v := c.Query("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+				return
+			}
+			limit = n
+		}
+
+		logs, err := s.FindAuditLogs(c.Query("entity_type"), entityID, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, logs)
+	}
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"orders/internal/commerceml"
+	"orders/internal/service"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler pentru importul fișierelor CommerceML din 1C (POST /exchange/1c/import, doar admin).
+// Se acceptă corpul cererii (XML) sau multipart cu unul sau mai multe câmpuri "file";
+// fișierele se importă în ordinea trimisă (import.xml înaintea offers.xml).
+func ImportCommerceMLHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetUint("user_id")
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, s.MaxUploadBytes())
+
+		if !strings.HasPrefix(c.ContentType(), "multipart/") {
+			result, err := s.ImportCommerceML(userID, "request.xml", c.Request.Body)
+			if err != nil {
+				commerceMLError(c, err, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"files": []gin.H{{"file": "request.xml", "result": result}}})
+			return
+		}
+
+		form, err := c.MultipartForm()
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrFileTooLarge.Error()})
+			return
+		}
+		if err != nil || len(form.File["file"]) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "multipart field 'file' is required"})
+			return
+		}
+
+		files := make([]gin.H, 0, len(form.File["file"]))
+		for _, header := range form.File["file"] {
+			file, err := header.Open()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "file": header.Filename, "files": files})
+				return
+			}
+			result, err := s.ImportCommerceML(userID, header.Filename, file)
+			file.Close()
+			if err != nil {
+				// Fișierele anterioare au fost deja importate; se raportează și rezultatul lor
+				commerceMLError(c, err, gin.H{"error": err.Error(), "file": header.Filename, "files": files})
+				return
+			}
+			files = append(files, gin.H{"file": header.Filename, "result": result})
+		}
+		c.JSON(http.StatusOK, gin.H{"files": files})
+	}
+}
+
+// commerceMLError răspunde cu 413 pentru cererile mai mari decât MAX_UPLOAD_MB, 400 pentru fișierele invalide
+// și 500 pentru erorile bazei de date
+func commerceMLError(c *gin.Context, err error, body gin.H) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		body["error"] = service.ErrFileTooLarge.Error()
+		c.JSON(http.StatusRequestEntityTooLarge, body)
+		return
+	}
+	if errors.Is(err, commerceml.ErrInvalidMessage) {
+		c.JSON(http.StatusBadRequest, body)
+		return
+	}
+	c.JSON(http.StatusInternalServerError, body)
+}
+
+// Handler pentru exportul comenzilor către 1C (GET /exchange/1c/orders?full=true, doar admin).
+// Implicit conține doar comenzile modificate după ultimul export reușit.
+func ExportOrdersCommerceMLHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		full := false
+		if v := c.Query("full"); v != "" {
+			var err error
+			if full, err = strconv.ParseBool(v); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid full"})
+				return
+			}
+		}
+
+		data, count, err := s.ExportOrdersCommerceML(c.GetUint("user_id"), full)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="orders.xml"`)
+		c.Header("X-Records-Count", strconv.Itoa(count))
+		c.Data(http.StatusOK, "application/xml; charset=utf-8", data)
+	}
+}
+
+// Handler pentru istoricul schimburilor cu 1C (GET /exchange/1c/log?limit=50, doar admin)
+func GetExchangeLogHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := 50
+		if v := c.Query("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > 500 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+				return
+			}
+			limit = n
+		}
+
+		logs, err := s.FindExchangeLogs(limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, logs)
+	}
+}
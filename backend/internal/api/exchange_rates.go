@@ -0,0 +1,66 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler pentru importul cursurilor oficiale BNM (POST /exchange-rates/import)
+// Acceptă fișierul XML în body (Content-Type: application/xml) sau în câmpul multipart "file".
+func ImportExchangeRatesHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reader io.Reader = c.Request.Body
+		if strings.HasPrefix(c.ContentType(), "multipart/") {
+			file, _, err := c.Request.FormFile("file")
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "multipart field 'file' is required"})
+				return
+			}
+			defer file.Close()
+			reader = file
+		}
+
+		rates, err := s.ImportExchangeRates(reader)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"imported": rates, "count": len(rates)})
+	}
+}
+
+// Handler pentru cursul în vigoare la o dată (GET /exchange-rates?currency=EUR&date=YYYY-MM-DD)
+func GetExchangeRateHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currency := c.Query("currency")
+		if currency == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter 'currency' is required"})
+			return
+		}
+
+		date := time.Now()
+		if v := c.Query("date"); v != "" {
+			parsed, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date, expected YYYY-MM-DD"})
+				return
+			}
+			date = parsed
+		}
+
+		rate, err := s.FindExchangeRateAt(currency, date)
+		if err != nil {
+			if isNotFound(err) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Exchange rate not found"})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, rate)
+	}
+}
@@ -1,34 +1,155 @@
 package api
 
 import (
+	"io"
 	"net/http"
 	"orders/internal/models"
+	"orders/internal/service"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Service interface {
 	// Authentication methods
-	Signup(email, password, role string) error
-	Login(email, password string) (string, error)
+	FindInvitationByToken(token string) (*models.Invitation, error)
+	AcceptInvitation(token, password string) (*models.User, error)
+	Login(email, password, userAgent, ip string) (*service.TokenPair, error)
+	RefreshSession(refreshToken string) (*service.TokenPair, error)
+	Authenticate(accessToken string) (*service.AccessClaims, error)
+	FindSessions(userID uint) ([]models.Session, error)
+	Logout(sessionID uint) error
+	LogoutAll(actorID, userID uint) (int64, error)
+	ChangePassword(userID, sessionID uint, currentPassword, newPassword string) error
+	RequestPasswordReset(email, ip string) error
+	ResetPassword(token, newPassword string) error
+	CreateAPIKey(adminID uint, name string, permissions []string, channelIDs []uint, expiresAt time.Time) (*models.APIKey, string, error)
+	FindAPIKeys() ([]models.APIKey, error)
+	RevokeAPIKey(adminID, id uint) (*models.APIKey, error)
+	AuthenticateAPIKey(secret, ip string) (*models.APIKey, error)
+	APIKeyChannelScope(key *models.APIKey) models.ChannelScope
+	RecordAPIKeyRequest(key *models.APIKey, method, path string, status int) error
+
+	// Role methods
+	UserRole(userID uint) (string, error)
+	FindRoles() []service.RolePermissions
+	FindUsers(role string) ([]models.User, error)
+	AssignUserRole(adminID, userID uint, role string) (*models.User, error)
+	CreateInvitation(adminID uint, email, role string, channelIDs []uint) (*models.Invitation, string, error)
+	FindInvitations(status string) ([]models.Invitation, error)
+	RevokeInvitation(adminID, id uint) (*models.Invitation, error)
+
+	// Channel methods
+	ChannelScope(userID uint, role string) (models.ChannelScope, error)
+	WithChannelScope(scope models.ChannelScope) *service.Service
+	WithAPIKey(keyID uint) *service.Service
+	CreateChannel(channel *models.Channel) error
+	FindChannels() ([]models.Channel, error)
+	FindChannelByID(id uint) (*models.Channel, error)
+	UpdateChannel(channel *models.Channel) error
+	DeleteChannel(id uint) error
+	FindChannelUsers(channelID uint) ([]models.User, error)
+	AddChannelUsers(channelID uint, userIDs []uint) error
+	RemoveChannelUser(channelID, userID uint) error
 
 	// Order methods
 	CreateOrder(userID uint, order *models.Order) error
+	ApproveOrder(userID, id uint) (*models.Order, error)
 	FindOrdersByUserID(userID uint) ([]models.Order, error)
 	FindOrderByID(id uint) (*models.Order, error)
+	FindOrderDocument(id uint) (*models.Order, error)
 
 	// Client methods
 	CreateClient(client *models.Client) error
 	FindClientByID(id uint) (*models.Client, error)
 	FindClientByFiscalID(fiscalID string) (*models.Client, error)
-	GetFirst1000Clients() ([]models.Client, error)
-	FindClientsByQuery(query string) ([]models.Client, error)
+	CountClients(filter models.ClientListFilter) (int64, error)
+	ListClients(filter models.ClientListFilter, cursor string, fn func(client *models.Client) error) (string, error)
+	SearchClients(query string, page, pageSize int) ([]models.ClientSearchHit, int64, error)
+	UpdateClient(client *models.Client) error
+	DeleteClient(id uint) error
+	InvalidFiscalIDReport() ([]models.InvalidFiscalIDEntry, error)
+
+	// Duplicate methods
+	DetectDuplicateClients() (int, error)
+	FindDuplicateCandidates(status string, minScore float64, limit int) ([]models.DuplicateCandidate, error)
+	DismissDuplicateCandidate(id uint) error
+	MergeClients(userID, survivorID, mergedID uint, candidateID *uint) (*models.ClientMerge, error)
+	UndoClientMerge(userID, mergeID uint) (*models.ClientMerge, error)
+	FindClientMergeByID(id uint) (*models.ClientMerge, error)
+	FindClientMerges(clientID uint) ([]models.ClientMerge, error)
+
+	// Audit methods
+	FindAuditLogs(entityType string, entityID uint, limit int) ([]models.AuditLog, error)
+
+	// Notification methods
+	FindNotifications(userID uint, unreadOnly bool, limit int) ([]models.Notification, error)
+	MarkNotificationRead(userID, id uint) error
+
+	// Import methods
+	ImportClients(userID uint, fileName string, r io.Reader, opts service.ImportOptions) (*models.Import, []models.ImportRow, error)
+	FindImportByID(id uint) (*models.Import, error)
+	OpenImportResult(imp *models.Import) (io.ReadCloser, error)
+
+	// 1C exchange methods
+	MaxUploadBytes() int64
+	ImportCommerceML(userID uint, fileName string, r io.Reader) (*service.ExchangeResult, error)
+	ExportOrdersCommerceML(userID uint, full bool) ([]byte, int, error)
+	FindExchangeLogs(limit int) ([]models.ExchangeLog, error)
+
+	// ClientContact methods
+	CreateClientContact(contact *models.ClientContact) error
+	FindClientContactByID(id uint) (*models.ClientContact, error)
+	UpdateClientContact(contact *models.ClientContact) error
+	DeleteClientContact(id uint) error
+
+	// ClientBankAccount methods
+	CreateClientBankAccount(account *models.ClientBankAccount) error
+	FindClientBankAccountByID(id uint) (*models.ClientBankAccount, error)
+	UpdateClientBankAccount(account *models.ClientBankAccount) error
+	DeleteClientBankAccount(account *models.ClientBankAccount) error
+	MatchStatementLines(lines []models.StatementLine) ([]models.StatementMatch, error)
 
 	// Contract methods
 	CreateContract(contract *models.Contract) error
 	FindContractByID(id uint) (*models.Contract, error)
+	UpdateContract(userID uint, contract *models.Contract) error
+	FindContracts(filter models.ContractListFilter) ([]models.Contract, int64, error)
+	CreateContractAmendment(userID uint, contract *models.Contract, amendment *models.ContractAmendment) error
+	FindContractAmendments(contractID uint) ([]models.ContractAmendment, error)
+	FindContractAmendment(contractID uint, version int) (*models.ContractAmendment, error)
+
+	// ContractPrice methods
+	CreateContractPrice(contract *models.Contract, price *models.ContractPrice) error
+	FindContractPrice(contractID, id uint) (*models.ContractPrice, error)
+	UpdateContractPrice(contract *models.Contract, price *models.ContractPrice) error
+	DeleteContractPrice(id uint) error
+	FindContractPrices(contractID uint, date *time.Time) ([]models.ContractPrice, error)
+	ContractPriceReport(contractID, clientID, priceTypeID uint, date time.Time) ([]models.ContractPriceComparison, error)
+	ChangeContractStatus(userID, id uint, status, reason string) (*models.Contract, error)
+	RunContractLifecycle() (expired, notified int, err error)
+
+	// ContractAddress methods
 	CreateContractAddress(addr *models.ContractAddress) error
 	FindContractAddressByID(id uint) (*models.ContractAddress, error)
+	FindContractAddresses(contractID uint) ([]models.ContractAddress, error)
+	UpdateContractAddress(addr *models.ContractAddress) error
+	DeleteContractAddress(id uint) error
+
+	// Locality methods
+	ImportLocalities(fileName string, r io.Reader) (*service.LocalityImportResult, error)
+	SearchLocalities(query string, limit int) ([]models.Locality, error)
+	FindLocalityByCode(code string) (*models.Locality, error)
+
+	// ContractTemplate methods
+	CreateContractTemplate(userID uint, template *models.ContractTemplate) error
+	FindContractTemplateByID(id uint) (*models.ContractTemplate, error)
+	FindContractTemplates(activeOnly bool) ([]models.ContractTemplate, error)
+	UpdateContractTemplate(userID uint, template *models.ContractTemplate) error
+	FindContractTemplateVersions(templateID uint) ([]models.ContractTemplateVersion, error)
+	FindContractTemplateVersion(templateID uint, version int) (*models.ContractTemplateVersion, error)
+	RenderContractDocument(userID, contractID, templateID uint, templateVersion int, format string) (*models.ContractDocument, error)
+	FindContractDocuments(contractID uint) ([]models.ContractDocument, error)
 
 	// Product methods
 	CreateProduct(product *models.Product) error
@@ -36,6 +157,55 @@ type Service interface {
 	FindVatTaxByID(id uint) (*models.VatTax, error)
 	FindUnitByID(id uint) (*models.Unit, error)
 	FindProductGroupByID(id uint) (*models.ProductGroup, error)
+	FindProductBySKU(sku string) (*models.Product, error)
+
+	// Barcode methods
+	CreateProductBarcode(barcode *models.ProductBarcode) error
+	FindProductBarcode(code string) (*models.ProductBarcode, error)
+	FindProductByBarcode(code string, priceTypeID uint) (*service.BarcodeLookup, error)
+
+	// VAT methods
+	FindAllVatTaxes() ([]models.VatTax, error)
+	AddVatTaxRate(rate *models.VatTaxRate) error
+
+	// Payment methods
+	CreatePayment(userID uint, payment *models.Payment) error
+	FindPaymentByID(id uint) (*models.Payment, error)
+
+	// Exchange rate methods
+	ImportExchangeRates(r io.Reader) ([]models.ExchangeRate, error)
+	FindExchangeRateAt(currency string, date time.Time) (*models.ExchangeRate, error)
+
+	// Report methods
+	VatReport(from, to time.Time) ([]models.VatSummary, error)
+	IncomeTaxReport(from, to time.Time) ([]models.IncomeTaxSummary, error)
+
+	// Attachment methods
+	UploadAttachment(ownerType string, ownerID, userID uint, fileName string, r io.Reader) (*models.Attachment, error)
+	FindAttachmentByID(id uint) (*models.Attachment, error)
+	FindAttachmentsByOwner(ownerType string, ownerID uint) ([]models.Attachment, error)
+	OpenAttachment(attachment *models.Attachment, thumbnail bool) (io.ReadCloser, error)
+	DeleteAttachment(id uint) error
+	SignAttachmentURL(id uint, thumbnail bool, ttl time.Duration) string
+	VerifyAttachmentSignature(id uint, thumbnail bool, expires, signature string) bool
+}
+
+// scoped construiește handler-ul la fiecare cerere cu serviciul restrâns la canalele de vânzări ale utilizatorului
+// (setate de channelScopeMiddleware): clienții, contractele și comenzile din alte canale nu se văd
+func scoped(s Service, handler func(s Service) gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope, _ := c.Get("channel_scope")
+		channels, ok := scope.(models.ChannelScope)
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Channel scope required"})
+			return
+		}
+		scopedService := s.WithChannelScope(channels)
+		if key, ok := c.Get("api_key"); ok {
+			scopedService = scopedService.WithAPIKey(key.(*models.APIKey).ID)
+		}
+		handler(scopedService)(c)
+	}
 }
 
 func SetupRoutes(router *gin.Engine, service Service) {
@@ -45,23 +215,6 @@ func SetupRoutes(router *gin.Engine, service Service) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 	// --- Auth ---
-	router.POST("/signup", func(context *gin.Context) {
-		var req struct {
-			Email    string `json:"email"`
-			Password string `json:"password"`
-			Role     string `json:"role"`
-		}
-		if err := context.ShouldBindJSON(&req); err != nil {
-			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		if err := service.Signup(req.Email, req.Password, req.Role); err != nil {
-			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		context.JSON(http.StatusOK, gin.H{"message": "User created"})
-	})
-
 	router.POST("/login", func(context *gin.Context) {
 		// 1. Folosim procedura universală care știe JSON și XML
 		// Definim o structură locală sau folosim una din modele
@@ -80,42 +233,181 @@ func SetupRoutes(router *gin.Engine, service Service) {
 		req := requests[0]
 
 		// 2. Logica de login
-		token, err := service.Login(req.Email, req.Password)
+		tokens, err := service.Login(req.Email, req.Password, context.Request.UserAgent(), context.ClientIP())
 		if err != nil {
 			context.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 			return
 		}
 
-		context.JSON(http.StatusOK, gin.H{"token": token})
+		context.JSON(http.StatusOK, tokens)
 	})
+	router.POST("/refresh", RefreshSessionHandler(service))
+	router.POST("/password/forgot", ForgotPasswordHandler(service))
+	router.POST("/password/reset", ResetPasswordHandler(service))
+
+	// Conturile noi se creează doar prin invitațiile administratorului
+	router.GET("/invitations/:token", GetInvitationByTokenHandler(service))
+	router.POST("/invitations/accept", AcceptInvitationHandler(service))
+
+	// --- Signed file downloads (autorizate prin semnătura din link) ---
+	router.GET("/files/attachments/:id", SignedDownloadHandler(service))
 
 	// API v1 routes with prefix
 	api := router.Group("/api/v1")
-	protected := api.Group("/").Use(authMiddleware())
+	protected := api.Group("/").Use(authMiddleware(service), roleMiddleware(service), channelScopeMiddleware(service))
 	{
 
+		// --- Sessions ---
+		protected.GET("/sessions", userOnly(), scoped(service, ListSessionsHandler))
+		protected.POST("/logout", userOnly(), scoped(service, LogoutHandler))
+		protected.POST("/logout/all", userOnly(), scoped(service, LogoutAllHandler))
+		protected.PUT("/password", userOnly(), scoped(service, ChangePasswordHandler))
+
+		// --- Roles and users ---
+		protected.GET("/roles", requirePermission("users:manage"), scoped(service, ListRolesHandler))
+		protected.GET("/users", requirePermission("users:manage"), scoped(service, ListUsersHandler))
+		protected.PUT("/users/:id/role", requirePermission("users:manage"), scoped(service, AssignUserRoleHandler))
+		protected.DELETE("/users/:id/sessions", requirePermission("users:manage"), scoped(service, LogoutUserHandler))
+
+		// --- API keys ---
+		protected.POST("/api-keys", requirePermission("users:manage"), scoped(service, CreateAPIKeyHandler))
+		protected.GET("/api-keys", requirePermission("users:manage"), scoped(service, ListAPIKeysHandler))
+		protected.DELETE("/api-keys/:id", requirePermission("users:manage"), scoped(service, RevokeAPIKeyHandler))
+		protected.POST("/invitations", requirePermission("users:manage"), scoped(service, CreateInvitationHandler))
+		protected.GET("/invitations", requirePermission("users:manage"), scoped(service, ListInvitationsHandler))
+		protected.DELETE("/invitations/:id", requirePermission("users:manage"), scoped(service, RevokeInvitationHandler))
+
+		// --- Channels ---
+		protected.GET("/channels", scoped(service, ListChannelsHandler))
+		protected.POST("/channels", requirePermission("channels:manage"), scoped(service, CreateChannelHandler))
+		protected.GET("/channels/:id", requirePermission("channels:manage"), scoped(service, GetChannelHandler))
+		protected.PATCH("/channels/:id", requirePermission("channels:manage"), scoped(service, UpdateChannelHandler))
+		protected.DELETE("/channels/:id", requirePermission("channels:manage"), scoped(service, DeleteChannelHandler))
+		protected.GET("/channels/:id/users", requirePermission("channels:manage"), scoped(service, GetChannelUsersHandler))
+		protected.POST("/channels/:id/users", requirePermission("channels:manage"), scoped(service, AddChannelUsersHandler))
+		protected.DELETE("/channels/:id/users/:user_id", requirePermission("channels:manage"), scoped(service, RemoveChannelUserHandler))
+
 		// --- Orders ---
-		protected.POST("/orders", CreateOrderHandler(service))
-		protected.GET("/orders", GetOrdersHandler(service))
-		protected.GET("/orders/:id", GetOrderHandler(service))
+		protected.POST("/orders", requirePermission("orders:create"), scoped(service, CreateOrderHandler))
+		protected.GET("/orders", requirePermission("orders:read"), scoped(service, GetOrdersHandler))
+		protected.GET("/orders/:id", requirePermission("orders:read"), scoped(service, GetOrderHandler))
+		protected.GET("/orders/:id/print", requirePermission("orders:read"), scoped(service, PrintOrderHandler))
+		protected.POST("/orders/:id/approve", requirePermission("orders:approve"), scoped(service, ApproveOrderHandler))
 
 		// --- Clients ---
-		protected.POST("/clients", CreateClientHandler(service))
-		protected.GET("/clients", GetFirst1000Clients(service))
-		protected.GET("/clients/search", SearchClientsHandler(service))
-		protected.GET("/clients/:id", GetClientByIDHandler(service))
+		protected.POST("/clients", requirePermission("clients:edit"), scoped(service, CreateClientHandler))
+		protected.GET("/clients", requirePermission("clients:read"), scoped(service, ListClientsHandler))
+		protected.GET("/clients/search", requirePermission("clients:read"), scoped(service, SearchClientsHandler))
+		protected.GET("/clients/:id", requirePermission("clients:read"), scoped(service, GetClientByIDHandler))
+		protected.PATCH("/clients/:id", requirePermission("clients:edit"), scoped(service, UpdateClientHandler))
+		protected.DELETE("/clients/:id", requirePermission("clients:delete"), scoped(service, DeleteClientHandler))
+		protected.POST("/clients/:id/contacts", requirePermission("clients:edit"), scoped(service, CreateClientContactHandler))
+		protected.PATCH("/clients/:id/contacts/:contact_id", requirePermission("clients:edit"), scoped(service, UpdateClientContactHandler))
+		protected.DELETE("/clients/:id/contacts/:contact_id", requirePermission("clients:edit"), scoped(service, DeleteClientContactHandler))
+		protected.POST("/clients/:id/bank-accounts", requirePermission("clients:edit"), scoped(service, CreateClientBankAccountHandler))
+		protected.PATCH("/clients/:id/bank-accounts/:account_id", requirePermission("clients:edit"), scoped(service, UpdateClientBankAccountHandler))
+		protected.DELETE("/clients/:id/bank-accounts/:account_id", requirePermission("clients:edit"), scoped(service, DeleteClientBankAccountHandler))
+		protected.GET("/clients/:id/contracts", requirePermission("contracts:read"), scoped(service, GetClientContractsHandler))
+
+		// --- Duplicate clients ---
+		protected.GET("/clients/duplicates", requirePermission("clients:merge"), scoped(service, ListDuplicateClientsHandler))
+		protected.POST("/clients/duplicates/scan", requirePermission("clients:merge"), scoped(service, ScanDuplicateClientsHandler))
+		protected.POST("/clients/duplicates/:id/dismiss", requirePermission("clients:merge"), scoped(service, DismissDuplicateClientsHandler))
+		protected.POST("/clients/merge", requirePermission("clients:merge"), scoped(service, MergeClientsHandler))
+		protected.GET("/clients/:id/merges", requirePermission("clients:merge"), scoped(service, GetClientMergesHandler))
+		protected.GET("/client-merges/:id", requirePermission("clients:merge"), scoped(service, GetClientMergeHandler))
+		protected.POST("/client-merges/:id/undo", requirePermission("clients:merge"), scoped(service, UndoClientMergeHandler))
+
+		// --- Imports ---
+		protected.POST("/imports/clients", requirePermission("clients:import"), scoped(service, ImportClientsHandler))
+		protected.GET("/imports/:id", requirePermission("clients:import"), scoped(service, GetImportHandler))
+		protected.GET("/imports/:id/result", requirePermission("clients:import"), scoped(service, DownloadImportResultHandler))
+
+		// --- 1C exchange ---
+		protected.POST("/exchange/1c/import", requirePermission("exchange:1c"), scoped(service, ImportCommerceMLHandler))
+		protected.GET("/exchange/1c/orders", requirePermission("exchange:1c"), scoped(service, ExportOrdersCommerceMLHandler))
+		protected.GET("/exchange/1c/log", requirePermission("exchange:1c"), scoped(service, GetExchangeLogHandler))
 
 		// --- Contracts ---
-		protected.POST("/contracts", CreateContractHandler(service))
-		protected.GET("/contracts/:id", GetContractByIDHandler(service))
+		protected.POST("/contracts", requirePermission("contracts:edit"), scoped(service, CreateContractHandler))
+		protected.GET("/contracts", requirePermission("contracts:read"), scoped(service, ListContractsHandler))
+		protected.POST("/contracts/lifecycle/run", requirePermission("jobs:run"), scoped(service, RunContractLifecycleHandler))
+		protected.GET("/contracts/:id", requirePermission("contracts:read"), scoped(service, GetContractByIDHandler))
+		protected.PATCH("/contracts/:id", requirePermission("contracts:edit"), scoped(service, UpdateContractHandler))
+		protected.POST("/contracts/:id/status", requirePermission("contracts:approve"), scoped(service, ChangeContractStatusHandler))
+		protected.POST("/contracts/:id/amendments", requirePermission("contracts:edit"), scoped(service, CreateContractAmendmentHandler))
+		protected.GET("/contracts/:id/amendments", requirePermission("contracts:read"), scoped(service, GetContractAmendmentsHandler))
+		protected.GET("/contracts/:id/amendments/:version", requirePermission("contracts:read"), scoped(service, GetContractAmendmentHandler))
+		protected.POST("/contracts/:id/prices", requirePermission("prices:edit"), scoped(service, CreateContractPricesHandler))
+		protected.GET("/contracts/:id/prices", requirePermission("contracts:read"), scoped(service, GetContractPricesHandler))
+		protected.PATCH("/contracts/:id/prices/:price_id", requirePermission("prices:edit"), scoped(service, UpdateContractPriceHandler))
+		protected.DELETE("/contracts/:id/prices/:price_id", requirePermission("prices:edit"), scoped(service, DeleteContractPriceHandler))
+		protected.POST("/contracts/:id/documents", requirePermission("contracts:edit"), scoped(service, CreateContractDocumentHandler))
+		protected.GET("/contracts/:id/documents", requirePermission("contracts:read"), scoped(service, GetContractDocumentsHandler))
+
+		// --- ContractTemplates ---
+		protected.GET("/contract-templates/placeholders", GetContractPlaceholdersHandler())
+		protected.GET("/contract-templates", requirePermission("contracts:read"), scoped(service, ListContractTemplatesHandler))
+		protected.GET("/contract-templates/:id", requirePermission("contracts:read"), scoped(service, GetContractTemplateHandler))
+		protected.GET("/contract-templates/:id/versions/:version", requirePermission("contracts:read"), scoped(service, GetContractTemplateVersionHandler))
+		protected.POST("/contract-templates", requirePermission("templates:edit"), scoped(service, CreateContractTemplateHandler))
+		protected.PATCH("/contract-templates/:id", requirePermission("templates:edit"), scoped(service, UpdateContractTemplateHandler))
 
 		// --- ContractAddresses ---
-		protected.POST("/contract_addresses", CreateContractAddressHandler(service))
-		protected.GET("/contract_addresses/:id", GetContractAddressByIDHandler(service))
+		protected.POST("/contract_addresses", requirePermission("contracts:edit"), scoped(service, CreateContractAddressHandler))
+		protected.GET("/contract_addresses/:id", requirePermission("contracts:read"), scoped(service, GetContractAddressByIDHandler))
+		protected.GET("/contracts/:id/addresses", requirePermission("contracts:read"), scoped(service, GetContractAddressesHandler))
+		protected.PATCH("/contracts/:id/addresses/:address_id", requirePermission("contracts:edit"), scoped(service, UpdateContractAddressHandler))
+		protected.DELETE("/contracts/:id/addresses/:address_id", requirePermission("contracts:edit"), scoped(service, DeleteContractAddressHandler))
+
+		// --- Localities (CUATM) ---
+		protected.GET("/localities", scoped(service, SearchLocalitiesHandler))
+		protected.GET("/localities/:code", scoped(service, GetLocalityHandler))
+		protected.POST("/localities/import", requirePermission("reference:edit"), scoped(service, ImportLocalitiesHandler))
 
 		// --- Products ---
-		protected.POST("/products", CreateProductHandler(service))
-		protected.GET("/products/:id", GetProductByIDHandler(service))
+		protected.POST("/products", requirePermission("products:edit"), scoped(service, CreateProductHandler))
+		protected.GET("/products/by-barcode/:code", requirePermission("products:read"), scoped(service, GetProductByBarcodeHandler))
+		protected.GET("/products/:id", requirePermission("products:read"), scoped(service, GetProductByIDHandler))
+		protected.POST("/products/:id/barcodes", requirePermission("products:edit"), scoped(service, AddProductBarcodeHandler))
+
+		// --- Payments ---
+		protected.POST("/payments", requirePermission("payments:create"), scoped(service, CreatePaymentHandler))
+		protected.GET("/payments/:id", requirePermission("payments:read"), scoped(service, GetPaymentHandler))
+		protected.GET("/payments/:id/print", requirePermission("payments:read"), scoped(service, PrintPaymentHandler))
+		protected.POST("/payments/statement/match", requirePermission("payments:create"), scoped(service, MatchStatementHandler))
+
+		// --- VAT ---
+		protected.GET("/vat-taxes", scoped(service, GetVatTaxesHandler))
+		protected.POST("/vat-taxes/:id/rates", requirePermission("reference:edit"), scoped(service, CreateVatTaxRateHandler))
+
+		// --- Exchange rates ---
+		protected.GET("/exchange-rates", scoped(service, GetExchangeRateHandler))
+		protected.POST("/exchange-rates/import", requirePermission("reference:edit"), scoped(service, ImportExchangeRatesHandler))
+
+		// --- Reports ---
+		protected.GET("/reports/vat", requirePermission("reports:read"), scoped(service, VatReportHandler))
+		protected.GET("/reports/income-tax", requirePermission("reports:read"), scoped(service, IncomeTaxReportHandler))
+		protected.GET("/reports/contract-prices", requirePermission("reports:read"), scoped(service, ContractPriceReportHandler))
+		protected.GET("/reports/invalid-fiscal-ids", requirePermission("reports:read"), scoped(service, InvalidFiscalIDReportHandler))
+
+		// --- Audit ---
+		protected.GET("/audit-log", requirePermission("audit:read"), scoped(service, GetAuditLogHandler))
+
+		// --- Notifications ---
+		protected.GET("/notifications", scoped(service, GetNotificationsHandler))
+		protected.POST("/notifications/:id/read", scoped(service, MarkNotificationReadHandler))
+
+		// --- Attachments ---
+		protected.POST("/products/:id/attachments", requirePermission("products:edit"), scoped(service, forOwner(UploadAttachmentHandler, "product")))
+		protected.GET("/products/:id/attachments", requirePermission("products:read"), scoped(service, forOwner(ListAttachmentsHandler, "product")))
+		protected.POST("/contracts/:id/attachments", requirePermission("contracts:edit"), scoped(service, forOwner(UploadAttachmentHandler, "contract")))
+		protected.GET("/contracts/:id/attachments", requirePermission("contracts:read"), scoped(service, forOwner(ListAttachmentsHandler, "contract")))
+		protected.POST("/orders/:id/attachments", requirePermission("orders:create"), scoped(service, forOwner(UploadAttachmentHandler, "order")))
+		protected.GET("/orders/:id/attachments", requirePermission("orders:read"), scoped(service, forOwner(ListAttachmentsHandler, "order")))
+		protected.GET("/attachments/:id", scoped(service, GetAttachmentHandler))
+		protected.GET("/attachments/:id/download", scoped(service, DownloadAttachmentHandler))
+		protected.DELETE("/attachments/:id", scoped(service, DeleteAttachmentHandler))
 
 	}
 }
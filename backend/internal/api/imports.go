@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"orders/internal/models"
+	"orders/internal/service"
+	"orders/internal/spreadsheet"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportClientsHandler gestionează POST /imports/clients (multipart):
+//   - file:    fișierul CSV sau XLSX (primul rând - antetul)
+//   - mapping: JSON opțional {"name":"Denumirea","fiscal_code":"IDNO",...} - câmpul clientului -> coloana din fișier
+//   - mode:    "create" (implicit) sau "upsert" (actualizează clienții existenți după codul fiscal)
+//   - dry_run: "true" - doar validare, fără scriere în baza de date
+func ImportClientsHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		file, header, err := c.Request.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "multipart field 'file' is required"})
+			return
+		}
+		defer file.Close()
+
+		opts := service.ImportOptions{Mode: c.PostForm("mode")}
+		if v := c.PostForm("dry_run"); v != "" {
+			if opts.DryRun, err = strconv.ParseBool(v); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dry_run"})
+				return
+			}
+		}
+		if v := c.PostForm("mapping"); v != "" {
+			if err := json.Unmarshal([]byte(v), &opts.Mapping); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid mapping: " + err.Error()})
+				return
+			}
+		}
+
+		imp, rows, err := s.ImportClients(c.GetUint("user_id"), header.Filename, file, opts)
+		if err != nil {
+			var missing *service.MissingColumnsError
+			switch {
+			case errors.As(err, &missing):
+				c.JSON(http.StatusBadRequest, gin.H{"error": "missing_columns", "columns": missing.Columns})
+			case errors.Is(err, service.ErrFileTooLarge):
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			case errors.Is(err, service.ErrInvalidImportMode), errors.Is(err, service.ErrUnknownImportField),
+				errors.Is(err, service.ErrEmptyFile), errors.Is(err, spreadsheet.ErrUnknownFormat):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		// În răspuns intră doar rândurile cu erori; starea tuturor rândurilor este în fișierul rezultat
+		errorsByRow := make([]models.ImportRow, 0)
+		for _, row := range rows {
+			if row.Action == "skip" {
+				errorsByRow = append(errorsByRow, row)
+			}
+		}
+
+		status := http.StatusCreated
+		if imp.DryRun {
+			status = http.StatusOK
+		}
+		c.JSON(status, gin.H{
+			"import":     imp,
+			"errors":     errorsByRow,
+			"result_url": fmt.Sprintf("/api/v1/imports/%d/result", imp.ID),
+		})
+	}
+}
+
+// findOwnImport citește importul din URL; rezultatele unui import le vede doar autorul lui
+// sau administratorul (permisiunea users:manage)
+func findOwnImport(c *gin.Context, s Service) (*models.Import, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	imp, err := s.FindImportByID(uint(id))
+	if err != nil || (imp.OwnerID != c.GetUint("user_id") && !hasPermission(c, service.PermUsersManage)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+		return nil, false
+	}
+	return imp, true
+}
+
+// GetImportHandler gestionează GET /imports/:id - sumarul importului
+func GetImportHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		imp, ok := findOwnImport(c, s)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"import":     imp,
+			"result_url": fmt.Sprintf("/api/v1/imports/%d/result", imp.ID),
+		})
+	}
+}
+
+// DownloadImportResultHandler gestionează GET /imports/:id/result - fișierul CSV cu starea fiecărui rând
+func DownloadImportResultHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		imp, ok := findOwnImport(c, s)
+		if !ok {
+			return
+		}
+
+		reader, err := s.OpenImportResult(imp)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+			return
+		}
+		defer reader.Close()
+
+		name := strings.TrimSuffix(imp.FileName, filepath.Ext(imp.FileName)) + "-result.csv"
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+		c.Status(http.StatusOK)
+		io.Copy(c.Writer, reader)
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"orders/internal/service"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cererea de invitare a utilizatorului
+type InvitationReq struct {
+	Email      string `json:"email" binding:"required"`
+	Role       string `json:"role" binding:"required"`
+	ChannelIDs []uint `json:"channel_ids"`
+}
+
+// Cererea de acceptare a invitației: token-ul primit și parola aleasă
+type AcceptInvitationReq struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// respondInvitationError întoarce statutul HTTP pentru erorile invitațiilor
+func respondInvitationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidEmail), errors.Is(err, service.ErrInvalidRole),
+		errors.Is(err, service.ErrChannelNotFound), errors.Is(err, service.ErrPasswordTooShort):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserExists), errors.Is(err, service.ErrInvitationNotPending):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvitationInvalid), isNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvitationExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Handler pentru invitarea utilizatorului (POST /invitations). Token-ul se întoarce o singură dată.
+func CreateInvitationHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req InvitationReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		invitation, token, err := s.CreateInvitation(c.GetUint("user_id"), req.Email, req.Role, req.ChannelIDs)
+		if err != nil {
+			respondInvitationError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"invitation": invitation, "token": token})
+	}
+}
+
+// Handler pentru lista invitațiilor (GET /invitations?status=pending|accepted|expired|revoked)
+func ListInvitationsHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		invitations, err := s.FindInvitations(strings.TrimSpace(c.Query("status")))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, invitations)
+	}
+}
+
+// Handler pentru anularea invitației neacceptate (DELETE /invitations/:id)
+func RevokeInvitationHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		invitation, err := s.RevokeInvitation(c.GetUint("user_id"), uint(id))
+		if err != nil {
+			respondInvitationError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, invitation)
+	}
+}
+
+// Handler pentru invitația după token (GET /invitations/:token, fără autentificare)
+func GetInvitationByTokenHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		invitation, err := s.FindInvitationByToken(c.Param("token"))
+		if err != nil {
+			respondInvitationError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"email": invitation.Email, "role": invitation.Role, "expires_at": invitation.ExpiresAt})
+	}
+}
+
+// Handler pentru acceptarea invitației (POST /invitations/accept, fără autentificare)
+func AcceptInvitationHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AcceptInvitationReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		user, err := s.AcceptInvitation(req.Token, req.Password)
+		if err != nil {
+			respondInvitationError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, user)
+	}
+}
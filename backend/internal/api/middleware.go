@@ -1,17 +1,42 @@
 package api
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"strings"
-	"orders/internal/config"
+	"orders/internal/models"
+	"orders/internal/service"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-func authMiddleware() gin.HandlerFunc {
+// authMiddleware verifică token-ul de acces; token-urile sesiunilor anulate (logout) se resping.
+// Integrările se autentifică cu cheia API din header-ul X-API-Key; fiecare cerere cu cheia se scrie în jurnalul de audit.
+func authMiddleware(s Service) gin.HandlerFunc {
 	return func(context *gin.Context) {
-		// Конфигурация
-		cfg := config.Load()
+		if secret := context.GetHeader("X-API-Key"); secret != "" {
+			key, err := s.AuthenticateAPIKey(secret, context.ClientIP())
+			if errors.Is(err, service.ErrAPIKeyInvalid) {
+				context.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+				context.Abort()
+				return
+			}
+			if err != nil {
+				context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				context.Abort()
+				return
+			}
+
+			// Cererea se face în numele administratorului care a emis cheia, cu permisiunile cheii
+			context.Set("user_id", key.CreatedByID)
+			context.Set("api_key", key)
+			context.Next()
+
+			if err := s.RecordAPIKeyRequest(key, context.Request.Method, context.Request.URL.Path, context.Writer.Status()); err != nil {
+				log.Printf("❌ API key %d audit failed: %v", key.ID, err)
+			}
+			return
+		}
 
 		tokenString := context.GetHeader("Authorization")
 		if tokenString == "" {
@@ -21,24 +46,91 @@ func authMiddleware() gin.HandlerFunc {
 		}
 		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(cfg.JWTSecret), nil
-		})
-		if err != nil || !token.Valid {
+		claims, err := s.Authenticate(tokenString)
+		if errors.Is(err, service.ErrSessionInvalid) {
 			context.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			context.Abort()
 			return
 		}
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			context.Abort()
+			return
+		}
+
+		context.Set("user_id", claims.UserID)
+		context.Set("session_id", claims.SessionID)
+		context.Set("role", claims.Role)
+		context.Next()
+	}
+}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			context.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+// roleMiddleware înlocuiește rolul din token cu rolul actual al utilizatorului, ca schimbarea rolului
+// să se aplice imediat; utilizatorul șters nu mai are acces
+func roleMiddleware(s Service) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if _, ok := context.Get("api_key"); ok {
+			context.Next()
+			return
+		}
+		role, err := s.UserRole(context.GetUint("user_id"))
+		if err != nil {
+			context.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
 			context.Abort()
 			return
 		}
+		context.Set("role", role)
+		context.Next()
+	}
+}
 
-		context.Set("user_id", uint(claims["user_id"].(float64)))
-		context.Set("role", claims["role"].(string))
+// hasPermission verifică permisiunea rolului utilizatorului sau, pentru cererile cu cheie API, a cheii
+func hasPermission(context *gin.Context, permission string) bool {
+	if key, ok := context.Get("api_key"); ok {
+		return key.(*models.APIKey).HasPermission(permission)
+	}
+	return service.HasPermission(context.GetString("role"), permission)
+}
+
+// requirePermission permite accesul doar rolurilor (cheilor API) care au permisiunea dată (matricea din service)
+func requirePermission(permission string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if !hasPermission(context, permission) {
+			context.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions", "permission": permission})
+			context.Abort()
+			return
+		}
+		context.Next()
+	}
+}
+
+// channelScopeMiddleware determină canalele de vânzări vizibile utilizatorului (după authMiddleware)
+func channelScopeMiddleware(s Service) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if key, ok := context.Get("api_key"); ok {
+			context.Set("channel_scope", s.APIKeyChannelScope(key.(*models.APIKey)))
+			context.Next()
+			return
+		}
+		scope, err := s.ChannelScope(context.GetUint("user_id"), context.GetString("role"))
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			context.Abort()
+			return
+		}
+		context.Set("channel_scope", scope)
+		context.Next()
+	}
+}
+
+// userOnly permite ruta doar utilizatorilor autentificați prin login (sesiunile și parola nu sunt ale cheii API)
+func userOnly() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if _, ok := context.Get("api_key"); ok {
+			context.JSON(http.StatusForbidden, gin.H{"error": "Not available with an API key"})
+			context.Abort()
+			return
+		}
 		context.Next()
 	}
-}
\ No newline at end of file
+}
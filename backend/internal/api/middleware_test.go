@@ -0,0 +1,209 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"orders/internal/models"
+	"orders/internal/service"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// fakeService - serviciul folosit de middleware-uri în teste; celelalte metode nu se apelează (interfața încorporată e nil)
+type fakeService struct {
+	Service
+	tokens   map[string]*service.AccessClaims // Token-ul de acces -> datele lui
+	roles    map[uint]string                  // Rolul actual al utilizatorilor
+	keys     map[string]*models.APIKey        // Cheile API după secret
+	requests []string                         // Cererile înregistrate cu cheia API
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{
+		tokens: map[string]*service.AccessClaims{},
+		roles:  map[uint]string{},
+		keys:   map[string]*models.APIKey{},
+	}
+}
+
+// addUser adaugă utilizatorul cu rolul dat și întoarce token-ul lui de acces
+func (s *fakeService) addUser(id uint, role string) string {
+	token := "token-" + role
+	s.tokens[token] = &service.AccessClaims{UserID: id, SessionID: id, Role: role}
+	s.roles[id] = role
+	return token
+}
+
+func (s *fakeService) Authenticate(accessToken string) (*service.AccessClaims, error) {
+	claims, ok := s.tokens[accessToken]
+	if !ok {
+		return nil, service.ErrSessionInvalid
+	}
+	return claims, nil
+}
+
+func (s *fakeService) UserRole(userID uint) (string, error) {
+	role, ok := s.roles[userID]
+	if !ok {
+		return "", gorm.ErrRecordNotFound
+	}
+	return role, nil
+}
+
+func (s *fakeService) ChannelScope(userID uint, role string) (models.ChannelScope, error) {
+	return models.ChannelScope{All: true}, nil
+}
+
+func (s *fakeService) AuthenticateAPIKey(secret, ip string) (*models.APIKey, error) {
+	key, ok := s.keys[secret]
+	if !ok {
+		return nil, service.ErrAPIKeyInvalid
+	}
+	return key, nil
+}
+
+func (s *fakeService) APIKeyChannelScope(key *models.APIKey) models.ChannelScope {
+	return models.ChannelScope{All: true}
+}
+
+func (s *fakeService) RecordAPIKeyRequest(key *models.APIKey, method, path string, status int) error {
+	s.requests = append(s.requests, method+" "+path)
+	return nil
+}
+
+// serve trimite cererea cu token-ul sau cheia API dată și întoarce răspunsul
+func serve(router *gin.Engine, method, path, token, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// forbiddenPermission întoarce permisiunea din răspunsul 403
+func forbiddenPermission(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Permission string `json:"permission"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response %q: %v", w.Body.String(), err)
+	}
+	return body.Permission
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newFakeService()
+	for i, role := range []string{models.RoleAdmin, models.RoleManager, models.RoleSalesRep, models.RoleWarehouse, models.RoleAccountant, models.RoleReadOnly} {
+		s.addUser(uint(i+1), role)
+	}
+	// Rolul s-a schimbat după emiterea token-ului: contează rolul actual
+	s.tokens["token-demoted"] = &service.AccessClaims{UserID: 20, SessionID: 20, Role: models.RoleAdmin}
+	s.roles[20] = models.RoleSalesRep
+	s.tokens["token-promoted"] = &service.AccessClaims{UserID: 21, SessionID: 21, Role: models.RoleSalesRep}
+	s.roles[21] = models.RoleAccountant
+	// Utilizatorul a fost șters după emiterea token-ului
+	s.tokens["token-deleted"] = &service.AccessClaims{UserID: 22, SessionID: 22, Role: models.RoleAdmin}
+	s.keys["key-reports"] = &models.APIKey{Permissions: []string{service.PermReportsRead}, CreatedByID: 1}
+	s.keys["key-orders"] = &models.APIKey{Permissions: []string{service.PermOrdersRead}, CreatedByID: 1}
+
+	router := gin.New()
+	router.GET("/reports", authMiddleware(s), roleMiddleware(s), requirePermission(service.PermReportsRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		token  string
+		apiKey string
+		want   int
+	}{
+		{"no credentials", "", "", http.StatusUnauthorized},
+		{"invalid token", "token-unknown", "", http.StatusUnauthorized},
+		{"admin", "token-admin", "", http.StatusOK},
+		{"manager", "token-manager", "", http.StatusOK},
+		{"accountant", "token-accountant", "", http.StatusOK},
+		{"read only", "token-read_only", "", http.StatusOK},
+		{"sales rep", "token-sales_rep", "", http.StatusForbidden},
+		{"warehouse", "token-warehouse", "", http.StatusForbidden},
+		{"role removed after login", "token-demoted", "", http.StatusForbidden},
+		{"role granted after login", "token-promoted", "", http.StatusOK},
+		{"deleted user", "token-deleted", "", http.StatusUnauthorized},
+		{"api key with permission", "", "key-reports", http.StatusOK},
+		{"api key without permission", "", "key-orders", http.StatusForbidden},
+		{"invalid api key", "", "key-unknown", http.StatusUnauthorized},
+		// Cheia API are prioritate: rolul utilizatorului nu-i adaugă permisiuni
+		{"api key with admin token", "token-admin", "key-orders", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodGet, "/reports", tt.token, tt.apiKey)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d (%s)", w.Code, tt.want, w.Body.String())
+			}
+			if w.Code == http.StatusForbidden {
+				if got := forbiddenPermission(t, w); got != service.PermReportsRead {
+					t.Errorf("permission = %q, want %q", got, service.PermReportsRead)
+				}
+			}
+		})
+	}
+
+	// Cererile cu cheia API se înregistrează, inclusiv cele respinse
+	if len(s.requests) != 3 {
+		t.Errorf("recorded api key requests = %q, want 3", s.requests)
+	}
+}
+
+// Rutele reale verifică permisiunea corectă; cererile respinse nu ajung la handler
+func TestRoutePermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newFakeService()
+	for i, role := range []string{models.RoleSalesRep, models.RoleWarehouse, models.RoleAccountant, models.RoleReadOnly} {
+		s.addUser(uint(i+1), role)
+	}
+	s.keys["key-clients"] = &models.APIKey{Permissions: []string{service.PermClientsRead}, CreatedByID: 1}
+	router := gin.New()
+	SetupRoutes(router, s)
+
+	tests := []struct {
+		method     string
+		path       string
+		token      string
+		apiKey     string
+		permission string
+	}{
+		{http.MethodGet, "/api/v1/reports/invalid-fiscal-ids", "token-sales_rep", "", service.PermReportsRead},
+		{http.MethodGet, "/api/v1/reports/invalid-fiscal-ids", "token-warehouse", "", service.PermReportsRead},
+		{http.MethodGet, "/api/v1/reports/invalid-fiscal-ids", "", "key-clients", service.PermReportsRead},
+		{http.MethodGet, "/api/v1/reports/vat", "token-sales_rep", "", service.PermReportsRead},
+		{http.MethodPost, "/api/v1/clients", "token-accountant", "", service.PermClientsEdit},
+		{http.MethodPost, "/api/v1/clients", "", "key-clients", service.PermClientsEdit},
+		{http.MethodDelete, "/api/v1/clients/1", "token-sales_rep", "", service.PermClientsDelete},
+		{http.MethodPost, "/api/v1/clients/merge", "token-sales_rep", "", service.PermClientsMerge},
+		{http.MethodPost, "/api/v1/orders", "token-warehouse", "", service.PermOrdersCreate},
+		{http.MethodPost, "/api/v1/orders/1/approve", "token-sales_rep", "", service.PermOrdersApprove},
+		{http.MethodPost, "/api/v1/payments", "token-read_only", "", service.PermPaymentsCreate},
+		{http.MethodPut, "/api/v1/users/1/role", "token-accountant", "", service.PermUsersManage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path+" "+tt.token+tt.apiKey, func(t *testing.T) {
+			w := serve(router, tt.method, tt.path, tt.token, tt.apiKey)
+			if w.Code != http.StatusForbidden {
+				t.Fatalf("status = %d, want %d (%s)", w.Code, http.StatusForbidden, w.Body.String())
+			}
+			if got := forbiddenPermission(t, w); got != tt.permission {
+				t.Errorf("permission = %q, want %q", got, tt.permission)
+			}
+		})
+	}
+}
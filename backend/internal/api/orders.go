@@ -1,19 +1,35 @@
 package api
 
 import (
+	"bytes"
+	"errors"
 	"net/http"
+	"orders/internal/documents"
 	"orders/internal/models"
+	"orders/internal/service"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 // Request pentru crearea comenzii (fără OwnerID, acesta vine din context)
 type OrderCreateRequest struct {
-	ClientID   uint    `json:"client_id" xml:"client_id" binding:"required"`
-	ContractID uint    `json:"contract_id" xml:"contract_id"`
-	TotalPrice float64 `json:"total_price" xml:"total_price" binding:"required"`
-	Status     string  `json:"status" xml:"status" binding:"required"`
+	ClientID    uint               `json:"client_id" xml:"client_id" binding:"required"`
+	ContractID  uint               `json:"contract_id" xml:"contract_id"`
+	PriceTypeID uint               `json:"price_type_id" xml:"price_type_id"`
+	Currency    string             `json:"currency" xml:"currency"` // Implicit moneda contractului sau MDL
+	Date        string             `json:"date" xml:"date"`         // Format YYYY-MM-DD, implicit data curentă
+	TotalPrice  float64            `json:"total_price" xml:"total_price" binding:"required"`
+	Status      string             `json:"status" xml:"status" binding:"required"`
+	Items       []OrderItemRequest `json:"items" xml:"items>item"`
+}
+
+// Poziția comenzii: prețul și TVA-ul se calculează pe server
+type OrderItemRequest struct {
+	ProductID uint    `json:"product_id" xml:"product_id" binding:"required"`
+	Quantity  float64 `json:"quantity" xml:"quantity" binding:"required"`
+	UnitID    uint    `json:"unit_id" xml:"unit_id"` // Unitatea de ambalare, implicit unitatea produsului
 }
 
 // Handler pentru crearea comenzii (POST /orders)
@@ -37,14 +53,51 @@ func CreateOrderHandler(s Service) gin.HandlerFunc {
 		userID := c.GetUint("user_id") // user_id din context, nu din JSON
 
 		order := &models.Order{
-			OwnerID:    userID,
-			ClientID:   req.ClientID,
-			ContractID: req.ContractID,
-			TotalPrice: req.TotalPrice,
-			Status:     req.Status,
+			OwnerID:     userID,
+			ClientID:    req.ClientID,
+			ContractID:  req.ContractID,
+			PriceTypeID: req.PriceTypeID,
+			Currency:    req.Currency,
+			TotalPrice:  req.TotalPrice,
+			Status:      req.Status,
+		}
+		if req.Date != "" {
+			date, err := time.Parse("2006-01-02", req.Date)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date, expected YYYY-MM-DD"})
+				return
+			}
+			order.Date = date
+		}
+		for _, item := range req.Items {
+			if item.ProductID == 0 || item.Quantity <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "each item needs product_id and a positive quantity"})
+				return
+			}
+			order.OrderItems = append(order.OrderItems, models.OrderItem{
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+				UnitID:    item.UnitID,
+			})
 		}
 
 		if err := s.CreateOrder(userID, order); err != nil {
+			if errors.Is(err, service.ErrInvalidCurrency) || errors.Is(err, service.ErrCurrencyMismatch) || errors.Is(err, service.ErrExchangeRateNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, service.ErrContractNotActive) || errors.Is(err, service.ErrOrderOutsideContract) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, service.ErrContractClientMismatch) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if isNotFound(err) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Client, contract or product not found"})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
@@ -92,3 +145,59 @@ func GetOrderHandler(s Service) gin.HandlerFunc {
 		c.JSON(http.StatusOK, order)
 	}
 }
+
+// Handler pentru aprobarea comenzii (POST /orders/:id/approve)
+func ApproveOrderHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		order, err := s.ApproveOrder(c.GetUint("user_id"), uint(id))
+		if err != nil {
+			switch {
+			case isNotFound(err):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			case errors.Is(err, service.ErrOrderNotPending):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, order)
+	}
+}
+
+// Handler pentru documentul tipăribil al comenzii (GET /orders/:id/print)
+func PrintOrderHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetUint("user_id")
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		order, err := s.FindOrderDocument(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+
+		// Verifică dacă utilizatorul este owner-ul comenzii
+		if order.OwnerID != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized"})
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := documents.RenderOrderHTML(&buf, order); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+	}
+}
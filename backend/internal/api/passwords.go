@@ -0,0 +1,90 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"orders/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cererea de schimbare a parolei
+type ChangePasswordReq struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// Cererea linkului de resetare a parolei
+type ForgotPasswordReq struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// Cererea de setare a parolei noi după token-ul din scrisoare
+type ResetPasswordReq struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// respondPasswordError întoarce statutul HTTP pentru erorile parolei
+func respondPasswordError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrPasswordTooShort), errors.Is(err, service.ErrInvalidEmail),
+		errors.Is(err, service.ErrResetTokenInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCurrentPasswordInvalid):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResetTokenExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Handler pentru schimbarea parolei (PUT /password); celelalte sesiuni ale utilizatorului se închid
+func ChangePasswordHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ChangePasswordReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.ChangePassword(c.GetUint("user_id"), c.GetUint("session_id"), req.CurrentPassword, req.NewPassword); err != nil {
+			respondPasswordError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// Handler pentru cererea linkului de resetare a parolei (POST /password/forgot, fără autentificare).
+// Răspunsul este același pentru emailurile cu și fără cont.
+func ForgotPasswordHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ForgotPasswordReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.RequestPasswordReset(req.Email, c.ClientIP()); err != nil {
+			respondPasswordError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"message": "If the account exists, a password reset link has been sent"})
+	}
+}
+
+// Handler pentru setarea parolei noi (POST /password/reset, fără autentificare)
+func ResetPasswordHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ResetPasswordReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.ResetPassword(req.Token, req.NewPassword); err != nil {
+			respondPasswordError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"orders/internal/documents"
+	"orders/internal/models"
+	"orders/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentReq struct {
+	Direction   string  `json:"direction" xml:"direction" binding:"required"` // incoming, outgoing
+	Date        string  `json:"date" xml:"date"`                              // Format YYYY-MM-DD, implicit data curentă
+	ClientID    uint    `json:"client_id" xml:"client_id" binding:"required"`
+	ContractID  *uint   `json:"contract_id" xml:"contract_id"`
+	Amount      float64 `json:"amount" xml:"amount" binding:"required"`
+	IncomeTaxID *uint   `json:"income_tax_id" xml:"income_tax_id"` // Opțional, altfel din contract sau tipul clientului
+	Description string  `json:"description" xml:"description"`
+}
+
+// Handler pentru înregistrarea plăților (POST /payments)
+func CreatePaymentHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requests, err := ParseBody[PaymentReq](c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format"})
+			return
+		}
+
+		userID := c.GetUint("user_id")
+
+		created := make([]*models.Payment, 0)
+		skipped := make([]map[string]string, 0)
+		for _, req := range requests {
+			payment := &models.Payment{
+				Direction:   req.Direction,
+				ClientID:    req.ClientID,
+				ContractID:  req.ContractID,
+				Amount:      req.Amount,
+				IncomeTaxID: req.IncomeTaxID,
+				Description: req.Description,
+			}
+			if req.Date != "" {
+				date, err := time.Parse("2006-01-02", req.Date)
+				if err != nil {
+					skipped = append(skipped, map[string]string{"client_id": strconv.FormatUint(uint64(req.ClientID), 10), "reason": "invalid_date"})
+					continue
+				}
+				payment.Date = date
+			}
+
+			// Rândurile cu erori (inclusiv ale bazei de date) se raportează în skipped, ca la celelalte liste;
+			// plățile salvate deja rămân în created
+			if err := s.CreatePayment(userID, payment); err != nil {
+				var reason string
+				switch {
+				case isNotFound(err):
+					reason = "client_contract_or_income_tax_not_found"
+				case errors.Is(err, service.ErrInvalidPaymentDirection), errors.Is(err, service.ErrInvalidPaymentAmount),
+					errors.Is(err, service.ErrContractClientMismatch), errors.Is(err, service.ErrIncomeTaxNotApplicable):
+					reason = err.Error()
+				default:
+					// Textul erorilor bazei de date nu ajunge în răspuns
+					log.Printf("❌ Payment for client %d failed: %v", req.ClientID, err)
+					reason = "internal_error"
+				}
+				skipped = append(skipped, map[string]string{"client_id": strconv.FormatUint(uint64(req.ClientID), 10), "reason": reason})
+				continue
+			}
+			created = append(created, payment)
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"created": created, "skipped": skipped})
+	}
+}
+
+// Handler pentru obținerea plății după id (GET /payments/:id)
+func GetPaymentHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		payment, err := s.FindPaymentByID(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+			return
+		}
+		c.JSON(http.StatusOK, payment)
+	}
+}
+
+// Handler pentru documentul tipăribil al plății (GET /payments/:id/print)
+func PrintPaymentHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		payment, err := s.FindPaymentByID(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := documents.RenderPaymentHTML(&buf, payment); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+	}
+}
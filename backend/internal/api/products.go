@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"io"
 	"net/http"
 	"orders/internal/models"
+	"orders/internal/service"
+	"orders/internal/validation"
 	"strconv"
 	"strings"
 
@@ -15,12 +18,20 @@ import (
 
 // Handler pentru crearea produsului
 type ProductRequest struct {
-	Name           string  `json:"name" xml:"name" binding:"required"`
-	Price          float64 `json:"price" xml:"price"`
-	Description    string  `json:"description" xml:"description"`
-	ProductGroupID uint    `json:"product_group_id" xml:"product_group_id" binding:"required"`
-	UnitID         uint    `json:"unit_id" xml:"unit_id" binding:"required"`
-	VatTaxID       uint    `json:"vat_tax_id" xml:"vat_tax_id" binding:"required"`
+	Name           string           `json:"name" xml:"name" binding:"required"`
+	Price          float64          `json:"price" xml:"price"`
+	Description    string           `json:"description" xml:"description"`
+	ProductGroupID uint             `json:"product_group_id" xml:"product_group_id" binding:"required"`
+	UnitID         uint             `json:"unit_id" xml:"unit_id" binding:"required"`
+	VatTaxID       uint             `json:"vat_tax_id" xml:"vat_tax_id" binding:"required"`
+	SKU            string           `json:"sku" xml:"sku"`
+	Barcodes       []BarcodeRequest `json:"barcodes" xml:"barcodes>barcode"`
+}
+
+// Cod de bare pentru o unitate de ambalare a produsului
+type BarcodeRequest struct {
+	Barcode string `json:"barcode" xml:"barcode" binding:"required"`
+	UnitID  uint   `json:"unit_id" xml:"unit_id" binding:"required"`
 }
 
 func CreateProductHandler(s Service) gin.HandlerFunc {
@@ -114,13 +125,29 @@ func CreateProductHandler(s Service) gin.HandlerFunc {
 				continue
 			}
 
+			sku := strings.TrimSpace(req.SKU)
+			if sku != "" {
+				if _, err := s.FindProductBySKU(sku); err == nil {
+					skipped = append(skipped, map[string]string{"name": req.Name, "reason": "duplicate_sku"})
+					continue
+				}
+			}
+
+			barcodes, reason := buildProductBarcodes(s, req.UnitID, req.Barcodes)
+			if reason != "" {
+				skipped = append(skipped, map[string]string{"name": req.Name, "reason": reason})
+				continue
+			}
+
 			product := &models.Product{
 				Name:           req.Name,
+				SKU:            sku,
 				Price:          req.Price,
 				Description:    req.Description,
 				ProductGroupID: req.ProductGroupID,
 				UnitID:         req.UnitID,
 				VatTaxID:       req.VatTaxID,
+				Barcodes:       barcodes,
 			}
 
 			if err := s.CreateProduct(product); err != nil {
@@ -154,3 +181,125 @@ func GetProductByIDHandler(s Service) gin.HandlerFunc {
 		c.JSON(http.StatusOK, product)
 	}
 }
+
+// buildProductBarcodes validează codurile de bare din cerere și întoarce motivul respingerii, dacă există.
+// Dacă unitatea nu este specificată, codul se atribuie unității de bază a produsului.
+func buildProductBarcodes(s Service, baseUnitID uint, reqs []BarcodeRequest) ([]models.ProductBarcode, string) {
+	barcodes := make([]models.ProductBarcode, 0, len(reqs))
+	seenCodes := make(map[string]bool)
+	seenUnits := make(map[uint]bool)
+
+	for _, req := range reqs {
+		code := validation.NormalizeBarcode(req.Barcode)
+		if !validation.IsValidEAN(code) {
+			return nil, "invalid_barcode"
+		}
+
+		unitID := req.UnitID
+		if unitID == 0 {
+			unitID = baseUnitID
+		}
+		if unitID != baseUnitID {
+			if _, err := s.FindUnitByID(unitID); err != nil {
+				return nil, "invalid_barcode_unit_id"
+			}
+		}
+
+		// Un singur cod pe unitate de ambalare și coduri unice în toată baza
+		if seenCodes[code] {
+			return nil, "duplicate_barcode"
+		}
+		if seenUnits[unitID] {
+			return nil, "duplicate_barcode_unit"
+		}
+		if _, err := s.FindProductBarcode(code); err == nil {
+			return nil, "duplicate_barcode"
+		}
+		seenCodes[code] = true
+		seenUnits[unitID] = true
+
+		barcodes = append(barcodes, models.ProductBarcode{Barcode: code, UnitID: unitID})
+	}
+	return barcodes, ""
+}
+
+// AddProductBarcodeHandler gestionează POST /products/:id/barcodes
+func AddProductBarcodeHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+			return
+		}
+
+		product, err := s.FindProductByID(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+
+		requests, err := ParseBody[BarcodeRequest](c)
+		if err != nil || len(requests) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format"})
+			return
+		}
+
+		// Unitățile care au deja un cod de bare nu pot primi încă unul
+		taken := make(map[uint]bool)
+		for _, b := range product.Barcodes {
+			taken[b.UnitID] = true
+		}
+
+		created := make([]models.ProductBarcode, 0)
+		skipped := make([]map[string]string, 0)
+		for _, req := range requests {
+			barcodes, reason := buildProductBarcodes(s, product.UnitID, []BarcodeRequest{req})
+			if reason == "" && taken[barcodes[0].UnitID] {
+				reason = "duplicate_barcode_unit"
+			}
+			if reason != "" {
+				skipped = append(skipped, map[string]string{"barcode": req.Barcode, "reason": reason})
+				continue
+			}
+
+			barcode := barcodes[0]
+			barcode.ProductID = product.ID
+			if err := s.CreateProductBarcode(&barcode); err != nil {
+				skipped = append(skipped, map[string]string{"barcode": req.Barcode, "reason": err.Error()})
+				continue
+			}
+			taken[barcode.UnitID] = true
+			created = append(created, barcode)
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"created": created, "skipped": skipped})
+	}
+}
+
+// GetProductByBarcodeHandler gestionează GET /products/by-barcode/:code
+// Parametrul opțional ?price_type_id= alege prețul din PriceProduct în locul prețului de bază.
+func GetProductByBarcodeHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var priceTypeID uint64
+		if v := c.Query("price_type_id"); v != "" {
+			parsed, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid price_type_id"})
+				return
+			}
+			priceTypeID = parsed
+		}
+
+		res, err := s.FindProductByBarcode(c.Param("code"), uint(priceTypeID))
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidBarcode) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_barcode"})
+				return
+			}
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, res)
+	}
+}
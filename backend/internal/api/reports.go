@@ -0,0 +1,125 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"orders/internal/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePeriod citește parametrii ?from=YYYY-MM-DD&to=YYYY-MM-DD; implicit luna curentă
+func parsePeriod(c *gin.Context) (time.Time, time.Time, bool) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, -1)
+
+	if v := c.Query("from"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid 'from', expected YYYY-MM-DD"})
+			return from, to, false
+		}
+		from = parsed
+	}
+	if v := c.Query("to"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid 'to', expected YYYY-MM-DD"})
+			return from, to, false
+		}
+		to = parsed
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "'to' must not be before 'from'"})
+		return from, to, false
+	}
+	return from, to, true
+}
+
+// Handler pentru raportul TVA pe categorii (GET /reports/vat?from=&to=)
+// Livrările scutite și cele cu cota zero apar pe rânduri separate.
+func VatReportHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from, to, ok := parsePeriod(c)
+		if !ok {
+			return
+		}
+
+		rows, err := s.VatReport(from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Totalurile se pot aduna doar în moneda de bază; rândurile păstrează și sumele în valută
+		var netBase, vatBase, totalBase float64
+		for _, row := range rows {
+			netBase += row.NetBase
+			vatBase += row.VatBase
+			totalBase += row.TotalBase
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"from": from.Format("2006-01-02"),
+			"to":   to.Format("2006-01-02"),
+			"rows": rows,
+			"totals": gin.H{
+				"currency":   models.BaseCurrency,
+				"net_base":   math.Round(netBase*100) / 100,
+				"vat_base":   math.Round(vatBase*100) / 100,
+				"total_base": math.Round(totalBase*100) / 100,
+			},
+		})
+	}
+}
+
+// Handler pentru raportul impozitului pe venit reținut (GET /reports/income-tax?from=&to=)
+// Rândurile sunt grupate pe lună și beneficiar, pentru declarațiile fiscale.
+func IncomeTaxReportHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from, to, ok := parsePeriod(c)
+		if !ok {
+			return
+		}
+
+		rows, err := s.IncomeTaxReport(from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		var amount, withheld, net float64
+		for _, row := range rows {
+			amount += row.Amount
+			withheld += row.WithheldAmount
+			net += row.NetAmount
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"from": from.Format("2006-01-02"),
+			"to":   to.Format("2006-01-02"),
+			"rows": rows,
+			"totals": gin.H{
+				"amount":          math.Round(amount*100) / 100,
+				"withheld_amount": math.Round(withheld*100) / 100,
+				"net_amount":      math.Round(net*100) / 100,
+			},
+		})
+	}
+}
+
+// Handler pentru raportul clienților cu cod fiscal invalid (GET /reports/invalid-fiscal-ids, doar admin)
+// Companiile trebuie să aibă IDNO, persoanele fizice IDNP (13 cifre, cu cifra de control).
+func InvalidFiscalIDReportHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rows, err := s.InvalidFiscalIDReport()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"rows": rows, "count": len(rows)})
+	}
+}
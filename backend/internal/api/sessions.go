@@ -0,0 +1,107 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"orders/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cererea de reînnoire a token-ului de acces
+type RefreshReq struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Handler pentru reînnoirea token-ului de acces (POST /refresh, fără autentificare).
+// Răspunsul conține și refresh token-ul nou; cel vechi nu mai este acceptat.
+func RefreshSessionHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RefreshReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		tokens, err := s.RefreshSession(req.RefreshToken)
+		if err != nil {
+			if errors.Is(err, service.ErrSessionInvalid) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, tokens)
+	}
+}
+
+// Handler pentru sesiunile active ale utilizatorului (GET /sessions)
+func ListSessionsHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessions, err := s.FindSessions(c.GetUint("user_id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		current := c.GetUint("session_id")
+		res := make([]gin.H, 0, len(sessions))
+		for _, session := range sessions {
+			res = append(res, gin.H{
+				"id":           session.ID,
+				"created_at":   session.CreatedAt,
+				"last_used_at": session.LastUsedAt,
+				"expires_at":   session.ExpiresAt,
+				"user_agent":   session.UserAgent,
+				"ip":           session.IP,
+				"current":      session.ID == current,
+			})
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+// Handler pentru ieșirea din sesiunea curentă (POST /logout)
+func LogoutHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := s.Logout(c.GetUint("session_id")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// Handler pentru ieșirea de pe toate dispozitivele (POST /logout/all)
+func LogoutAllHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetUint("user_id")
+		revoked, err := s.LogoutAll(userID, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+	}
+}
+
+// Handler pentru anularea tuturor sesiunilor utilizatorului de către administrator (DELETE /users/:id/sessions)
+func LogoutUserHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		revoked, err := s.LogoutAll(c.GetUint("user_id"), uint(id))
+		if err != nil {
+			if errors.Is(err, service.ErrUserNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+	}
+}
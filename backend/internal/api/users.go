@@ -0,0 +1,67 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"orders/internal/service"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cererea de atribuire a rolului
+type UserRoleReq struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// Handler pentru matricea rolurilor și permisiunilor (GET /roles)
+func ListRolesHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.FindRoles())
+	}
+}
+
+// Handler pentru lista utilizatorilor cu rolurile și canalele lor (GET /users?role=)
+func ListUsersHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		users, err := s.FindUsers(strings.TrimSpace(c.Query("role")))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, users)
+	}
+}
+
+// Handler pentru atribuirea rolului (PUT /users/:id/role, {"role": "manager"})
+func AssignUserRoleHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		var req UserRoleReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		user, err := s.AssignUserRole(c.GetUint("user_id"), uint(id), req.Role)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrInvalidRole):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case errors.Is(err, service.ErrUserNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case errors.Is(err, service.ErrLastAdmin):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, user)
+	}
+}
@@ -3,11 +3,13 @@ package api
 import (
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // ParseBody este pentru parsarea cererilor API.
@@ -59,3 +61,8 @@ func ParseBody[T any](c *gin.Context) ([]T, error) {
 
 	return nil, err
 }
+
+// isNotFound raportează dacă eroarea provine dintr-o înregistrare inexistentă
+func isNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"orders/internal/models"
+	"orders/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Versiune nouă a ratei TVA
+type VatTaxRateReq struct {
+	Rate      float64 `json:"rate" xml:"rate"`
+	ValidFrom string  `json:"valid_from" xml:"valid_from" binding:"required"` // Format YYYY-MM-DD
+}
+
+// Handler pentru lista taxelor TVA cu versiunile ratelor (GET /vat-taxes)
+func GetVatTaxesHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		vatTaxes, err := s.FindAllVatTaxes()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, vatTaxes)
+	}
+}
+
+// Handler pentru adăugarea unei versiuni datate a ratei (POST /vat-taxes/:id/rates)
+func CreateVatTaxRateHandler(s Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		requests, err := ParseBody[VatTaxRateReq](c)
+		if err != nil || len(requests) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format"})
+			return
+		}
+
+		created := make([]*models.VatTaxRate, 0)
+		skipped := make([]map[string]string, 0)
+		for _, req := range requests {
+			validFrom, err := time.Parse("2006-01-02", req.ValidFrom)
+			if err != nil {
+				skipped = append(skipped, map[string]string{"valid_from": req.ValidFrom, "reason": "invalid_date"})
+				continue
+			}
+
+			rate := &models.VatTaxRate{VatTaxID: uint(id), Rate: req.Rate, ValidFrom: validFrom}
+			if err := s.AddVatTaxRate(rate); err != nil {
+				reason := err.Error()
+				if isNotFound(err) {
+					reason = "vat_tax_not_found"
+				} else if !errors.Is(err, service.ErrInvalidVatRate) {
+					reason = "duplicate_or_invalid: " + reason
+				}
+				skipped = append(skipped, map[string]string{"valid_from": req.ValidFrom, "reason": reason})
+				continue
+			}
+			created = append(created, rate)
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"created": created, "skipped": skipped})
+	}
+}
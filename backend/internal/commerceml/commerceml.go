@@ -0,0 +1,225 @@
+// Package commerceml citește și scrie fișierele de schimb CommerceML 2 folosite de 1C:Enterprise.
+// Suportă subsetul necesar: clasificatorul (grupe, contrapartide), catalogul de produse,
+// pachetul de oferte (tipuri de preț, prețuri) și documentele "Заказ товара" pentru export.
+package commerceml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Versiunea și spațiul de nume ale schemei scrise în fișierele exportate
+const (
+	SchemaVersion = "2.05"
+	Namespace     = "urn:1C.ru:commerceml_2"
+)
+
+// ErrInvalidMessage - fișierul nu este un XML CommerceML valid
+var ErrInvalidMessage = errors.New("invalid CommerceML")
+
+// Message - rădăcina fișierului <КоммерческаяИнформация>
+type Message struct {
+	XMLName       xml.Name     `xml:"КоммерческаяИнформация"`
+	Xmlns         string       `xml:"xmlns,attr,omitempty"`
+	SchemaVersion string       `xml:"ВерсияСхемы,attr"`
+	GeneratedAt   string       `xml:"ДатаФормирования,attr"`
+	Classifier    *Classifier  `xml:"Классификатор"`
+	Catalog       *Catalog     `xml:"Каталог"`
+	Offers        *OfferPacket `xml:"ПакетПредложений"`
+	Documents     []Document   `xml:"Документ"`
+}
+
+// Classifier - <Классификатор>: grupele de produse și contrapartidele
+type Classifier struct {
+	ID             string         `xml:"Ид"`
+	Name           string         `xml:"Наименование"`
+	Groups         []Group        `xml:"Группы>Группа"`
+	Counterparties []Counterparty `xml:"Контрагенты>Контрагент"`
+}
+
+// Group - <Группа>, poate conține subgrupe
+type Group struct {
+	ID     string  `xml:"Ид"`
+	Name   string  `xml:"Наименование"`
+	Groups []Group `xml:"Группы>Группа"`
+}
+
+// Flatten întoarce grupele și toate subgrupele lor într-o singură listă
+func Flatten(groups []Group) []Group {
+	var res []Group
+	for _, g := range groups {
+		res = append(res, Group{ID: g.ID, Name: g.Name})
+		res = append(res, Flatten(g.Groups)...)
+	}
+	return res
+}
+
+// Counterparty - <Контрагент>
+type Counterparty struct {
+	ID           string    `xml:"Ид"`
+	Name         string    `xml:"Наименование"`
+	Role         string    `xml:"Роль,omitempty"`
+	FullName     string    `xml:"ПолноеНаименование,omitempty"`
+	OfficialName string    `xml:"ОфициальноеНаименование,omitempty"` // Doar pentru persoane juridice
+	FiscalID     string    `xml:"ИНН,omitempty"`
+	LegalAddress *Address  `xml:"ЮридическийАдрес,omitempty"`
+	Address      *Address  `xml:"Адрес,omitempty"`
+	Contacts     *Contacts `xml:"Контакты,omitempty"`
+	DeletionMark bool      `xml:"ПометкаУдаления,omitempty"`
+}
+
+// Address - adresa (se folosește doar reprezentarea textuală)
+type Address struct {
+	Text string `xml:"Представление"`
+}
+
+// Contacts - <Контакты>
+type Contacts struct {
+	Items []Contact `xml:"Контакт"`
+}
+
+// Contact - <Контакт>: telefon, email etc.
+type Contact struct {
+	Type  string `xml:"Тип"`
+	Value string `xml:"Значение"`
+}
+
+// IsLegalEntity - contrapartida are atributele unei persoane juridice
+func (c Counterparty) IsLegalEntity() bool {
+	return c.OfficialName != "" || c.LegalAddress != nil
+}
+
+// AddressText întoarce adresa poștală sau, dacă lipsește, pe cea juridică
+func (c Counterparty) AddressText() string {
+	if c.Address != nil && strings.TrimSpace(c.Address.Text) != "" {
+		return strings.TrimSpace(c.Address.Text)
+	}
+	if c.LegalAddress != nil {
+		return strings.TrimSpace(c.LegalAddress.Text)
+	}
+	return ""
+}
+
+// ContactValue întoarce prima valoare a contactului al cărui tip conține unul din cuvintele date
+func (c Counterparty) ContactValue(kinds ...string) string {
+	if c.Contacts == nil {
+		return ""
+	}
+	for _, contact := range c.Contacts.Items {
+		t := strings.ToLower(contact.Type)
+		for _, kind := range kinds {
+			if strings.Contains(t, kind) {
+				return strings.TrimSpace(contact.Value)
+			}
+		}
+	}
+	return ""
+}
+
+// Catalog - <Каталог>: produsele
+type Catalog struct {
+	ID           string    `xml:"Ид"`
+	ClassifierID string    `xml:"ИдКлассификатора"`
+	Name         string    `xml:"Наименование"`
+	OnlyChanges  bool      `xml:"СодержитТолькоИзменения,attr"`
+	Products     []Product `xml:"Товары>Товар"`
+}
+
+// Product - <Товар>
+type Product struct {
+	ID           string    `xml:"Ид"`
+	SKU          string    `xml:"Артикул,omitempty"`
+	Barcode      string    `xml:"Штрихкод,omitempty"`
+	Name         string    `xml:"Наименование"`
+	BaseUnit     *Unit     `xml:"БазоваяЕдиница,omitempty"`
+	GroupIDs     []string  `xml:"Группы>Ид,omitempty"`
+	Description  string    `xml:"Описание,omitempty"`
+	TaxRates     []TaxRate `xml:"СтавкиНалогов>СтавкаНалога,omitempty"`
+	DeletionMark bool      `xml:"ПометкаУдаления,omitempty"`
+}
+
+// Unit - unitatea de măsură (textul este denumirea scurtă, ex: "шт", "buc")
+type Unit struct {
+	Code     string `xml:"Код,attr,omitempty"`
+	FullName string `xml:"НаименованиеПолное,attr,omitempty"`
+	Name     string `xml:",chardata"`
+}
+
+// TaxRate - <СтавкаНалога>; Rate este "20", "8", "0" sau "Без налога" / "Без НДС"
+type TaxRate struct {
+	Name string `xml:"Наименование"`
+	Rate string `xml:"Ставка"`
+}
+
+// OfferPacket - <ПакетПредложений>: tipurile de preț și prețurile
+type OfferPacket struct {
+	ID          string      `xml:"Ид"`
+	CatalogID   string      `xml:"ИдКаталога"`
+	OnlyChanges bool        `xml:"СодержитТолькоИзменения,attr"`
+	PriceTypes  []PriceType `xml:"ТипыЦен>ТипЦены"`
+	Offers      []Offer     `xml:"Предложения>Предложение"`
+}
+
+// PriceType - <ТипЦены>
+type PriceType struct {
+	ID       string `xml:"Ид"`
+	Name     string `xml:"Наименование"`
+	Currency string `xml:"Валюта"`
+}
+
+// Offer - <Предложение>: prețurile unui produs
+type Offer struct {
+	ID     string  `xml:"Ид"` // Ид-ul produsului, eventual cu "#<caracteristică>"
+	Name   string  `xml:"Наименование"`
+	Prices []Price `xml:"Цены>Цена"`
+}
+
+// Price - <Цена>
+type Price struct {
+	PriceTypeID string `xml:"ИдТипаЦены"`
+	UnitPrice   string `xml:"ЦенаЗаЕдиницу"`
+	Currency    string `xml:"Валюта"`
+	Unit        string `xml:"Единица"`
+	Coefficient string `xml:"Коэффициент"`
+}
+
+// ProductID întoarce Ид-ul produsului fără caracteristică ("guid#guid" -> "guid")
+func (o Offer) ProductID() string {
+	id, _, _ := strings.Cut(o.ID, "#")
+	return id
+}
+
+// Parse citește un fișier CommerceML (import.xml, offers.xml sau un fișier combinat).
+// Fișierele din versiunile mai vechi ale 1C pot fi în windows-1251.
+func Parse(r io.Reader) (*Message, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	var msg Message
+	if err := decoder.Decode(&msg); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMessage, err)
+	}
+	return &msg, nil
+}
+
+// ParseNumber citește un număr scris de 1C ("1 234,50", "12.5")
+func ParseNumber(s string) (float64, error) {
+	s = strings.NewReplacer(" ", "", " ", "", ",", ".").Replace(strings.TrimSpace(s))
+	return strconv.ParseFloat(s, 64)
+}
+
+// ParseTaxRate întoarce rata în procente și dacă produsul este scutit ("Без налога", "Без НДС")
+func ParseTaxRate(s string) (rate float64, exempt bool, err error) {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "%"))
+	if strings.HasPrefix(strings.ToLower(s), "без") {
+		return 0, true, nil
+	}
+	rate, err = ParseNumber(s)
+	return rate, false, err
+}
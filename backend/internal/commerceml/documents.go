@@ -0,0 +1,101 @@
+package commerceml
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Valorile folosite de 1C pentru comenzile clienților
+const (
+	OperationOrder = "Заказ товара"
+	RoleSeller     = "Продавец"
+	RoleBuyer      = "Покупатель"
+	TaxVAT         = "НДС"
+)
+
+// Document - <Документ> (comanda clientului)
+type Document struct {
+	ID             string         `xml:"Ид"`
+	Number         string         `xml:"Номер"`
+	Date           string         `xml:"Дата"`
+	Time           string         `xml:"Время,omitempty"`
+	Operation      string         `xml:"ХозОперация"`
+	Role           string         `xml:"Роль"`
+	Currency       string         `xml:"Валюта"`
+	Rate           string         `xml:"Курс"`
+	Amount         string         `xml:"Сумма"`
+	Counterparties []Counterparty `xml:"Контрагенты>Контрагент"`
+	Comment        string         `xml:"Комментарий,omitempty"`
+	Taxes          *Taxes         `xml:"Налоги,omitempty"`
+	Items          []DocumentItem `xml:"Товары>Товар"`
+	Requisites     *Requisites    `xml:"ЗначенияРеквизитов,omitempty"`
+	DeletionMark   bool           `xml:"ПометкаУдаления,omitempty"`
+}
+
+// DocumentItem - <Товар> din document
+type DocumentItem struct {
+	ID        string `xml:"Ид"`
+	SKU       string `xml:"Артикул,omitempty"`
+	Name      string `xml:"Наименование"`
+	BaseUnit  *Unit  `xml:"БазоваяЕдиница,omitempty"`
+	UnitPrice string `xml:"ЦенаЗаЕдиницу"`
+	Quantity  string `xml:"Количество"`
+	Amount    string `xml:"Сумма"`
+	Unit      string `xml:"Единица,omitempty"`
+	Taxes     *Taxes `xml:"Налоги,omitempty"`
+}
+
+// Taxes - <Налоги>
+type Taxes struct {
+	Items []Tax `xml:"Налог"`
+}
+
+// Tax - <Налог>; IncludedInAmount = true: suma conține deja taxa
+type Tax struct {
+	Name             string `xml:"Наименование"`
+	IncludedInAmount bool   `xml:"УчтеноВСумме"`
+	Amount           string `xml:"Сумма"`
+	Rate             string `xml:"Ставка,omitempty"`
+}
+
+// Requisites - <ЗначенияРеквизитов>
+type Requisites struct {
+	Items []RequisiteValue `xml:"ЗначениеРеквизита"`
+}
+
+// RequisiteValue - <ЗначениеРеквизита>: atribut suplimentar (ex: statutul comenzii)
+type RequisiteValue struct {
+	Name  string `xml:"Наименование"`
+	Value string `xml:"Значение"`
+}
+
+// FormatMoney scrie o sumă în formatul așteptat de 1C (punct zecimal, două zecimale)
+func FormatMoney(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// FormatQuantity scrie cantitatea fără zerouri inutile
+func FormatQuantity(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// WriteDocuments scrie fișierul <КоммерческаяИнформация> cu documentele date (UTF-8)
+func WriteDocuments(w io.Writer, generatedAt time.Time, documents []Document) error {
+	msg := Message{
+		Xmlns:         Namespace,
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   generatedAt.Format("2006-01-02T15:04:05"),
+		Documents:     documents,
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "\t")
+	if err := encoder.Encode(msg); err != nil {
+		return err
+	}
+	return encoder.Flush()
+}
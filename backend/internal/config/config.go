@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -16,7 +18,45 @@ type Config struct {
 	DBSSLMode  	string
 	JWTSecret  	string
 	DSN        	string 
-	Allowsignup bool
+	AdminEmail    string // Primul administrator, creat la pornire dacă nu există niciun administrator
+	AdminPassword string
+	InvitationTTLHours int // Valabilitatea invitației, în ore
+	AccessTokenTTLMinutes int // Valabilitatea token-ului de acces, în minute
+	RefreshTokenTTLDays int // Valabilitatea refresh token-ului (sesiunii), în zile
+	PasswordResetTTLMinutes int // Valabilitatea linkului de resetare a parolei, în minute
+	PasswordResetCooldownMinutes int // Intervalul minim între două scrisori de resetare pentru același utilizator, în minute
+	AppURL string // Adresa aplicației web, pentru linkurile din scrisori
+	StoragePath string // Directorul pentru fișierele atașate
+	MaxUploadMB int64  // Dimensiunea maximă a unui fișier încărcat, în MB
+	DuplicateScanHours int // Intervalul detectării clienților duplicați, în ore (0 - dezactivată)
+	ContractCheckHours int // Intervalul verificării expirării contractelor, în ore (0 - dezactivată)
+	ContractExpiryNoticeDays int // Cu câte zile înainte de expirare se anunță ownerul contractului (0 - nu se anunță)
+	Organization Organization // Rechizitele organizației noastre (pentru contractele generate)
+	Mail Mail // Trimiterea scrisorilor
+}
+
+// Organization - rechizitele organizației, din variabilele ORG_*
+type Organization struct {
+	Name       string // ORG_NAME
+	FiscalCode string // ORG_FISCAL_CODE
+	Address    string // ORG_ADDRESS
+	Phone      string // ORG_PHONE
+	Email      string // ORG_EMAIL
+	IBAN       string // ORG_IBAN
+	BIC        string // ORG_BIC
+	BankName   string // ORG_BANK_NAME
+	Director   string // ORG_DIRECTOR
+}
+
+// Mail - trimiterea scrisorilor, din variabilele MAIL_* și SMTP_*
+type Mail struct {
+	Driver       string // MAIL_DRIVER: "smtp" sau "outbox" (implicit) - scrisorile se scriu în OutboxPath
+	From         string // MAIL_FROM
+	OutboxPath   string // MAIL_OUTBOX_PATH
+	SMTPHost     string // SMTP_HOST
+	SMTPPort     int    // SMTP_PORT
+	SMTPUsername string // SMTP_USERNAME
+	SMTPPassword string // SMTP_PASSWORD
 }
 
 func Load() Config {
@@ -30,7 +70,82 @@ func Load() Config {
 		DBName:     os.Getenv("DB_NAME"),
 		DBSSLMode:  os.Getenv("DB_SSLMODE"),
 		JWTSecret:  os.Getenv("JWT_SECRET"),
-		Allowsignup: os.Getenv("ALLOWSIGNUP") == "true",
+		AdminEmail:    os.Getenv("ADMIN_EMAIL"),
+		AdminPassword: os.Getenv("ADMIN_PASSWORD"),
+		InvitationTTLHours: 72,
+		AccessTokenTTLMinutes: 15,
+		RefreshTokenTTLDays: 30,
+		PasswordResetTTLMinutes: 60,
+		PasswordResetCooldownMinutes: 5,
+		AppURL: strings.TrimRight(os.Getenv("APP_URL"), "/"),
+		StoragePath: os.Getenv("STORAGE_PATH"),
+		MaxUploadMB: 10,
+		DuplicateScanHours: 24,
+		ContractCheckHours: 6,
+		ContractExpiryNoticeDays: 30,
+		Organization: Organization{
+			Name:       os.Getenv("ORG_NAME"),
+			FiscalCode: os.Getenv("ORG_FISCAL_CODE"),
+			Address:    os.Getenv("ORG_ADDRESS"),
+			Phone:      os.Getenv("ORG_PHONE"),
+			Email:      os.Getenv("ORG_EMAIL"),
+			IBAN:       os.Getenv("ORG_IBAN"),
+			BIC:        os.Getenv("ORG_BIC"),
+			BankName:   os.Getenv("ORG_BANK_NAME"),
+			Director:   os.Getenv("ORG_DIRECTOR"),
+		},
+		Mail: Mail{
+			Driver:       strings.ToLower(os.Getenv("MAIL_DRIVER")),
+			From:         os.Getenv("MAIL_FROM"),
+			OutboxPath:   os.Getenv("MAIL_OUTBOX_PATH"),
+			SMTPHost:     os.Getenv("SMTP_HOST"),
+			SMTPPort:     587,
+			SMTPUsername: os.Getenv("SMTP_USERNAME"),
+			SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		},
+	}
+
+	if cfg.StoragePath == "" {
+		cfg.StoragePath = "./data/attachments"
+	}
+	if v, err := strconv.ParseInt(os.Getenv("MAX_UPLOAD_MB"), 10, 64); err == nil && v > 0 {
+		cfg.MaxUploadMB = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("DUPLICATE_SCAN_HOURS")); err == nil && v >= 0 {
+		cfg.DuplicateScanHours = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("CONTRACT_CHECK_HOURS")); err == nil && v >= 0 {
+		cfg.ContractCheckHours = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("CONTRACT_EXPIRY_NOTICE_DAYS")); err == nil && v >= 0 {
+		cfg.ContractExpiryNoticeDays = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("INVITATION_TTL_HOURS")); err == nil && v > 0 {
+		cfg.InvitationTTLHours = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("ACCESS_TOKEN_TTL_MINUTES")); err == nil && v > 0 {
+		cfg.AccessTokenTTLMinutes = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("REFRESH_TOKEN_TTL_DAYS")); err == nil && v > 0 {
+		cfg.RefreshTokenTTLDays = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_TTL_MINUTES")); err == nil && v > 0 {
+		cfg.PasswordResetTTLMinutes = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_COOLDOWN_MINUTES")); err == nil && v >= 0 {
+		cfg.PasswordResetCooldownMinutes = v
+	}
+	if cfg.Mail.Driver == "" {
+		cfg.Mail.Driver = "outbox"
+	}
+	if cfg.Mail.From == "" {
+		cfg.Mail.From = "no-reply@localhost"
+	}
+	if cfg.Mail.OutboxPath == "" {
+		cfg.Mail.OutboxPath = "./data/outbox"
+	}
+	if v, err := strconv.Atoi(os.Getenv("SMTP_PORT")); err == nil && v > 0 {
+		cfg.Mail.SMTPPort = v
 	}
 
 	// Формируем DSN из переменных
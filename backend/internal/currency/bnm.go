@@ -0,0 +1,96 @@
+package currency
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Rate - cursul unei monede, așa cum apare în fișierul BNM
+type Rate struct {
+	Currency string  // Codul literal (CharCode), ex: "EUR"
+	Nominal  int     // Numărul de unități
+	Value    float64 // MDL pentru Nominal unități
+}
+
+// Structura fișierului XML al Băncii Naționale a Moldovei (bnm.md, "official_exchange_rates?get_xml=1"):
+//
+//	<ValCurs Date="19.10.2026" name="Cursul oficial de schimb">
+//	  <Valute ID="47">
+//	    <NumCode>978</NumCode>
+//	    <CharCode>EUR</CharCode>
+//	    <Nominal>1</Nominal>
+//	    <Name>Euro</Name>
+//	    <Value>19.6083</Value>
+//	  </Valute>
+//	</ValCurs>
+type bnmValCurs struct {
+	XMLName xml.Name    `xml:"ValCurs"`
+	Date    string      `xml:"Date,attr"`
+	Valutes []bnmValute `xml:"Valute"`
+}
+
+type bnmValute struct {
+	CharCode string `xml:"CharCode"`
+	Nominal  string `xml:"Nominal"`
+	Value    string `xml:"Value"`
+}
+
+// ParseBNM citește fișierul XML cu cursurile oficiale BNM și întoarce data cursului și ratele
+func ParseBNM(r io.Reader) (time.Time, []Rate, error) {
+	decoder := xml.NewDecoder(r)
+	// Fișierele BNM mai vechi sunt în windows-1251
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	var doc bnmValCurs
+	if err := decoder.Decode(&doc); err != nil {
+		return time.Time{}, nil, fmt.Errorf("invalid BNM XML: %w", err)
+	}
+
+	date, err := time.Parse("02.01.2006", strings.TrimSpace(doc.Date))
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("invalid BNM date %q: %w", doc.Date, err)
+	}
+
+	rates := make([]Rate, 0, len(doc.Valutes))
+	for _, v := range doc.Valutes {
+		code := strings.ToUpper(strings.TrimSpace(v.CharCode))
+		if !IsValidCode(code) {
+			return time.Time{}, nil, fmt.Errorf("invalid currency code %q", v.CharCode)
+		}
+
+		nominal := 1
+		if s := strings.TrimSpace(v.Nominal); s != "" {
+			nominal, err = strconv.Atoi(s)
+			if err != nil || nominal <= 0 {
+				return time.Time{}, nil, fmt.Errorf("invalid nominal %q for %s", v.Nominal, code)
+			}
+		}
+
+		value, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(v.Value), ",", "."), 64)
+		if err != nil || value <= 0 {
+			return time.Time{}, nil, fmt.Errorf("invalid value %q for %s", v.Value, code)
+		}
+
+		rates = append(rates, Rate{Currency: code, Nominal: nominal, Value: value})
+	}
+	return date, rates, nil
+}
+
+// IsValidCode verifică forma codului de monedă: trei litere latine mari (ISO 4217)
+func IsValidCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for i := 0; i < 3; i++ {
+		if code[i] < 'A' || code[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package currency
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBNM(t *testing.T) {
+	const doc = `<?xml version="1.0" encoding="UTF-8"?>
+<ValCurs Date="19.10.2026" name="Cursul oficial de schimb">
+  <Valute ID="47">
+    <NumCode>978</NumCode>
+    <CharCode>EUR</CharCode>
+    <Nominal>1</Nominal>
+    <Name>Euro</Name>
+    <Value>19.6083</Value>
+  </Valute>
+  <Valute ID="36">
+    <NumCode>643</NumCode>
+    <CharCode>rub</CharCode>
+    <Nominal>100</Nominal>
+    <Name>Rubla rusească</Name>
+    <Value>21,4512</Value>
+  </Valute>
+  <Valute ID="44">
+    <NumCode>840</NumCode>
+    <CharCode>USD</CharCode>
+    <Nominal></Nominal>
+    <Name>Dolar S.U.A.</Name>
+    <Value>17.8012</Value>
+  </Valute>
+</ValCurs>`
+
+	date, rates, err := ParseBNM(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ParseBNM: %v", err)
+	}
+	if want := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC); !date.Equal(want) {
+		t.Errorf("date = %v, want %v", date, want)
+	}
+	want := []Rate{
+		{Currency: "EUR", Nominal: 1, Value: 19.6083},
+		{Currency: "RUB", Nominal: 100, Value: 21.4512},
+		{Currency: "USD", Nominal: 1, Value: 17.8012},
+	}
+	if len(rates) != len(want) {
+		t.Fatalf("got %d rates, want %d", len(rates), len(want))
+	}
+	for i := range want {
+		if rates[i] != want[i] {
+			t.Errorf("rates[%d] = %+v, want %+v", i, rates[i], want[i])
+		}
+	}
+}
+
+func TestParseBNMWindows1251(t *testing.T) {
+	// Numele monedei în windows-1251 ("Рубль"), ca în fișierele BNM mai vechi
+	doc := "<?xml version=\"1.0\" encoding=\"windows-1251\"?>\n" +
+		"<ValCurs Date=\"01.02.2020\"><Valute><CharCode>RUB</CharCode><Nominal>1</Nominal>" +
+		"<Name>\xd0\xf3\xe1\xeb\xfc</Name><Value>0.2801</Value></Valute></ValCurs>"
+	_, rates, err := ParseBNM(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ParseBNM: %v", err)
+	}
+	if len(rates) != 1 || rates[0].Currency != "RUB" || rates[0].Value != 0.2801 {
+		t.Errorf("rates = %+v", rates)
+	}
+}
+
+func TestParseBNMErrors(t *testing.T) {
+	valute := func(code, nominal, value string) string {
+		return `<ValCurs Date="19.10.2026"><Valute><CharCode>` + code + `</CharCode><Nominal>` + nominal +
+			`</Nominal><Value>` + value + `</Value></Valute></ValCurs>`
+	}
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"not xml", "rates"},
+		{"wrong root", `<Rates Date="19.10.2026"></Rates>`},
+		{"missing date", `<ValCurs><Valute><CharCode>EUR</CharCode><Value>19.6</Value></Valute></ValCurs>`},
+		{"invalid date", `<ValCurs Date="2026-10-19"></ValCurs>`},
+		{"invalid code", valute("EURO", "1", "19.6")},
+		{"zero nominal", valute("EUR", "0", "19.6")},
+		{"invalid nominal", valute("EUR", "one", "19.6")},
+		{"negative value", valute("EUR", "1", "-19.6")},
+		{"empty value", valute("EUR", "1", "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseBNM(strings.NewReader(tt.doc)); err == nil {
+				t.Errorf("ParseBNM(%s): expected an error", tt.doc)
+			}
+		})
+	}
+}
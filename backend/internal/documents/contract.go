@@ -0,0 +1,115 @@
+package documents
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Câmpurile disponibile în șabloanele de contract, cu descrierea lor.
+// În șablon se scriu ca {{client.name}}; valorile lipsă se înlocuiesc cu text gol.
+var ContractPlaceholders = map[string]string{
+	"client.name":        "Denumirea clientului",
+	"client.fiscal_code": "Codul fiscal (IDNO / IDNP) al clientului",
+	"client.address":     "Adresa clientului",
+	"client.phone":       "Telefonul clientului",
+	"client.email":       "Email-ul clientului",
+	"client.iban":        "IBAN-ul contului implicit al clientului",
+	"client.bic":         "Codul BIC al băncii clientului",
+	"client.bank_name":   "Banca clientului",
+
+	"contract.number":     "Numărul contractului",
+	"contract.name":       "Numele contractului",
+	"contract.date":       "Data semnării (ZZ.LL.AAAA)",
+	"contract.start_date": "Începutul valabilității",
+	"contract.end_date":   "Sfârșitul valabilității (gol - nelimitat)",
+	"contract.amount":     "Suma contractului",
+	"contract.currency":   "Moneda contractului",
+	"contract.terms":      "Condițiile contractului",
+	"contract.version":    "Versiunea contractului (acordul adițional în vigoare)",
+
+	"addresses.billing":  "Prima adresă de facturare a contractului",
+	"addresses.shipping": "Prima adresă de livrare a contractului",
+	"addresses.all":      "Toate adresele contractului, separate prin „; ”",
+
+	"org.name":        "Denumirea organizației noastre",
+	"org.fiscal_code": "Codul fiscal al organizației",
+	"org.address":     "Adresa organizației",
+	"org.phone":       "Telefonul organizației",
+	"org.email":       "Email-ul organizației",
+	"org.iban":        "IBAN-ul organizației",
+	"org.bic":         "Codul BIC al băncii organizației",
+	"org.bank_name":   "Banca organizației",
+	"org.director":    "Conducătorul organizației (semnatarul)",
+
+	"today": "Data generării documentului",
+}
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([a-z_.]+)\s*\}\}`)
+
+// UnknownPlaceholders întoarce câmpurile din șablon care nu există în ContractPlaceholders (sortate, fără repetări)
+func UnknownPlaceholders(body string) []string {
+	seen := make(map[string]bool)
+	unknown := make([]string, 0)
+	for _, m := range placeholderPattern.FindAllStringSubmatch(body, -1) {
+		name := m[1]
+		if _, ok := ContractPlaceholders[name]; ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		unknown = append(unknown, name)
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// FillTemplate înlocuiește câmpurile {{nume}} cu valorile date
+func FillTemplate(body string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(body, func(m string) string {
+		return values[placeholderPattern.FindStringSubmatch(m)[1]]
+	})
+}
+
+// Tipurile blocurilor de text ale documentului
+const (
+	BlockTitle     = "title"     // Linie care începe cu "# " - titlul, centrat
+	BlockHeading   = "heading"   // Linie care începe cu "## " - titlul unui capitol
+	BlockParagraph = "paragraph" // Rândurile consecutive de text, unite într-un paragraf
+)
+
+// Block - un bloc de text al documentului generat
+type Block struct {
+	Kind string
+	Text string
+}
+
+// ParseBlocks împarte textul șablonului completat în blocuri: titluri ("# "), capitole ("## ")
+// și paragrafe (rândurile consecutive, separate de un rând gol)
+func ParseBlocks(text string) []Block {
+	var blocks []Block
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, Block{Kind: BlockParagraph, Text: strings.Join(paragraph, " ")})
+			paragraph = nil
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "## "):
+			flush()
+			blocks = append(blocks, Block{Kind: BlockHeading, Text: strings.TrimSpace(line[3:])})
+		case strings.HasPrefix(line, "# "):
+			flush()
+			blocks = append(blocks, Block{Kind: BlockTitle, Text: strings.TrimSpace(line[2:])})
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+	return blocks
+}
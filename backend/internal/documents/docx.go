@@ -0,0 +1,84 @@
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// Tipul MIME al documentelor Word (DOCX)
+const DocxContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+const docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>
+</Types>`
+
+const docxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>
+</Relationships>`
+
+// docxEscape escapează textul pentru XML
+func docxEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// RenderDOCX scrie blocurile într-un document Word (DOCX), ca să poată fi redactat înainte de semnare.
+// Titlul se centrează, capitolele sunt îngroșate; pagina este A4 cu margini de 2 cm.
+func RenderDOCX(title string, blocks []Block) ([]byte, error) {
+	var body strings.Builder
+	for _, block := range blocks {
+		props, runProps := "", ""
+		switch block.Kind {
+		case BlockTitle:
+			props = `<w:pPr><w:jc w:val="center"/><w:spacing w:before="240" w:after="240"/></w:pPr>`
+			runProps = `<w:rPr><w:b/><w:sz w:val="28"/></w:rPr>`
+		case BlockHeading:
+			props = `<w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/></w:pPr>`
+			runProps = `<w:rPr><w:b/><w:sz w:val="22"/></w:rPr>`
+		default:
+			props = `<w:pPr><w:jc w:val="both"/><w:spacing w:after="120"/></w:pPr>`
+			runProps = `<w:rPr><w:sz w:val="20"/></w:rPr>`
+		}
+		body.WriteString(`<w:p>` + props + `<w:r>` + runProps + `<w:t xml:space="preserve">` + docxEscape(block.Text) + `</w:t></w:r></w:p>`)
+	}
+
+	document := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		body.String() +
+		`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1134" w:right="1134" w:bottom="1134" w:left="1134" w:header="709" w:footer="709" w:gutter="0"/></w:sectPr>` +
+		`</w:body></w:document>`
+
+	core := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>` +
+		docxEscape(title) + `</dc:title></cp:coreProperties>`
+
+	var out bytes.Buffer
+	zw := zip.NewWriter(&out)
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRels},
+		{"docProps/core.xml", core},
+		{"word/document.xml", document},
+	} {
+		w, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
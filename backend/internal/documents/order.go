@@ -0,0 +1,82 @@
+package documents
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"orders/internal/models"
+	"strconv"
+	"time"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"money": func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) },
+	"qty":   func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) },
+	"inc":   func(i int) int { return i + 1 },
+	"date":  func(t time.Time) string { return t.Format("02.01.2006") },
+	"rate":  func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) },
+	"vatRateLabel": func(category string, rate float64) string {
+		if category == models.VatCategoryExempt {
+			return VatCategoryLabel(category)
+		}
+		return fmt.Sprintf("%s %s%%", VatCategoryLabel(category), strconv.FormatFloat(rate, 'f', -1, 64))
+	},
+}).ParseFS(templateFS, "templates/*.html"))
+
+// orderDocument - datele transmise șablonului de comandă
+type orderDocument struct {
+	Order               *models.Order
+	VatLines            []models.VatSummary
+	TotalWithoutVat     float64
+	TotalWithoutVatBase float64
+	Foreign             bool                      // Comanda este în valută: se afișează și sumele în moneda de bază
+	BaseCurrency        string                    // Moneda de bază (MDL)
+	BankAccount         *models.ClientBankAccount // Contul bancar al clientului afișat în document
+}
+
+// orderBankAccount alege contul clientului pentru document: contul implicit în moneda comenzii,
+// apoi oricare cont în moneda comenzii, apoi contul implicit al clientului
+func orderBankAccount(order *models.Order) *models.ClientBankAccount {
+	accounts := order.Client.BankAccounts
+	var sameCurrency, fallback *models.ClientBankAccount
+	for i := range accounts {
+		account := &accounts[i]
+		if account.Currency == order.Currency {
+			if account.IsDefault {
+				return account
+			}
+			if sameCurrency == nil {
+				sameCurrency = account
+			}
+		}
+		if account.IsDefault && fallback == nil {
+			fallback = account
+		}
+	}
+	if sameCurrency != nil {
+		return sameCurrency
+	}
+	return fallback
+}
+
+// RenderOrderHTML generează varianta tipăribilă (HTML) a comenzii, cu totalurile TVA pe categorii
+func RenderOrderHTML(w io.Writer, order *models.Order) error {
+	doc := orderDocument{
+		Order:        order,
+		VatLines:     VatBreakdown(order.OrderItems),
+		Foreign:      order.Currency != "" && order.Currency != models.BaseCurrency,
+		BaseCurrency: models.BaseCurrency,
+		BankAccount:  orderBankAccount(order),
+	}
+	for _, line := range doc.VatLines {
+		doc.TotalWithoutVat += line.Base
+		doc.TotalWithoutVatBase += line.NetBase
+	}
+	doc.TotalWithoutVat = round2(doc.TotalWithoutVat)
+	doc.TotalWithoutVatBase = round2(doc.TotalWithoutVatBase)
+	return templates.ExecuteTemplate(w, "order.html", doc)
+}
@@ -0,0 +1,11 @@
+package documents
+
+import (
+	"io"
+	"orders/internal/models"
+)
+
+// RenderPaymentHTML generează varianta tipăribilă a plății, cu impozitul reținut și suma netă
+func RenderPaymentHTML(w io.Writer, payment *models.Payment) error {
+	return templates.ExecuteTemplate(w, "payment.html", payment)
+}
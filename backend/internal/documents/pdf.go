@@ -0,0 +1,253 @@
+package documents
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ErrUnsupportedCharacters - textul conține caractere pe care fonturile standard ale PDF-ului nu le au
+var ErrUnsupportedCharacters = errors.New("unsupported_characters")
+
+// CharactersError - caracterele documentului care nu se pot scrie în PDF (ex. chirilice);
+// documentul se poate genera în DOCX
+type CharactersError struct {
+	Characters []string
+}
+
+func (e *CharactersError) Error() string {
+	return ErrUnsupportedCharacters.Error() + ": " + strings.Join(e.Characters, " ")
+}
+
+func (e *CharactersError) Unwrap() error {
+	return ErrUnsupportedCharacters
+}
+
+// Pagina A4 în puncte tipografice și așezarea textului
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 56.0
+	pdfFontSize   = 10.0
+	pdfLeading    = 14.0
+)
+
+// Fonturile standard Helvetica nu au nevoie de încorporare. Codurile WinAnsi nefolosite (sau rar folosite)
+// primesc literele românești care lipsesc din WinAnsi: ă, Ă, ș, Ș, ț, Ț.
+const pdfEncoding = "<< /Type /Encoding /BaseEncoding /WinAnsiEncoding " +
+	"/Differences [129 /abreve 141 /Abreve 143 /scommaaccent 144 /Scommaaccent 152 /Tcommaaccent 157 /tcommaaccent] >>"
+
+var pdfRunes = map[rune]byte{
+	'ă': 129, 'Ă': 141, 'ș': 143, 'Ș': 144, 'ş': 143, 'Ş': 144, 'ț': 157, 'Ț': 152, 'ţ': 157, 'Ţ': 152,
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '„': 0x84, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// Lățimile caracterelor ASCII (32..126) ale fontului Helvetica, în miimi din mărimea fontului
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// pdfCode întoarce codul caracterului în codificarea fontului și dacă fontul are glifa lui
+func pdfCode(r rune) (byte, bool) {
+	switch {
+	case r >= 32 && r < 127:
+		return byte(r), true
+	case pdfRunes[r] != 0:
+		return pdfRunes[r], true
+	case r >= 0xA0 && r <= 0xFF:
+		return byte(r), true
+	}
+	return 0, false
+}
+
+// pdfEncode transformă textul în codurile fontului. Caracterele fără glifă se verifică înainte
+// (pdfCheckText); dacă totuși apar, devin "?".
+func pdfEncode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		if code, ok := pdfCode(r); ok {
+			out = append(out, code)
+		} else {
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// pdfCheckText întoarce *CharactersError cu caracterele pe care fontul nu le poate scrie (spațiile nu contează,
+// pdfWrap le înlocuiește)
+func pdfCheckText(texts ...string) error {
+	seen := make(map[rune]bool)
+	var unsupported []string
+	for _, text := range texts {
+		for _, r := range text {
+			if _, ok := pdfCode(r); ok || seen[r] || unicode.IsSpace(r) {
+				continue
+			}
+			seen[r] = true
+			unsupported = append(unsupported, string(r))
+		}
+	}
+	if len(unsupported) == 0 {
+		return nil
+	}
+	sort.Strings(unsupported)
+	return &CharactersError{Characters: unsupported}
+}
+
+// pdfTextWidth - lățimea textului codificat, în puncte; literele din afara ASCII se socotesc ca o literă obișnuită
+func pdfTextWidth(text []byte, size float64, bold bool) float64 {
+	total := 0
+	for _, b := range text {
+		if b >= 32 && b < 127 {
+			total += helveticaWidths[b-32]
+		} else {
+			total += 556
+		}
+	}
+	width := float64(total) * size / 1000
+	if bold {
+		width *= 1.06 // Helvetica-Bold este puțin mai lat
+	}
+	return width
+}
+
+// pdfWrap împarte textul în rânduri care încap în lățimea dată
+func pdfWrap(text string, size, width float64, bold bool) [][]byte {
+	var lines [][]byte
+	var line []byte
+	for _, word := range strings.Fields(text) {
+		encoded := pdfEncode(word)
+		candidate := encoded
+		if len(line) > 0 {
+			candidate = append(append(append([]byte{}, line...), ' '), encoded...)
+		}
+		if len(line) > 0 && pdfTextWidth(candidate, size, bold) > width {
+			lines = append(lines, line)
+			candidate = encoded
+		}
+		line = candidate
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// pdfString scrie textul ca șir PDF, cu parantezele și backslash-ul escapate
+func pdfString(text []byte) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, c := range text {
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// RenderPDF așază blocurile pe pagini A4 și întoarce fișierul PDF.
+// Textul folosește fonturile standard Helvetica (fără încorporare); paginile sunt numerotate în subsol.
+// Dacă textul are caractere din afara WinAnsi și a literelor românești, întoarce *CharactersError.
+func RenderPDF(title string, blocks []Block) ([]byte, error) {
+	texts := []string{title}
+	for _, block := range blocks {
+		texts = append(texts, block.Text)
+	}
+	if err := pdfCheckText(texts...); err != nil {
+		return nil, err
+	}
+
+	textWidth := pdfPageWidth - 2*pdfMargin
+	var pages []*bytes.Buffer
+	var page *bytes.Buffer
+	y := 0.0
+	newPage := func() {
+		page = &bytes.Buffer{}
+		pages = append(pages, page)
+		y = pdfPageHeight - pdfMargin
+	}
+	newPage()
+
+	for i, block := range blocks {
+		font, size, bold, center := "F1", pdfFontSize, false, false
+		before := pdfLeading / 2
+		switch block.Kind {
+		case BlockTitle:
+			font, size, bold, center = "F2", 14, true, true
+			before = pdfLeading
+		case BlockHeading:
+			font, size, bold = "F2", 11, true
+			before = pdfLeading
+		}
+		if i == 0 {
+			before = 0
+		}
+		leading := size * 1.4
+
+		lines := pdfWrap(block.Text, size, textWidth, bold)
+		y -= before
+		// Titlul capitolului nu rămâne singur la sfârșitul paginii
+		if block.Kind == BlockHeading && y-2*leading-pdfLeading < pdfMargin {
+			newPage()
+		}
+		for _, line := range lines {
+			if y-leading < pdfMargin {
+				newPage()
+			}
+			y -= leading
+			x := pdfMargin
+			if center {
+				x = (pdfPageWidth - pdfTextWidth(line, size, bold)) / 2
+			}
+			fmt.Fprintf(page, "BT /%s %.1f Tf %.2f %.2f Td %s Tj ET\n", font, size, x, y, pdfString(line))
+		}
+	}
+
+	// Obiectele: 1 - catalogul, 2 - lista paginilor, 3, 4 - fonturile, apoi câte o pagină și conținutul ei
+	var out bytes.Buffer
+	offsets := []int{0}
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets)-1, body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding " + pdfEncoding + " >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding " + pdfEncoding + " >>")
+	for i, content := range pages {
+		footer := pdfEncode(fmt.Sprintf("Pagina %d din %d", i+1, len(pages)))
+		fmt.Fprintf(content, "BT /F1 8.0 Tf %.2f %.2f Td %s Tj ET\n",
+			(pdfPageWidth-pdfTextWidth(footer, 8, false))/2, pdfMargin/2, pdfString(footer))
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+	info := len(offsets)
+	object("<< /Title " + pdfString(pdfEncode(title)) + " /Producer (orders) >>")
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets))
+	for _, offset := range offsets[1:] {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets), info, xref)
+	return out.Bytes(), nil
+}
@@ -0,0 +1,86 @@
+package documents
+
+import (
+	"math"
+	"orders/internal/models"
+	"sort"
+)
+
+// Ordinea categoriilor în tabelele de TVA ale documentelor și rapoartelor
+var vatCategoryOrder = map[string]int{
+	models.VatCategoryStandard:  0,
+	models.VatCategoryReduced:   1,
+	models.VatCategoryZeroRated: 2,
+	models.VatCategoryExempt:    3,
+}
+
+// Denumirile categoriilor de TVA afișate pe documentele tipărite
+var vatCategoryLabels = map[string]string{
+	models.VatCategoryStandard:  "Cota standard",
+	models.VatCategoryReduced:   "Cota redusă",
+	models.VatCategoryZeroRated: "Cota zero",
+	models.VatCategoryExempt:    "Scutit de TVA",
+}
+
+// VatCategoryLabel întoarce denumirea categoriei pentru documente
+func VatCategoryLabel(category string) string {
+	if label, ok := vatCategoryLabels[category]; ok {
+		return label
+	}
+	return category
+}
+
+// VatBreakdown grupează pozițiile după categoria și rata TVA.
+// Livrările scutite și cele cu cota zero apar pe rânduri separate, chiar dacă ambele au TVA 0.
+func VatBreakdown(items []models.OrderItem) []models.VatSummary {
+	type key struct {
+		category string
+		rate     float64
+	}
+	totals := make(map[key]*models.VatSummary)
+	for _, item := range items {
+		k := key{item.VatCategory, item.VatRate}
+		line, ok := totals[k]
+		if !ok {
+			line = &models.VatSummary{Category: item.VatCategory, Rate: item.VatRate}
+			totals[k] = line
+		}
+		line.Base += item.Summ
+		line.Vat += item.VatSumm
+		line.Total += item.SummWithVat
+		line.NetBase += item.SummBase
+		line.VatBase += item.VatSummBase
+		line.TotalBase += item.SummWithVatBase
+	}
+
+	res := make([]models.VatSummary, 0, len(totals))
+	for _, line := range totals {
+		line.Base = round2(line.Base)
+		line.Vat = round2(line.Vat)
+		line.Total = round2(line.Total)
+		line.NetBase = round2(line.NetBase)
+		line.VatBase = round2(line.VatBase)
+		line.TotalBase = round2(line.TotalBase)
+		res = append(res, *line)
+	}
+	SortVatSummary(res)
+	return res
+}
+
+// SortVatSummary ordonează rândurile: standard, redusă, zero, scutit; în cadrul categoriei descrescător după rată, apoi după monedă
+func SortVatSummary(lines []models.VatSummary) {
+	sort.Slice(lines, func(i, j int) bool {
+		ci, cj := vatCategoryOrder[lines[i].Category], vatCategoryOrder[lines[j].Category]
+		if ci != cj {
+			return ci < cj
+		}
+		if lines[i].Rate != lines[j].Rate {
+			return lines[i].Rate > lines[j].Rate
+		}
+		return lines[i].Currency < lines[j].Currency
+	})
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,33 @@
+// Package jobs rulează sarcinile periodice ale aplicației (detectarea duplicatelor etc.) în fundal.
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every rulează fn la fiecare interval, până la anularea ctx. Prima rulare are loc după primul interval,
+// ca pornirea aplicației să nu fie încetinită. Erorile se scriu în log; sarcina continuă la intervalul următor.
+// Un interval <= 0 dezactivează sarcina.
+func Every(ctx context.Context, name string, interval time.Duration, fn func() error) {
+	if interval <= 0 {
+		log.Printf("⏸  Job %q disabled", name)
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			started := time.Now()
+			if err := fn(); err != nil {
+				log.Printf("❌ Job %q failed: %v", name, err)
+				continue
+			}
+			log.Printf("✅ Job %q done in %s", name, time.Since(started).Round(time.Millisecond))
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package mailer trimite scrisorile aplicației (resetarea parolei etc.) prin SMTP
+// sau le scrie în directorul outbox, pentru lucrul local fără server de mail.
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoRecipients este întoarsă pentru scrisoarea fără destinatari
+var ErrNoRecipients = errors.New("mailer: no recipients")
+
+// Message - scrisoarea (text simplu, UTF-8)
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer - interfața pentru trimiterea scrisorilor
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// SMTPMailer trimite scrisorile prin serverul SMTP (STARTTLS, dacă serverul îl oferă)
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string // Gol - fără autentificare
+	Password string
+	From     string
+}
+
+// Creează o nouă instanță de SMTPMailer
+func NewSMTPMailer(host string, port int, username, password, from string) *SMTPMailer {
+	return &SMTPMailer{Host: host, Port: port, Username: username, Password: password, From: from}
+}
+
+// Send trimite scrisoarea tuturor destinatarilor
+func (m *SMTPMailer) Send(msg Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+	if err := smtp.SendMail(addr, auth, m.From, msg.To, build(m.From, msg, time.Now())); err != nil {
+		return fmt.Errorf("mailer: smtp send: %w", err)
+	}
+	return nil
+}
+
+// OutboxMailer scrie fiecare scrisoare ca fișier .eml în directorul Dir
+type OutboxMailer struct {
+	Dir  string
+	From string
+}
+
+// Creează o nouă instanță de OutboxMailer și directorul, dacă lipsește
+func NewOutboxMailer(dir, from string) (*OutboxMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("mailer: cannot create outbox %s: %w", dir, err)
+	}
+	return &OutboxMailer{Dir: dir, From: from}, nil
+}
+
+// Send scrie scrisoarea într-un fișier temporar și îl redenumește la final,
+// ca cititorii directorului să nu vadă scrisori incomplete.
+func (m *OutboxMailer) Send(msg Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+	now := time.Now()
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000Z"), hex.EncodeToString(suffix))
+
+	tmp, err := os.CreateTemp(m.Dir, ".mail-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(build(m.From, msg, now)); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(m.Dir, name))
+}
+
+// build formează scrisoarea RFC 5322; subiectul se codifică pentru diacritice
+func build(from string, msg Message, date time.Time) []byte {
+	var b bytes.Buffer
+	header := func(name, value string) {
+		// Fără CR/LF în antete (injectarea altor antete)
+		value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+		b.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", from)
+	header("To", strings.Join(msg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "8bit")
+	b.WriteString("\r\n")
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return b.Bytes()
+}
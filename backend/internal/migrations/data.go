@@ -0,0 +1,361 @@
+package migrations
+
+import (
+	"log"
+	"orders/internal/validation"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DataMigration is a one-off data (or schema) change that AutoMigrate cannot express.
+// Applied migration names are recorded in the data_migrations table.
+type DataMigration struct {
+	Name string
+	Up   func(tx *gorm.DB) error
+}
+
+// appliedMigration records a data migration that has already run
+type appliedMigration struct {
+	Name      string `gorm:"primaryKey;type:varchar(100)"`
+	AppliedAt time.Time
+}
+
+func (appliedMigration) TableName() string {
+	return "data_migrations"
+}
+
+// GetDataMigrations returns the one-off data migrations, in execution order.
+// Never rename or reorder entries that already ran in production — append new ones at the end.
+func GetDataMigrations() []DataMigration {
+	return []DataMigration{
+		{Name: "2026_10_order_base_currency_amounts", Up: fillOrderBaseAmounts},
+		{Name: "2026_10_client_search_indexes", Up: createClientSearchIndexes},
+		{Name: "2026_10_client_phone_email_normalization", Up: normalizeClientPhonesAndEmails},
+		{Name: "2026_10_contract_lifecycle", Up: normalizeContractStatuses},
+		{Name: "2026_10_contract_initial_versions", Up: createInitialContractVersions},
+		{Name: "2026_10_order_item_list_prices", Up: fillOrderItemListPrices},
+		{Name: "2026_10_contract_address_types", Up: normalizeContractAddressTypes},
+		{Name: "2026_10_channel_scoping", Up: assignRecordChannels},
+		{Name: "2026_10_user_roles", Up: mapLegacyUserRoles},
+		{Name: "2026_10_client_unique_active", Up: uniqueActiveClients},
+		{Name: "2026_10_client_email_search_index", Up: createClientEmailSearchIndex},
+	}
+}
+
+// RunDataMigrations executes pending data migrations, each in its own transaction.
+// Must run after AutoMigrate (new columns exist) and before DropUnusedColumns (old columns still exist).
+func RunDataMigrations(db *gorm.DB) error {
+	if err := db.AutoMigrate(&appliedMigration{}); err != nil {
+		return err
+	}
+
+	for _, m := range GetDataMigrations() {
+		var count int64
+		if err := db.Model(&appliedMigration{}).Where("name = ?", m.Name).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&appliedMigration{Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			log.Printf("❌ Data migration %s failed: %v\n", m.Name, err)
+			return err
+		}
+		log.Printf("✅ Data migration applied: %s\n", m.Name)
+	}
+	return nil
+}
+
+// fillOrderBaseAmounts fills base currency amounts for orders created before currencies
+// were introduced (they were all in MDL, so amounts are copied as they are).
+func fillOrderBaseAmounts(tx *gorm.DB) error {
+	if err := tx.Exec(`UPDATE order_items
+		SET summ_base = summ, vat_summ_base = vat_summ, summ_with_vat_base = summ_with_vat
+		WHERE summ_with_vat_base = 0 AND summ_with_vat <> 0`).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`UPDATE orders
+		SET total_price_base = total_price, total_vat_base = total_vat
+		WHERE currency = 'MDL' AND total_price_base = 0 AND total_price <> 0`).Error
+}
+
+// createClientSearchIndexes enables pg_trgm and unaccent and creates the trigram indexes used by
+// client search. unaccent() is not IMMUTABLE, so indexes go through the f_unaccent wrapper.
+//
+// CREATE EXTENSION needs superuser (or a trusted extension and the CREATE privilege on the database).
+// When it fails the migration does not abort startup: f_unaccent falls back to translate() over the
+// common diacritics, no trigram indexes are created and the repository searches with LIKE only.
+// See the README for the DBA step that enables the extensions afterwards.
+func createClientSearchIndexes(tx *gorm.DB) error {
+	trigram := createExtension(tx, "pg_trgm")
+	unaccent := createExtension(tx, "unaccent")
+
+	body := `SELECT public.unaccent('public.unaccent', $1)`
+	if !unaccent {
+		body = `SELECT translate($1, 'ăâîșşțţáàäéèëíïóöúüçёйĂÂÎȘŞȚŢÁÀÄÉÈËÍÏÓÖÚÜÇЁЙ', 'aaissttaaaeeeiioouucеиAAISSTTAAAEEEIIOOUUCЕИ')`
+	}
+	if err := tx.Exec(`CREATE OR REPLACE FUNCTION f_unaccent(text) RETURNS text
+		LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+		AS $$ ` + body + ` $$`).Error; err != nil {
+		return err
+	}
+	if !trigram {
+		log.Println("⚠️  pg_trgm is not available: client search falls back to LIKE, without typo tolerance")
+		return nil
+	}
+
+	statements := []string{
+		`CREATE INDEX IF NOT EXISTS idx_clients_name_trgm ON clients USING gin (f_unaccent(lower(name)) gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_clients_fiscal_id_trgm ON clients USING gin (lower(fiscal_id) gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_clients_phone_trgm ON clients USING gin (regexp_replace(phone, '\D', '', 'g') gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_clients_address_trgm ON clients USING gin (f_unaccent(lower(address)) gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_client_contacts_name_trgm ON client_contacts USING gin (f_unaccent(lower(name)) gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_client_contacts_phone_trgm ON client_contacts USING gin (regexp_replace(phone, '\D', '', 'g') gin_trgm_ops)`,
+	}
+	for _, stmt := range statements {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createExtension creates the extension inside a savepoint, so that a missing privilege does not
+// abort the surrounding migration transaction. Returns whether the extension is installed.
+func createExtension(tx *gorm.DB, name string) bool {
+	var count int64
+	if err := tx.Raw("SELECT COUNT(*) FROM pg_extension WHERE extname = ?", name).Scan(&count).Error; err == nil && count > 0 {
+		return true
+	}
+	savepoint := "create_extension_" + name
+	if err := tx.SavePoint(savepoint).Error; err != nil {
+		return false
+	}
+	if err := tx.Exec("CREATE EXTENSION IF NOT EXISTS " + name).Error; err != nil {
+		log.Printf("⚠️  Cannot create extension %s (ask a DBA to run CREATE EXTENSION %s): %v\n", name, name, err)
+		tx.RollbackTo(savepoint)
+		return false
+	}
+	return true
+}
+
+// createClientEmailSearchIndex adds the trigram index for the email match of client search
+// (skipped when pg_trgm is not installed).
+func createClientEmailSearchIndex(tx *gorm.DB) error {
+	var count int64
+	if err := tx.Raw("SELECT COUNT(*) FROM pg_extension WHERE extname = 'pg_trgm'").Scan(&count).Error; err != nil || count == 0 {
+		return err
+	}
+	return tx.Exec(`CREATE INDEX IF NOT EXISTS idx_clients_email_trgm ON clients USING gin (lower(email) gin_trgm_ops)`).Error
+}
+
+// normalizeClientPhonesAndEmails cleans up client contact data:
+//   - generated placeholder emails (placeholder_...@local.invalid, "n/a", ...) become NULL;
+//   - emails are lower-cased; invalid ones and case-insensitive duplicates (all but the oldest client) become NULL;
+//   - phones of clients and contact persons are stored in E.164 (default region MD); unrecognised ones are kept as they are.
+//
+// The plain unique constraint on clients.email is replaced by a case-insensitive unique index;
+// NULL emails never collide.
+func normalizeClientPhonesAndEmails(tx *gorm.DB) error {
+	for _, stmt := range []string{
+		`ALTER TABLE clients DROP CONSTRAINT IF EXISTS uni_clients_email`,
+		`ALTER TABLE clients DROP CONSTRAINT IF EXISTS clients_email_key`,
+		`DROP INDEX IF EXISTS idx_clients_email`,
+		`ALTER TABLE clients ALTER COLUMN email DROP NOT NULL`,
+	} {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	var clients []struct {
+		ID    uint
+		Email *string
+		Phone string
+	}
+	// Soft-deleted clients are included: the unique index covers them too
+	if err := tx.Table("clients").Select("id, email, phone").Order("id").Scan(&clients).Error; err != nil {
+		return err
+	}
+
+	owners := make(map[string]uint)
+	var placeholders, invalidEmails, duplicates, phones, invalidPhones int
+	for _, c := range clients {
+		updates := make(map[string]interface{})
+
+		if c.Email != nil {
+			email, ok := validation.NormalizeEmail(*c.Email)
+			switch {
+			case ok && email == "":
+				placeholders++
+			case !ok:
+				invalidEmails++
+				log.Printf("⚠️  client %d: invalid email %q removed", c.ID, *c.Email)
+			case owners[email] != 0:
+				duplicates++
+				log.Printf("⚠️  client %d: email %q already used by client %d, removed", c.ID, *c.Email, owners[email])
+				email = ""
+			default:
+				owners[email] = c.ID
+			}
+			if email == "" {
+				updates["email"] = nil
+			} else if email != *c.Email {
+				updates["email"] = email
+			}
+		}
+
+		if phone, ok := validation.NormalizePhone(c.Phone, validation.DefaultPhoneRegion); !ok {
+			invalidPhones++
+		} else if phone != c.Phone {
+			updates["phone"] = phone
+			phones++
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Table("clients").Where("id = ?", c.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	var contacts []struct {
+		ID    uint
+		Email string
+		Phone string
+	}
+	if err := tx.Table("client_contacts").Select("id, email, phone").Order("id").Scan(&contacts).Error; err != nil {
+		return err
+	}
+	for _, c := range contacts {
+		updates := make(map[string]interface{})
+		if email, ok := validation.NormalizeEmail(c.Email); ok && email != c.Email {
+			updates["email"] = email
+		}
+		if phone, ok := validation.NormalizePhone(c.Phone, validation.DefaultPhoneRegion); ok && phone != c.Phone {
+			updates["phone"] = phone
+		} else if !ok {
+			invalidPhones++
+		}
+		if len(updates) > 0 {
+			if err := tx.Table("client_contacts").Where("id = ?", c.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	log.Printf("ℹ️  Client emails: %d placeholders and %d invalid set to NULL, %d duplicates removed; client phones: %d normalized; %d phones not recognised",
+		placeholders, invalidEmails, duplicates, phones, invalidPhones)
+
+	return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_clients_email_lower ON clients (lower(email))`).Error
+}
+
+// normalizeContractStatuses moves the free-form contract statuses to the lifecycle ones
+// (draft, active, suspended, expired, closed) and fills start_date from the signing date.
+// Unknown statuses become suspended: no orders are accepted on them, but unlike closed they can
+// be reactivated once reviewed. The original value is kept in the audit log (contract.status).
+func normalizeContractStatuses(tx *gorm.DB) error {
+	if err := tx.Exec(`UPDATE contracts SET start_date = date WHERE start_date IS NULL`).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec(`UPDATE contracts SET status = lower(trim(status))`).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec(`UPDATE contracts SET status = 'draft' WHERE status = ''`).Error; err != nil {
+		return err
+	}
+	unknown := `status NOT IN ('draft', 'active', 'suspended', 'expired', 'closed')`
+	audit := tx.Exec(`INSERT INTO audit_logs (created_at, updated_at, uuid, user_id, action, entity_type, entity_id, details)
+		SELECT now(), now(), gen_random_uuid(), owner_id, 'contract.status', 'contract', id,
+			json_build_object('from', status, 'to', 'suspended', 'reason', 'unknown legacy status')::text
+		FROM contracts WHERE ` + unknown)
+	if audit.Error != nil {
+		return audit.Error
+	}
+	if audit.RowsAffected > 0 {
+		log.Printf("⚠️  %d contracts with unknown statuses suspended; original values are in audit_logs (action contract.status)\n", audit.RowsAffected)
+	}
+	return tx.Exec(`UPDATE contracts SET status = 'suspended' WHERE ` + unknown).Error
+}
+
+// createInitialContractVersions stores version 1 (the contract as signed) for contracts created
+// before amendments existed; orders placed on them keep the default contract_version = 1.
+func createInitialContractVersions(tx *gorm.DB) error {
+	return tx.Exec(`INSERT INTO contract_amendments
+			(created_at, updated_at, uuid, contract_id, version, number, date, effective_from,
+			 name, amount, start_date, end_date, terms, user_id)
+		SELECT now(), now(), gen_random_uuid(), c.id, 1, '', c.date, COALESCE(c.start_date, c.date),
+			c.name, c.amount, c.start_date, c.end_date, c.terms, c.owner_id
+		FROM contracts c
+		WHERE NOT EXISTS (SELECT 1 FROM contract_amendments a WHERE a.contract_id = c.id)`).Error
+}
+
+// fillOrderItemListPrices copies the price into list_price for order items created before contract
+// price agreements existed: every price then was the list price.
+func fillOrderItemListPrices(tx *gorm.DB) error {
+	return tx.Exec(`UPDATE order_items SET list_price = price WHERE list_price = 0 AND contract_price_id IS NULL`).Error
+}
+
+// normalizeContractAddressTypes maps the free-form address types to billing, shipping and legal.
+// Unrecognised or empty types become shipping: the free-text address stays as it was, and the
+// address has to get a classifier locality the next time it is edited.
+func normalizeContractAddressTypes(tx *gorm.DB) error {
+	if err := tx.Exec(`UPDATE contract_addresses SET type = lower(trim(coalesce(type, '')))`).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec(`UPDATE contract_addresses SET type = 'billing'
+		WHERE type IN ('invoice', 'invoicing', 'facturare', 'factura')`).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec(`UPDATE contract_addresses SET type = 'legal'
+		WHERE type IN ('juridica', 'juridică', 'registered', 'legal_address')`).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`UPDATE contract_addresses SET type = 'shipping'
+		WHERE type NOT IN ('billing', 'shipping', 'legal')`).Error
+}
+
+// assignRecordChannels puts existing contracts into their client's sales channel and orders into
+// their contract's channel (or the client's, for orders without one). Records of clients without
+// a channel stay unassigned and are visible to admins only.
+func assignRecordChannels(tx *gorm.DB) error {
+	if err := tx.Exec(`UPDATE contracts SET channel_id = clients.channel_id
+		FROM clients WHERE clients.id = contracts.client_id AND contracts.channel_id IS NULL`).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`UPDATE orders o SET channel_id = COALESCE(
+			(SELECT channel_id FROM contracts WHERE contracts.id = o.contract_id),
+			(SELECT channel_id FROM clients WHERE clients.id = o.client_id))
+		WHERE o.channel_id IS NULL`).Error
+}
+
+// mapLegacyUserRoles moves the old catch-all "user" role (and any other unknown role) to sales_rep,
+// which keeps the access those users had to clients, contracts and orders.
+func mapLegacyUserRoles(tx *gorm.DB) error {
+	return tx.Exec(`UPDATE users SET role = 'sales_rep'
+		WHERE role NOT IN ('admin', 'manager', 'sales_rep', 'warehouse', 'accountant', 'read_only')`).Error
+}
+
+// uniqueActiveClients makes fiscal codes and emails unique among live clients only: soft-deleted and
+// merged-away clients must not block creating or importing a client with the same fiscal code or email.
+// AutoMigrate has already dropped the column-level constraint; the IF EXISTS covers databases where it never existed.
+func uniqueActiveClients(tx *gorm.DB) error {
+	if err := tx.Exec(`ALTER TABLE clients DROP CONSTRAINT IF EXISTS uni_clients_fiscal_id`).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_clients_fiscal_id_active ON clients (fiscal_id) WHERE deleted_at IS NULL`).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec(`DROP INDEX IF EXISTS idx_clients_email_lower`).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`CREATE UNIQUE INDEX idx_clients_email_lower ON clients (lower(email)) WHERE deleted_at IS NULL`).Error
+}
@@ -12,40 +12,93 @@ func GetAllModels() []interface{} {
 		&models.PriceType{},
 		// Main entities
 		&models.User{},
+		&models.Invitation{},
+		&models.Session{},
+		&models.PasswordReset{},
+		&models.APIKey{},
 		// Client methods
 		&models.Client{},
+		&models.ClientContact{},
+		&models.ClientBankAccount{},
 		// Contract methods
 		&models.Contract{},
+		&models.ContractAmendment{},
+		&models.ContractPrice{},
+		&models.ContractTemplate{},
+		&models.ContractTemplateVersion{},
+		&models.Locality{},
 		&models.ContractAddress{},
 		// Product methods
 		&models.Product{},
+		&models.ProductBarcode{},
 		&models.ProductGroup{},
 		&models.VatTax{},
+		&models.VatTaxRate{},
 		&models.IncomeTax{},
 		&models.Unit{},
 		&models.PriceProduct{},
+		&models.ExchangeRate{},
 		// Documents
 		&models.Order{},
 		&models.OrderItem{},
+		&models.Payment{},
+		// Files
+		&models.Attachment{},
+		&models.ContractDocument{},
+		&models.Import{},
+		// Integrations
+		&models.ExchangeLog{},
+		// Duplicates
+		&models.DuplicateCandidate{},
+		&models.ClientMerge{},
+		&models.ClientMergeItem{},
+		// Audit
+		&models.AuditLog{},
+		// Notifications
+		&models.Notification{},
 	}
 }
 
 // TableNameToModel maps database table names to model struct names
 func TableNameToModel(tableName string) string {
 	tableMap := map[string]string{
-		"client_types":       "ClientType",
-		"price_types":        "PriceType",
-		"users":              "User",
-		"clients":            "Client",
-		"contracts":          "Contract",
-		"contract_addresses": "ContractAddress",
-		"products":           "Product",
-		"vat_taxes":          "VatTax",
-		"income_taxes":       "IncomeTax",
-		"units":              "Unit",
-		"price_products":     "PriceProduct",
-		"orders":             "Order",
-		"order_items":        "OrderItem",
+		"client_types":               "ClientType",
+		"price_types":                "PriceType",
+		"users":                      "User",
+		"invitations":                "Invitation",
+		"sessions":                   "Session",
+		"password_resets":            "PasswordReset",
+		"api_keys":                   "APIKey",
+		"clients":                    "Client",
+		"client_contacts":            "ClientContact",
+		"client_bank_accounts":       "ClientBankAccount",
+		"contracts":                  "Contract",
+		"contract_amendments":        "ContractAmendment",
+		"contract_prices":            "ContractPrice",
+		"contract_templates":         "ContractTemplate",
+		"contract_template_versions": "ContractTemplateVersion",
+		"contract_documents":         "ContractDocument",
+		"contract_addresses":         "ContractAddress",
+		"localities":                 "Locality",
+		"products":                   "Product",
+		"product_barcodes":           "ProductBarcode",
+		"vat_taxes":                  "VatTax",
+		"vat_tax_rates":              "VatTaxRate",
+		"income_taxes":               "IncomeTax",
+		"units":                      "Unit",
+		"price_products":             "PriceProduct",
+		"exchange_rates":             "ExchangeRate",
+		"orders":                     "Order",
+		"order_items":                "OrderItem",
+		"payments":                   "Payment",
+		"attachments":                "Attachment",
+		"imports":                    "Import",
+		"exchange_logs":              "ExchangeLog",
+		"duplicate_candidates":       "DuplicateCandidate",
+		"client_merges":              "ClientMerge",
+		"client_merge_items":         "ClientMergeItem",
+		"audit_logs":                 "AuditLog",
+		"notifications":              "Notification",
 	}
 
 	if v, ok := tableMap[tableName]; ok {
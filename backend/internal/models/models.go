@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -17,11 +19,131 @@ type User struct {
 	gorm.Model
 	UUIDModel `gorm:"embedded"`
 	Email     string    `gorm:"unique;not null"`           // Email-ul utilizatorului (unic)
-	Password  string    `gorm:"not null"`                  // Hash-ul parolei (nu se afișează în JSON)
-	Role      string    `gorm:"type:varchar(20);not null"` // Rolul utilizatorului ("admin", "user" etc.)
+	Password  string    `gorm:"not null" json:"-"`         // Hash-ul parolei (nu se afișează în JSON)
+	Role      string    `gorm:"type:varchar(20);not null"` // Rolul utilizatorului (RoleAdmin, RoleManager etc.)
 	Channels  []Channel `gorm:"many2many:user_channels;"`  // Canalele de vânzări la care are acces utilizatorul
 }
 
+// Rolurile utilizatorilor; permisiunile fiecărui rol se stabilesc în service
+const (
+	RoleAdmin      = "admin"      // Administrator, are toate permisiunile
+	RoleManager    = "manager"    // Managerul de vânzări
+	RoleSalesRep   = "sales_rep"  // Agentul de vânzări
+	RoleWarehouse  = "warehouse"  // Depozitul
+	RoleAccountant = "accountant" // Contabilitatea
+	RoleReadOnly   = "read_only"  // Doar vizualizare
+)
+
+// ****************************************************
+
+// ********** Invitation - Invitația unui utilizator nou **********
+// Administratorul invită utilizatorul cu rolul și canalele lui; invitatul își setează parola
+// prin token-ul de unică folosință, care se păstrează doar ca hash.
+type Invitation struct {
+	gorm.Model
+	UUIDModel   `gorm:"embedded"`
+	Email       string     `gorm:"type:varchar(100);not null;index"`               // Email-ul invitatului
+	Role        string     `gorm:"type:varchar(20);not null"`                      // Rolul primit la acceptare
+	TokenHash   string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // SHA-256 al token-ului
+	ExpiresAt   time.Time  `gorm:"not null"`                                       // Termenul de valabilitate al token-ului
+	AcceptedAt  *time.Time `gorm:"default:null"`                                   // Data acceptării
+	RevokedAt   *time.Time `gorm:"default:null"`                                   // Data anulării
+	InvitedByID uint       `gorm:"not null"`                                       // Administratorul care a trimis invitația
+	UserID      *uint      `gorm:"default:null"`                                   // Utilizatorul creat la acceptare
+	Channels    []Channel  `gorm:"many2many:invitation_channels;"`                 // Canalele de vânzări ale utilizatorului
+}
+
+// Statusurile invitației (se calculează din date, nu se salvează)
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationExpired  = "expired"
+	InvitationRevoked  = "revoked"
+)
+
+// Status întoarce statusul invitației la momentul now
+func (invitation *Invitation) Status(now time.Time) string {
+	switch {
+	case invitation.AcceptedAt != nil:
+		return InvitationAccepted
+	case invitation.RevokedAt != nil:
+		return InvitationRevoked
+	case !now.Before(invitation.ExpiresAt):
+		return InvitationExpired
+	}
+	return InvitationPending
+}
+
+// ****************************************************
+
+// ********** Session - Sesiunea de autentificare **********
+// Sesiunea se creează la login; refresh token-ul se schimbă la fiecare reînnoire și se păstrează doar ca hash.
+// Token-ul de acces (JWT de scurtă durată) conține ID-ul sesiunii, deci anularea sesiunii îl invalidează imediat.
+type Session struct {
+	gorm.Model
+	UserID            uint       `gorm:"not null;index"`                                 // Utilizatorul sesiunii
+	TokenHash         string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // SHA-256 al refresh token-ului actual
+	PreviousTokenHash string     `gorm:"type:varchar(64);index" json:"-"`                // SHA-256 al refresh token-ului înlocuit (detectarea reutilizării)
+	ExpiresAt         time.Time  `gorm:"not null"`                                       // Termenul refresh token-ului
+	LastUsedAt        time.Time  `gorm:"not null"`                                       // Ultimul login sau ultima reînnoire
+	RevokedAt         *time.Time `gorm:"default:null"`                                   // Data anulării (logout)
+	UserAgent         string     `gorm:"type:varchar(255)"`                              // Dispozitivul / browserul
+	IP                string     `gorm:"type:varchar(45)"`                               // Adresa IP la login
+}
+
+// Active verifică dacă sesiunea nu a fost anulată și nu a expirat
+func (session *Session) Active(now time.Time) bool {
+	return session.RevokedAt == nil && now.Before(session.ExpiresAt)
+}
+
+// ****************************************************
+
+// ********** PasswordReset - Cererea de resetare a parolei **********
+// Token-ul se trimite prin email, se păstrează doar ca hash și se poate folosi o singură dată.
+type PasswordReset struct {
+	gorm.Model
+	UserID    uint       `gorm:"not null;index"`                                 // Utilizatorul care și-a uitat parola
+	TokenHash string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // SHA-256 al token-ului
+	ExpiresAt time.Time  `gorm:"not null"`                                       // Termenul de valabilitate al token-ului
+	UsedAt    *time.Time `gorm:"default:null"`                                   // Data folosirii (sau anulării de o cerere nouă)
+	IP        string     `gorm:"type:varchar(45)"`                               // Adresa IP a cererii
+}
+
+// ****************************************************
+
+// ********** APIKey - Cheia API pentru integrări **********
+// Cheia se emite de administrator pentru scripturi și alte sisteme (header X-API-Key) și se păstrează doar ca hash.
+// Cererile cu cheia au doar permisiunile și canalele ei și se fac în numele administratorului care a emis-o.
+type APIKey struct {
+	gorm.Model
+	UUIDModel   `gorm:"embedded"`
+	Name        string     `gorm:"type:varchar(100);not null"`                     // Denumirea (scriptul / sistemul care o folosește)
+	Prefix      string     `gorm:"type:varchar(16);not null"`                      // Începutul cheii, pentru recunoaștere
+	KeyHash     string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // SHA-256 al cheii
+	Permissions []string   `gorm:"type:text;serializer:json"`                      // Permisiunile cheii (resursă:acțiune)
+	Channels    []Channel  `gorm:"many2many:api_key_channels;"`                    // Canalele de vânzări accesibile cheii
+	ExpiresAt   time.Time  `gorm:"not null"`                                       // Termenul de valabilitate
+	LastUsedAt  *time.Time `gorm:"default:null"`                                   // Ultima cerere cu cheia
+	LastUsedIP  string     `gorm:"type:varchar(45)"`                               // Adresa IP a ultimei cereri
+	RevokedAt   *time.Time `gorm:"default:null"`                                   // Data anulării
+	CreatedByID uint       `gorm:"not null"`                                       // Administratorul care a emis cheia
+}
+
+// HasPermission verifică dacă cheia are permisiunea dată
+func (key *APIKey) HasPermission(permission string) bool {
+	for _, p := range key.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Active verifică dacă cheia nu a fost anulată și nu a expirat
+func (key *APIKey) Active(now time.Time) bool {
+	return key.RevokedAt == nil && now.Before(key.ExpiresAt)
+}
+
 // ****************************************************
 
 // ********** Channel - Canal de vânzări **********
@@ -35,11 +157,36 @@ type Channel struct {
 
 // ****************************************************
 
+// ********** ChannelScope - Canalele vizibile utilizatorului (nu se salvează) **********
+// Clienții, contractele și comenzile se văd doar în canalele utilizatorului; administratorul vede tot.
+// Înregistrările fără canal le vede doar administratorul.
+type ChannelScope struct {
+	All        bool   // Fără restricții (administratorul)
+	ChannelIDs []uint // Canalele utilizatorului
+}
+
+// Has verifică dacă înregistrarea din canalul dat este vizibilă
+func (scope ChannelScope) Has(channelID uint) bool {
+	if scope.All {
+		return true
+	}
+	for _, id := range scope.ChannelIDs {
+		if id == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+// ****************************************************
+
 // ********** Client - Client (beneficiar) **********
 type ClientType struct {
 	gorm.Model
-	UUIDModel `gorm:"embedded"`
-	Name      string `gorm:"type:varchar(20);not null"` // Tipul clientului ("individual", "company", etc.)
+	UUIDModel   `gorm:"embedded"`
+	Name        string     `gorm:"type:varchar(20);not null"`            // Tipul clientului ("individual", "company", etc.)
+	IncomeTaxID *uint      `gorm:"default:null"`                         // Impozitul pe venit reținut implicit la plățile către acest tip de client
+	IncomeTax   *IncomeTax `gorm:"foreignKey:IncomeTaxID;references:ID"` // Impozitul pe venit implicit
 }
 
 // ****************************************************
@@ -48,14 +195,49 @@ type ClientType struct {
 type Client struct {
 	gorm.Model
 	UUIDModel    `gorm:"embedded"`
-	ClientTypeID uint       `gorm:"not null"`                          // Foreign key to ClientType
-	ClientType   ClientType `gorm:"foreignKey:ClientTypeID;not null"`  // Tipul clientului ("individual", "company", etc.)
-	Name         string     `gorm:"type:varchar(100);not null"`        // Numele clientului
-	FiscalID     string     `gorm:"type:varchar(15);unique;not null"`  // Codul fiscal al clientului (unic)
-	Email        string     `gorm:"type:varchar(100);unique;not null"` // Email-ul clientului (unic)
-	Phone        string     `gorm:"type:varchar(50)"`                  // Telefonul clientului
-	Address      string     `gorm:"type:text"`                         // Adresa clientului
-	Contracts    []Contract `gorm:"foreignKey:ClientID"`               // Contractele clientului
+	ClientTypeID uint                `gorm:"not null"`                         // Foreign key to ClientType
+	ClientType   ClientType          `gorm:"foreignKey:ClientTypeID;not null"` // Tipul clientului ("individual", "company", etc.)
+	Name         string              `gorm:"type:varchar(100);not null"`       // Numele clientului
+	FiscalID     string              `gorm:"type:varchar(15);not null"`        // Codul fiscal al clientului (unic printre clienții neșterși, vezi migrațiile)
+	Email        *string             `gorm:"type:varchar(100);default:null"`   // Email-ul clientului (unic, fără diferență între litere mari și mici; null dacă lipsește)
+	Phone        string              `gorm:"type:varchar(50)"`                 // Telefonul clientului (E.164, ex: +37369123456)
+	Address      string              `gorm:"type:text"`                        // Adresa clientului
+	ChannelID    *uint               `gorm:"default:null;index"`               // Canalul de vânzări al clientului
+	Channel      *Channel            `gorm:"foreignKey:ChannelID"`             // Canalul de vânzări
+	Contracts    []Contract          `gorm:"foreignKey:ClientID"`              // Contractele clientului
+	Contacts     []ClientContact     `gorm:"foreignKey:ClientID"`              // Persoanele de contact ale clientului
+	BankAccounts []ClientBankAccount `gorm:"foreignKey:ClientID"`              // Conturile bancare ale clientului
+}
+
+// ****************************************************
+
+// ********** ClientContact - Persoană de contact a clientului **********
+// Canalele preferate de comunicare cu persoana de contact
+var ContactChannels = []string{"phone", "email", "sms", "viber", "telegram", "whatsapp"}
+
+type ClientContact struct {
+	gorm.Model
+	UUIDModel        `gorm:"embedded"`
+	ClientID         uint   `gorm:"not null;index"`             // Cheie externă către Client
+	Name             string `gorm:"type:varchar(100);not null"` // Numele persoanei
+	Position         string `gorm:"type:varchar(100)"`          // Funcția (ex: "director", "contabil")
+	Phone            string `gorm:"type:varchar(50)"`           // Telefonul
+	Email            string `gorm:"type:varchar(100)"`          // Email-ul
+	PreferredChannel string `gorm:"type:varchar(20)"`           // Canalul preferat ("phone", "email", "sms", "viber", "telegram", "whatsapp")
+}
+
+// ****************************************************
+
+// ********** ClientBankAccount - Cont bancar al clientului **********
+type ClientBankAccount struct {
+	gorm.Model
+	UUIDModel `gorm:"embedded"`
+	ClientID  uint   `gorm:"not null;index"`                              // Cheie externă către Client
+	IBAN      string `gorm:"column:iban;type:varchar(34);not null;index"` // IBAN-ul, fără spații (ex: MD24AG000225100013104168)
+	BankCode  string `gorm:"type:varchar(11)"`                            // Codul BIC / SWIFT al băncii (ex: AGRNMD2X)
+	BankName  string `gorm:"type:varchar(100)"`                           // Denumirea băncii
+	Currency  string `gorm:"type:varchar(3);not null;default:'MDL'"`      // Moneda contului (ISO 4217)
+	IsDefault bool   `gorm:"not null;default:false"`                      // Contul implicit al clientului (apare pe facturi)
 }
 
 // ****************************************************
@@ -63,31 +245,165 @@ type Client struct {
 // ********** Contract - Contract cu clientul **********
 type Contract struct {
 	gorm.Model
-	UUIDModel `gorm:"embedded"`
-	Number    string            `gorm:"type:varchar(50);not null;unique"`  // Numărul contractului
-	Name      string            `gorm:"type:varchar(100);not null"`        // Numele contractului
-	Date      string            `gorm:"type:date;not null"`                // Data contractului
-	Amount    float64           `gorm:"type:decimal(10,2);not null"`       // Suma contractului
-	Status    string            `gorm:"type:varchar(20);not null"`         // Statutul ("active", "closed" etc.)
-	ClientID  uint              `gorm:"not null"`                          // Cheie externă către Client
-	Client    Client            `gorm:"foreignKey:ClientID;references:ID"` // Clientul
-	OwnerID   uint              `gorm:"not null"`                          // ID-ul ownerului (utilizatorului)
-	Owner     User              `gorm:"foreignKey:OwnerID;references:ID"`  // Ownerul contractului
-	Addresses []ContractAddress `gorm:"foreignKey:ContractID"`             // Adresele asociate contractului
+	UUIDModel        `gorm:"embedded"`
+	Number           string              `gorm:"type:varchar(50);not null;unique"`                // Numărul contractului
+	Name             string              `gorm:"type:varchar(100);not null"`                      // Numele contractului
+	Date             time.Time           `gorm:"type:date;not null"`                              // Data semnării contractului
+	StartDate        time.Time           `gorm:"type:date"`                                       // Începutul valabilității (implicit data semnării)
+	EndDate          *time.Time          `gorm:"type:date;default:null;index"`                    // Sfârșitul valabilității (nil - pe termen nelimitat)
+	Amount           float64             `gorm:"type:decimal(10,2);not null"`                     // Suma contractului
+	Terms            string              `gorm:"type:text"`                                       // Condițiile contractului
+	Status           string              `gorm:"type:varchar(20);not null;default:'draft';index"` // Statutul: draft, active, suspended, expired, closed
+	ExpiryNotifiedAt *time.Time          `gorm:"default:null"`                                    // Când ownerul a fost anunțat despre expirarea apropiată
+	Version          int                 `gorm:"not null;default:1"`                              // Versiunea curentă (crește cu fiecare acord adițional)
+	Amendments       []ContractAmendment `gorm:"foreignKey:ContractID"`                           // Versiunile contractului (acordurile adiționale)
+	Currency         string              `gorm:"type:varchar(3);not null;default:'MDL'"`          // Moneda contractului (ISO 4217)
+	ClientID         uint                `gorm:"not null"`                                        // Cheie externă către Client
+	Client           Client              `gorm:"foreignKey:ClientID;references:ID"`               // Clientul
+	OwnerID          uint                `gorm:"not null"`                                        // ID-ul ownerului (utilizatorului)
+	Owner            User                `gorm:"foreignKey:OwnerID;references:ID"`                // Ownerul contractului
+	ChannelID        *uint               `gorm:"default:null;index"`                              // Canalul de vânzări (preluat de la client)
+	Channel          *Channel            `gorm:"foreignKey:ChannelID"`                            // Canalul de vânzări
+	Addresses        []ContractAddress   `gorm:"foreignKey:ContractID"`                           // Adresele asociate contractului
+	IncomeTaxID      *uint               `gorm:"default:null"`                                    // Impozitul pe venit reținut la plăți (are prioritate față de tipul clientului)
+	IncomeTax        *IncomeTax          `gorm:"foreignKey:IncomeTaxID;references:ID"`            // Impozitul pe venit al contractului
 }
 
+// Statutele contractului
+const (
+	ContractStatusDraft     = "draft"     // Ciornă, încă nu se pot face comenzi
+	ContractStatusActive    = "active"    // În vigoare
+	ContractStatusSuspended = "suspended" // Suspendat temporar
+	ContractStatusExpired   = "expired"   // A expirat data de sfârșit
+	ContractStatusClosed    = "closed"    // Închis definitiv
+)
+
 // ****************************************************
 
+// ********** ContractAmendment - Versiune a contractului (acord adițional) **********
+// Fiecare versiune păstrează toate condițiile contractului de la data EffectiveFrom;
+// versiunea 1 este contractul inițial.
+type ContractAmendment struct {
+	gorm.Model
+	UUIDModel     `gorm:"embedded"`
+	ContractID    uint       `gorm:"not null;uniqueIndex:idx_contract_amendment_version"` // Contractul
+	Version       int        `gorm:"not null;uniqueIndex:idx_contract_amendment_version"` // Numărul versiunii (1 - contractul inițial)
+	Number        string     `gorm:"type:varchar(50)"`                                    // Numărul acordului adițional
+	Date          time.Time  `gorm:"type:date;not null"`                                  // Data semnării
+	EffectiveFrom time.Time  `gorm:"type:date;not null"`                                  // Data de la care versiunea este în vigoare
+	Name          string     `gorm:"type:varchar(100);not null"`                          // Numele contractului
+	Amount        float64    `gorm:"type:decimal(10,2);not null"`                         // Suma contractului
+	StartDate     time.Time  `gorm:"type:date"`                                           // Începutul valabilității
+	EndDate       *time.Time `gorm:"type:date;default:null"`                              // Sfârșitul valabilității (nil - nelimitat)
+	Terms         string     `gorm:"type:text"`                                           // Condițiile contractului
+	UserID        uint       `gorm:"not null;default:0"`                                  // Utilizatorul care a înregistrat versiunea
+}
+
+// ****************************************************
+
+// ********** ContractPrice - Preț convenit în contract **********
+// Are prioritate față de PriceType / PriceProduct la comenzile pe acest contract.
+// Se indică fie prețul fix (în moneda contractului, pentru unitatea de bază), fie reducerea față de prețul de listă.
+type ContractPrice struct {
+	gorm.Model
+	UUIDModel  `gorm:"embedded"`
+	ContractID uint       `gorm:"not null;index:idx_contract_prices_product"` // Contractul
+	Contract   Contract   `gorm:"foreignKey:ContractID;references:ID"`        // Contractul
+	ProductID  uint       `gorm:"not null;index:idx_contract_prices_product"` // Produsul
+	Product    Product    `gorm:"foreignKey:ProductID;references:ID"`         // Produsul
+	Price      *float64   `gorm:"type:decimal(10,2);default:null"`            // Prețul fix, în moneda contractului
+	Discount   *float64   `gorm:"type:decimal(5,2);default:null"`             // Reducerea față de prețul de listă, %
+	ValidFrom  time.Time  `gorm:"type:date;not null"`                         // Valabil de la (implicit începutul contractului)
+	ValidTo    *time.Time `gorm:"type:date;default:null"`                     // Valabil până la (nil - până la sfârșitul contractului)
+	OwnerID    uint       `gorm:"not null"`                                   // Utilizatorul care a introdus acordul
+}
+
+// ****************************************************
+
+// ********** ContractTemplate - Șablon de contract **********
+// Textul conține câmpuri de forma {{client.name}}; fiecare modificare a textului creează o versiune nouă.
+type ContractTemplate struct {
+	gorm.Model
+	UUIDModel   `gorm:"embedded"`
+	Name        string                    `gorm:"type:varchar(100);not null;uniqueIndex"`  // Denumirea șablonului
+	Description string                    `gorm:"type:text"`                               // Descrierea (pentru ce contracte se folosește)
+	Body        string                    `gorm:"type:text;not null"`                      // Textul versiunii curente
+	Version     int                       `gorm:"not null;default:1"`                      // Versiunea curentă
+	IsActive    bool                      `gorm:"not null;default:true"`                   // Se poate folosi la generarea contractelor
+	Versions    []ContractTemplateVersion `gorm:"foreignKey:TemplateID" json:",omitempty"` // Versiunile șablonului
+}
+
+// ****************************************************
+
+// ********** ContractTemplateVersion - Versiune a șablonului de contract **********
+type ContractTemplateVersion struct {
+	gorm.Model
+	UUIDModel  `gorm:"embedded"`
+	TemplateID uint   `gorm:"not null;uniqueIndex:idx_contract_template_version"` // Șablonul
+	Version    int    `gorm:"not null;uniqueIndex:idx_contract_template_version"` // Numărul versiunii
+	Body       string `gorm:"type:text;not null"`                                 // Textul șablonului în această versiune
+	UserID     uint   `gorm:"not null"`                                           // Utilizatorul care a salvat versiunea
+}
+
+// ****************************************************
+
+// ********** ContractDocument - Document generat din șablon pentru contract **********
+type ContractDocument struct {
+	gorm.Model
+	UUIDModel       `gorm:"embedded"`
+	ContractID      uint             `gorm:"not null;index"`                               // Contractul
+	ContractVersion int              `gorm:"not null;default:1"`                           // Versiunea contractului la generare
+	TemplateID      uint             `gorm:"not null"`                                     // Șablonul folosit
+	Template        ContractTemplate `gorm:"foreignKey:TemplateID;references:ID" json:"-"` // Șablonul
+	TemplateVersion int              `gorm:"not null"`                                     // Versiunea șablonului folosită
+	Format          string           `gorm:"type:varchar(10);not null"`                    // "pdf" sau "docx"
+	AttachmentID    uint             `gorm:"not null"`                                     // Fișierul generat (atașat contractului)
+	Attachment      Attachment       `gorm:"foreignKey:AttachmentID;references:ID"`        // Fișierul generat
+	UserID          uint             `gorm:"not null"`                                     // Utilizatorul care a generat documentul
+}
+
+// ****************************************************
+
+// Tipurile adreselor contractului
+const (
+	AddressTypeBilling  = "billing"  // Adresa de facturare
+	AddressTypeShipping = "shipping" // Adresa de livrare (obligatoriu cu localitatea din clasificator)
+	AddressTypeLegal    = "legal"    // Adresa juridică
+)
+
 // ********** ContractAddress - Adresă asociată contractului **********
 type ContractAddress struct {
 	gorm.Model
-	UUIDModel  `gorm:"embedded"`
-	ContractID uint     `gorm:"not null"`                            // Cheie externă către Contract
-	Address    string   `gorm:"type:text;not null"`                  // Adresa
-	Type       string   `gorm:"type:varchar(50)"`                    // Tipul adresei ("billing", "shipping" etc.)
-	Contract   Contract `gorm:"foreignKey:ContractID;references:ID"` // Contractul
-	OwnerID    uint     `gorm:"not null"`                            // ID-ul ownerului (utilizatorului)
-	Owner      User     `gorm:"foreignKey:OwnerID;references:ID"`    // Ownerul adresei
+	UUIDModel     `gorm:"embedded"`
+	ContractID    uint      `gorm:"not null"`                                // Cheie externă către Contract
+	Address       string    `gorm:"type:text;not null"`                      // Adresa completă (se compune din câmpurile de mai jos)
+	Type          string    `gorm:"type:varchar(50);default:'shipping'"`     // Tipul adresei: billing, shipping, legal
+	LocalityCode  *string   `gorm:"type:varchar(7);index"`                   // Codul CUATM al localității
+	Locality      *Locality `gorm:"foreignKey:LocalityCode;references:Code"` // Localitatea
+	Street        string    `gorm:"type:varchar(150)"`                       // Strada (ex: "str. Ștefan cel Mare și Sfânt")
+	Building      string    `gorm:"type:varchar(50)"`                        // Numărul clădirii, blocul, oficiul
+	PostalCode    string    `gorm:"type:varchar(10)"`                        // Codul poștal (MD-2001)
+	Latitude      *float64  `gorm:"type:decimal(9,6)"`                       // Latitudinea GPS a punctului de livrare
+	Longitude     *float64  `gorm:"type:decimal(9,6)"`                       // Longitudinea GPS
+	ContactName   string    `gorm:"type:varchar(100)"`                       // Persoana de contact la adresă
+	ContactPhone  string    `gorm:"type:varchar(20)"`                        // Telefonul persoanei de contact (E.164)
+	OpeningHours  string    `gorm:"type:varchar(100)"`                       // Programul de primire (ex: "L-V 08:00-17:00")
+	DeliveryNotes string    `gorm:"type:text"`                               // Indicații pentru livrare (intrarea, rampa etc.)
+	Contract      Contract  `gorm:"foreignKey:ContractID;references:ID"`     // Contractul
+	OwnerID       uint      `gorm:"not null"`                                // ID-ul ownerului (utilizatorului)
+	Owner         User      `gorm:"foreignKey:OwnerID;references:ID"`        // Ownerul adresei
+}
+
+// ****************************************************
+
+// ********** Locality - Localitate din clasificatorul CUATM **********
+type Locality struct {
+	gorm.Model
+	UUIDModel `gorm:"embedded"`
+	Code      string `gorm:"type:varchar(7);not null;uniqueIndex"` // Codul CUATM (7 cifre)
+	Name      string `gorm:"type:varchar(150);not null;index"`     // Denumirea localității
+	Kind      string `gorm:"type:varchar(50)"`                     // Statutul (municipiu, oraș, comună, sat etc.)
+	District  string `gorm:"type:varchar(150)"`                    // Raionul / municipiul / UTA din care face parte
 }
 
 // ****************************************************
@@ -107,26 +423,62 @@ type ProductGroup struct {
 type Product struct {
 	gorm.Model
 	UUIDModel      `gorm:"embedded"`
-	Name           string       `gorm:"type:varchar(100);not null"`              // Numele produsului
-	Price          float64      `gorm:"type:decimal(10,2);default:0.0"`          // Prețul produsului
-	Description    string       `gorm:"type:text"`                               // Descrierea produsului
-	ProductGroupID uint         `gorm:"not null"`                                // ID-ul grupei de produse
-	ProductGroup   ProductGroup `gorm:"foreignKey:ProductGroupID;references:ID"` // Grupa de produse din care face parte
-	UnitID         uint         `gorm:"not null"`                                // ID-ul unității de măsură
-	Unit           Unit         `gorm:"foreignKey:UnitID;references:ID"`         // Unitatea de măsură a produsului
-	VatTaxID       uint         `gorm:"not null"`                                // ID-ul taxei VAT
-	VatTax         VatTax       `gorm:"foreignKey:VatTaxID;references:ID"`       // Taxa VAT a produsului
+	Name           string           `gorm:"type:varchar(100);not null"`                                     // Numele produsului
+	SKU            string           `gorm:"type:varchar(50);index:idx_products_sku,unique,where:sku <> ''"` // Codul de articol (SKU), unic dacă este completat
+	Price          float64          `gorm:"type:decimal(10,2);default:0.0"`                                 // Prețul produsului
+	Description    string           `gorm:"type:text"`                                                      // Descrierea produsului
+	ProductGroupID uint             `gorm:"not null"`                                                       // ID-ul grupei de produse
+	ProductGroup   ProductGroup     `gorm:"foreignKey:ProductGroupID;references:ID"`                        // Grupa de produse din care face parte
+	UnitID         uint             `gorm:"not null"`                                                       // ID-ul unității de măsură
+	Unit           Unit             `gorm:"foreignKey:UnitID;references:ID"`                                // Unitatea de măsură a produsului
+	VatTaxID       uint             `gorm:"not null"`                                                       // ID-ul taxei VAT
+	VatTax         VatTax           `gorm:"foreignKey:VatTaxID;references:ID"`                              // Taxa VAT a produsului
+	Barcodes       []ProductBarcode `gorm:"foreignKey:ProductID"`                                           // Codurile de bare ale produsului (câte unul pe ambalaj)
+}
+
+// ****************************************************
+
+// ********** ProductBarcode - Cod de bare al produsului **********
+type ProductBarcode struct {
+	gorm.Model
+	UUIDModel `gorm:"embedded"`
+	ProductID uint    `gorm:"not null;uniqueIndex:idx_product_barcode_unit"` // Cheie externă către Product
+	Product   Product `gorm:"foreignKey:ProductID;references:ID"`            // Produsul
+	UnitID    uint    `gorm:"not null;uniqueIndex:idx_product_barcode_unit"` // Unitatea de ambalare (un singur cod pe ambalaj)
+	Unit      Unit    `gorm:"foreignKey:UnitID;references:ID"`               // Unitatea de ambalare
+	Barcode   string  `gorm:"type:varchar(14);not null;uniqueIndex"`         // Codul de bare EAN-8 / EAN-13 / GTIN-14 (unic)
 }
 
 // ****************************************************
 
 // ********** VatRate - TVA **********
+// Categoriile de TVA: cota standard, cota redusă, cota zero (livrare impozabilă cu 0%) și scutit (fără drept de deducere)
+const (
+	VatCategoryStandard  = "standard"
+	VatCategoryReduced   = "reduced"
+	VatCategoryZeroRated = "zero_rated"
+	VatCategoryExempt    = "exempt"
+)
+
 type VatTax struct {
 	gorm.Model
 	UUIDModel   `gorm:"embedded"`
-	Name        string  `gorm:"type:varchar(100);not null"`  // Numele taxei
-	Rate        float64 `gorm:"type:decimal(10,2);not null"` // Rata taxei
-	Description string  `gorm:"type:text"`                   // Descrierea taxei
+	Name        string       `gorm:"type:varchar(100);not null"`                   // Numele taxei
+	Category    string       `gorm:"type:varchar(20);not null;default:'standard'"` // Categoria ("standard", "reduced", "zero_rated", "exempt")
+	Rate        float64      `gorm:"type:decimal(10,2);not null"`                  // Rata taxei (folosită dacă nu există versiuni datate)
+	Description string       `gorm:"type:text"`                                    // Descrierea taxei
+	Rates       []VatTaxRate `gorm:"foreignKey:VatTaxID"`                          // Versiunile datate ale ratei
+}
+
+// ****************************************************
+
+// ********** VatTaxRate - Versiune datată a ratei TVA **********
+type VatTaxRate struct {
+	gorm.Model
+	UUIDModel `gorm:"embedded"`
+	VatTaxID  uint      `gorm:"not null;uniqueIndex:idx_vat_tax_rate_from"`           // Cheie externă către VatTax
+	Rate      float64   `gorm:"type:decimal(10,2);not null"`                          // Rata taxei în vigoare
+	ValidFrom time.Time `gorm:"type:date;not null;uniqueIndex:idx_vat_tax_rate_from"` // Data de la care rata este în vigoare (până la următoarea versiune)
 }
 
 // ****************************************************
@@ -181,40 +533,397 @@ type PriceProduct struct {
 // ********** Order - Comandă **********
 type Order struct {
 	gorm.Model
-	UUIDModel   `gorm:"embedded"`
-	OwnerID     uint        `gorm:"not null"`                            // ID-ul ownerului (utilizatorului)
-	Owner       User        `gorm:"foreignKey:OwnerID;references:ID"`    // Ownerul comenzii
-	ClientID    uint        `gorm:"not null"`                            // ID-ul clientului (cheie externă)
-	Client      Client      `gorm:"foreignKey:ClientID;references:ID"`   // Clientul care a plasat comanda
-	PriceTypeID uint        `gorm:"not null"`                            // ID-ul tipului de preț (cheie externă)
-	PriceType   PriceType   `gorm:"foreignKey:PriceTypeID"`              // Tipul de preț al comenzii
-	ContractID  uint        `gorm:"not null"`                            // ID-ul contractului (cheie externă)
-	Contract    Contract    `gorm:"foreignKey:ContractID;references:ID"` // Contractul asociat comenzii
-	TotalPrice  float64     `gorm:"type:decimal(10,2);not null"`         // Suma totală a comenzii
-	Status      string      `gorm:"type:varchar(20);not null"`           // Statusul comenzii
-	OrderItems  []OrderItem `gorm:"foreignKey:OrderID"`                  // Pozițiile comenzii
+	UUIDModel       `gorm:"embedded"`
+	OwnerID         uint        `gorm:"not null"`                                // ID-ul ownerului (utilizatorului)
+	Owner           User        `gorm:"foreignKey:OwnerID;references:ID"`        // Ownerul comenzii
+	ClientID        uint        `gorm:"not null"`                                // ID-ul clientului (cheie externă)
+	Client          Client      `gorm:"foreignKey:ClientID;references:ID"`       // Clientul care a plasat comanda
+	PriceTypeID     uint        `gorm:"not null"`                                // ID-ul tipului de preț (cheie externă)
+	PriceType       PriceType   `gorm:"foreignKey:PriceTypeID"`                  // Tipul de preț al comenzii
+	ContractID      uint        `gorm:"not null"`                                // ID-ul contractului (cheie externă)
+	Contract        Contract    `gorm:"foreignKey:ContractID;references:ID"`     // Contractul asociat comenzii
+	ContractVersion int         `gorm:"not null;default:1"`                      // Versiunea contractului în vigoare la data comenzii
+	ChannelID       *uint       `gorm:"default:null;index"`                      // Canalul de vânzări (preluat din contract)
+	Channel         *Channel    `gorm:"foreignKey:ChannelID"`                    // Canalul de vânzări
+	Date            time.Time   `gorm:"type:date;not null;default:CURRENT_DATE"` // Data documentului (determină rata TVA aplicată)
+	Currency        string      `gorm:"type:varchar(3);not null;default:'MDL'"`  // Moneda documentului (ISO 4217)
+	ExchangeRate    float64     `gorm:"type:decimal(12,4);not null;default:1"`   // Cursul BNM la data documentului (MDL pentru 1 unitate)
+	TotalPrice      float64     `gorm:"type:decimal(10,2);not null"`             // Suma totală a comenzii (cu TVA), în moneda documentului
+	TotalVat        float64     `gorm:"type:decimal(10,2);not null;default:0"`   // Suma totală a TVA-ului, în moneda documentului
+	TotalPriceBase  float64     `gorm:"type:decimal(12,2);not null;default:0"`   // Suma totală cu TVA în moneda de bază (MDL)
+	TotalVatBase    float64     `gorm:"type:decimal(12,2);not null;default:0"`   // Suma TVA în moneda de bază (MDL)
+	Status          string      `gorm:"type:varchar(20);not null"`               // Statusul comenzii
+	OrderItems      []OrderItem `gorm:"foreignKey:OrderID"`                      // Pozițiile comenzii
 }
 
+// Statusurile comenzii
+const (
+	OrderStatusPending  = "pending"  // Nouă, așteaptă aprobarea
+	OrderStatusApproved = "approved" // Aprobată
+)
+
 // ****************************************************
 
 // ********** OrderItem - Poziție comandă **********
 type OrderItem struct {
 	gorm.Model
-	UUIDModel   `gorm:"embedded"`
-	OrderID     uint    `gorm:"not null"`                           // ID-ul comenzii
-	ProductID   uint    `gorm:"not null"`                           // ID-ul produsului
-	Product     Product `gorm:"foreignKey:ProductID;references:ID"` // Produsul asociat poziției
-	Quantity    float64 `gorm:"type:decimal(10,3);not null"`        // Cantitatea
-	Price       float64 `gorm:"type:decimal(10,2);not null"`        // Prețul unitar la momentul comenzii
-	UnitID      uint    `gorm:"not null"`                           // ID-ul unității de măsură
-	Unit        Unit    `gorm:"foreignKey:UnitID;references:ID"`    // Unitatea de măsură asociată poziției
-	UnitName    string  `gorm:"type:varchar(20)"`                   // Stocăm "KG" sau "BUC"
-	VatTaxID    uint    `gorm:"not null"`                           // ID-ul taxei VAT
-	VatTax      VatTax  `gorm:"foreignKey:VatTaxID;references:ID"`  // Taxa VAT asociată poziției
-	VatRate     float64 `gorm:"type:decimal(10,2);not null"`        // Rata TVA-ului (preluată din VatTax)
-	Summ        float64 `gorm:"type:decimal(10,2);not null"`        // Suma totală pentru poziție (Price * Quantity)
-	VatSumm     float64 `gorm:"type:decimal(10,2);not null"`        // Valoarea TVA-ului în bani
-	SummWithVat float64 `gorm:"type:decimal(10,2);not null"`        // Suma totală pentru poziție cu TVA (Summ + VAT)
+	UUIDModel       `gorm:"embedded"`
+	OrderID         uint    `gorm:"not null"`                                     // ID-ul comenzii
+	ProductID       uint    `gorm:"not null"`                                     // ID-ul produsului
+	Product         Product `gorm:"foreignKey:ProductID;references:ID"`           // Produsul asociat poziției
+	Quantity        float64 `gorm:"type:decimal(10,3);not null"`                  // Cantitatea
+	Price           float64 `gorm:"type:decimal(10,2);not null"`                  // Prețul unitar la momentul comenzii
+	UnitID          uint    `gorm:"not null"`                                     // ID-ul unității de măsură
+	Unit            Unit    `gorm:"foreignKey:UnitID;references:ID"`              // Unitatea de măsură asociată poziției
+	UnitName        string  `gorm:"type:varchar(20)"`                             // Stocăm "KG" sau "BUC"
+	VatTaxID        uint    `gorm:"not null"`                                     // ID-ul taxei VAT
+	VatTax          VatTax  `gorm:"foreignKey:VatTaxID;references:ID"`            // Taxa VAT asociată poziției
+	VatRate         float64 `gorm:"type:decimal(10,2);not null"`                  // Rata TVA-ului (preluată din VatTax)
+	VatCategory     string  `gorm:"type:varchar(20);not null;default:'standard'"` // Categoria TVA la data comenzii
+	Summ            float64 `gorm:"type:decimal(10,2);not null"`                  // Suma totală pentru poziție (Price * Quantity)
+	VatSumm         float64 `gorm:"type:decimal(10,2);not null"`                  // Valoarea TVA-ului în bani
+	SummWithVat     float64 `gorm:"type:decimal(10,2);not null"`                  // Suma totală pentru poziție cu TVA (Summ + VAT)
+	SummBase        float64 `gorm:"type:decimal(12,2);not null;default:0"`        // Suma fără TVA în moneda de bază (MDL)
+	VatSummBase     float64 `gorm:"type:decimal(12,2);not null;default:0"`        // Valoarea TVA-ului în moneda de bază (MDL)
+	SummWithVatBase float64 `gorm:"type:decimal(12,2);not null;default:0"`        // Suma cu TVA în moneda de bază (MDL)
+	ListPrice       float64 `gorm:"type:decimal(10,2);not null;default:0"`        // Prețul de listă (tip de preț / prețul de bază), în moneda comenzii
+	ContractPriceID *uint   `gorm:"default:null"`                                 // Acordul de preț aplicat (nil - prețul de listă)
+}
+
+// ****************************************************
+
+// ********** ExchangeRate - Cursul oficial BNM **********
+// Moneda de bază a contabilității; sumele în alte monede se convertesc în ea la înregistrare
+const BaseCurrency = "MDL"
+
+type ExchangeRate struct {
+	gorm.Model
+	UUIDModel `gorm:"embedded"`
+	Currency  string    `gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rate_day"` // Codul monedei (ISO 4217, ex: "EUR")
+	Date      time.Time `gorm:"type:date;not null;uniqueIndex:idx_exchange_rate_day"`       // Data cursului
+	Nominal   int       `gorm:"not null;default:1"`                                         // Numărul de unități pentru care se dă cursul
+	Rate      float64   `gorm:"type:decimal(12,4);not null"`                                // MDL pentru Nominal unități
+}
+
+// ****************************************************
+
+// ********** Payment - Plată (încasare sau plată către client) **********
+const (
+	PaymentIncoming = "incoming" // Încasare de la client
+	PaymentOutgoing = "outgoing" // Plată către client (servicii cumpărate, onorarii etc.)
+)
+
+type Payment struct {
+	gorm.Model
+	UUIDModel      `gorm:"embedded"`
+	OwnerID        uint       `gorm:"not null"`                              // ID-ul ownerului (utilizatorului)
+	Owner          User       `gorm:"foreignKey:OwnerID;references:ID"`      // Ownerul plății
+	Direction      string     `gorm:"type:varchar(10);not null"`             // Direcția ("incoming", "outgoing")
+	Date           time.Time  `gorm:"type:date;not null"`                    // Data plății
+	ClientID       uint       `gorm:"not null;index"`                        // ID-ul clientului
+	Client         Client     `gorm:"foreignKey:ClientID;references:ID"`     // Clientul
+	ContractID     *uint      `gorm:"default:null"`                          // ID-ul contractului (opțional)
+	Contract       *Contract  `gorm:"foreignKey:ContractID;references:ID"`   // Contractul
+	Amount         float64    `gorm:"type:decimal(10,2);not null"`           // Suma brută
+	IncomeTaxID    *uint      `gorm:"default:null"`                          // Impozitul pe venit reținut
+	IncomeTax      *IncomeTax `gorm:"foreignKey:IncomeTaxID;references:ID"`  // Impozitul pe venit
+	IncomeTaxRate  float64    `gorm:"type:decimal(10,2);not null;default:0"` // Rata impozitului la data plății
+	WithheldAmount float64    `gorm:"type:decimal(10,2);not null;default:0"` // Suma reținută
+	NetAmount      float64    `gorm:"type:decimal(10,2);not null"`           // Suma netă de plată (Amount - WithheldAmount)
+	Description    string     `gorm:"type:text"`                             // Destinația plății
+}
+
+// ****************************************************
+
+// Files - Fișiere
+// ********** Attachment - Fișier atașat (imagine, scan, fotografie) **********
+type Attachment struct {
+	gorm.Model
+	UUIDModel    `gorm:"embedded"`
+	OwnerType    string `gorm:"type:varchar(30);not null;index:idx_attachments_owner"` // Tipul entității ("product", "contract", "order")
+	OwnerID      uint   `gorm:"not null;index:idx_attachments_owner"`                  // ID-ul entității
+	Kind         string `gorm:"type:varchar(30);not null"`                             // Felul fișierului ("image", "signed_scan", "photo")
+	FileName     string `gorm:"type:varchar(255);not null"`                            // Numele original al fișierului
+	ContentType  string `gorm:"type:varchar(100);not null"`                            // Tipul MIME detectat din conținut
+	Size         int64  `gorm:"not null"`                                              // Dimensiunea în octeți
+	Checksum     string `gorm:"type:varchar(64);not null"`                             // SHA-256 al conținutului
+	StorageKey   string `gorm:"type:varchar(255);not null"`                            // Cheia fișierului în stocare
+	ThumbnailKey string `gorm:"type:varchar(255)"`                                     // Cheia miniaturii (doar pentru imagini)
+	UploadedByID uint   `gorm:"not null"`                                              // ID-ul utilizatorului care a încărcat fișierul
+	UploadedBy   User   `gorm:"foreignKey:UploadedByID;references:ID" json:"-"`        // Utilizatorul care a încărcat fișierul
+}
+
+// ****************************************************
+
+// ********** Import - Import de date din fișier (CSV / XLSX) **********
+type Import struct {
+	gorm.Model
+	UUIDModel `gorm:"embedded"`
+	Kind      string `gorm:"type:varchar(30);not null"`                 // Ce se importă ("clients")
+	FileName  string `gorm:"type:varchar(255);not null"`                // Numele fișierului încărcat
+	Mode      string `gorm:"type:varchar(10);not null"`                 // "create" - doar noi, "upsert" - creează sau actualizează
+	DryRun    bool   `gorm:"not null;default:false"`                    // Doar validare, fără scriere în baza de date
+	TotalRows int    `gorm:"not null;default:0"`                        // Rânduri de date procesate
+	Created   int    `gorm:"not null;default:0"`                        // Rânduri create (sau care ar fi create la dry-run)
+	Updated   int    `gorm:"not null;default:0"`                        // Rânduri actualizate (sau care ar fi actualizate)
+	Skipped   int    `gorm:"not null;default:0"`                        // Rânduri cu erori
+	ResultKey string `gorm:"type:varchar(255)" json:"-"`                // Cheia fișierului cu rezultatul în stocare
+	OwnerID   uint   `gorm:"not null"`                                  // ID-ul utilizatorului care a făcut importul
+	Owner     User   `gorm:"foreignKey:OwnerID;references:ID" json:"-"` // Utilizatorul
+}
+
+// ImportRow - rezultatul unui rând din fișierul importat (nu se salvează în tabel, ci în fișierul rezultat)
+type ImportRow struct {
+	Row      int      `json:"row"`              // Numărul rândului în fișier (antetul este rândul 1)
+	Action   string   `json:"action"`           // "create", "update" sau "skip"
+	Key      string   `json:"key"`              // Cheia rândului (codul fiscal)
+	ID       uint     `json:"id,omitempty"`     // ID-ul înregistrării create / actualizate
+	Reason   string   `json:"reason,omitempty"` // Motivul pentru "skip" (ex: "invalid_fiscal_id")
+	Detail   string   `json:"detail,omitempty"` // Detalii despre eroare
+	Original []string `json:"-"`                // Valorile originale ale rândului
+}
+
+// ****************************************************
+
+// Integrations - Integrări
+// ********** ExchangeLog - Schimb de date cu un sistem extern (1C) **********
+const (
+	ExchangeImport = "import" // Date primite din sistemul extern
+	ExchangeExport = "export" // Date trimise sistemului extern
+)
+
+type ExchangeLog struct {
+	gorm.Model
+	UUIDModel    `gorm:"embedded"`
+	System       string     `gorm:"type:varchar(20);not null;index:idx_exchange_logs_kind"` // Sistemul extern ("1c")
+	Direction    string     `gorm:"type:varchar(10);not null;index:idx_exchange_logs_kind"` // "import" sau "export"
+	Kind         string     `gorm:"type:varchar(20);not null;index:idx_exchange_logs_kind"` // Ce s-a schimbat ("catalog", "orders")
+	FileName     string     `gorm:"type:varchar(255)"`                                      // Fișierul primit / trimis
+	StartedAt    time.Time  `gorm:"not null"`                                               // Momentul începerii schimbului
+	ChangedSince *time.Time `gorm:"default:null"`                                           // Export: doar înregistrările modificate după acest moment (null = toate)
+	Records      int        `gorm:"not null;default:0"`                                     // Înregistrări create / actualizate / trimise
+	Skipped      int        `gorm:"not null;default:0"`                                     // Înregistrări sărite
+	Status       string     `gorm:"type:varchar(10);not null"`                              // "success" sau "failed"
+	Error        string     `gorm:"type:text"`                                              // Eroarea, dacă schimbul a eșuat
+	UserID       uint       `gorm:"not null"`                                               // Utilizatorul care a pornit schimbul
+}
+
+// ****************************************************
+
+// Duplicates - Duplicate
+// ********** DuplicateCandidate - Pereche de clienți posibil duplicați **********
+const (
+	DuplicateOpen      = "open"      // Așteaptă decizia: unire sau respingere
+	DuplicateDismissed = "dismissed" // Nu sunt duplicați; perechea nu se mai propune
+	DuplicateMerged    = "merged"    // Clienții au fost uniți
+)
+
+type DuplicateCandidate struct {
+	gorm.Model
+	UUIDModel    `gorm:"embedded"`
+	ClientAID    uint      `gorm:"column:client_a_id;not null;uniqueIndex:idx_duplicate_pair"` // Clientul cu ID-ul mai mic
+	ClientA      Client    `gorm:"foreignKey:ClientAID;references:ID"`                         // Primul client
+	ClientBID    uint      `gorm:"column:client_b_id;not null;uniqueIndex:idx_duplicate_pair"` // Clientul cu ID-ul mai mare
+	ClientB      Client    `gorm:"foreignKey:ClientBID;references:ID"`                         // Al doilea client
+	Score        float64   `gorm:"type:decimal(5,4);not null;index"`                           // Scorul total (0..1)
+	NameScore    float64   `gorm:"type:decimal(5,4);not null;default:0"`                       // Similaritatea numelor
+	PhoneScore   float64   `gorm:"type:decimal(5,4);not null;default:0"`                       // 1 dacă telefoanele coincid
+	AddressScore float64   `gorm:"type:decimal(5,4);not null;default:0"`                       // Similaritatea adreselor
+	Status       string    `gorm:"type:varchar(10);not null;default:'open';index"`             // "open", "dismissed", "merged"
+	DetectedAt   time.Time `gorm:"not null"`                                                   // Ultima detectare a perechii
+}
+
+// ****************************************************
+
+// ********** ClientMerge - Unirea a doi clienți **********
+type ClientMerge struct {
+	gorm.Model
+	UUIDModel    `gorm:"embedded"`
+	SurvivorID   uint              `gorm:"not null;index"`                      // Clientul care rămâne
+	Survivor     Client            `gorm:"foreignKey:SurvivorID;references:ID"` // Clientul care rămâne
+	MergedID     uint              `gorm:"not null;index"`                      // Clientul unit (șters logic)
+	Merged       Client            `gorm:"foreignKey:MergedID;references:ID"`   // Clientul unit
+	CandidateID  *uint             `gorm:"default:null"`                        // Perechea detectată, dacă unirea a pornit de la ea
+	Contracts    int               `gorm:"not null;default:0"`                  // Contracte mutate
+	Orders       int               `gorm:"not null;default:0"`                  // Comenzi mutate
+	Payments     int               `gorm:"not null;default:0"`                  // Plăți mutate
+	Contacts     int               `gorm:"not null;default:0"`                  // Persoane de contact mutate
+	BankAccounts int               `gorm:"not null;default:0"`                  // Conturi bancare mutate
+	UserID       uint              `gorm:"not null"`                            // Utilizatorul care a unit clienții
+	UndoneAt     *time.Time        `gorm:"default:null"`                        // Momentul anulării unirii
+	UndoneByID   *uint             `gorm:"default:null"`                        // Utilizatorul care a anulat unirea
+	Items        []ClientMergeItem `gorm:"foreignKey:MergeID" json:"-"`         // Înregistrările mutate
+}
+
+// ClientMergeItem - o înregistrare mutată la unire; la anulare se mută înapoi doar acestea
+type ClientMergeItem struct {
+	gorm.Model
+	UUIDModel  `gorm:"embedded"`
+	MergeID    uint   `gorm:"not null;index"`            // Cheie externă către ClientMerge
+	EntityType string `gorm:"type:varchar(20);not null"` // "contract", "order", "payment", "contact"
+	EntityID   uint   `gorm:"not null"`                  // ID-ul înregistrării mutate
+}
+
+// ****************************************************
+
+// Audit - Jurnal de audit
+// ********** AuditLog - Acțiune înregistrată în jurnalul de audit **********
+type AuditLog struct {
+	gorm.Model
+	UUIDModel  `gorm:"embedded"`
+	UserID     uint   `gorm:"not null;index"`                                        // Utilizatorul care a făcut acțiunea
+	Action     string `gorm:"type:varchar(50);not null;index"`                       // Acțiunea ("client.merge", "client.merge_undo" etc.)
+	EntityType string `gorm:"type:varchar(30);not null;index:idx_audit_logs_entity"` // Tipul entității ("client", "contract" etc.)
+	EntityID   uint   `gorm:"not null;index:idx_audit_logs_entity"`                  // ID-ul entității
+	Details    string `gorm:"type:text"`                                             // Detalii în format JSON
+	APIKeyID   *uint  `gorm:"default:null;index"`                                    // Cheia API cu care s-a făcut acțiunea (null - utilizatorul)
+}
+
+// ****************************************************
+
+// ********** Notification - Notificare pentru utilizator **********
+type Notification struct {
+	gorm.Model
+	UUIDModel  `gorm:"embedded"`
+	UserID     uint       `gorm:"not null;index"`             // Destinatarul
+	Type       string     `gorm:"type:varchar(50);not null"`  // Tipul ("contract.expiring", "contract.expired" etc.)
+	Title      string     `gorm:"type:varchar(200);not null"` // Titlul
+	Message    string     `gorm:"type:text"`                  // Textul notificării
+	EntityType string     `gorm:"type:varchar(30)"`           // Tipul entității vizate ("contract" etc.)
+	EntityID   uint       `gorm:"default:0"`                  // ID-ul entității vizate
+	ReadAt     *time.Time `gorm:"default:null"`               // Când a fost citită (nil - necitită)
+}
+
+// ****************************************************
+
+// Reports - Rapoarte (structuri fără tabel)
+// ********** VatSummary - Total pe categorie și rată TVA **********
+type VatSummary struct {
+	Category  string  `json:"category"`   // Categoria TVA
+	Rate      float64 `json:"rate"`       // Rata TVA
+	Currency  string  `json:"currency"`   // Moneda documentelor
+	Base      float64 `json:"base"`       // Baza impozabilă (fără TVA), în moneda documentelor
+	Vat       float64 `json:"vat"`        // Suma TVA, în moneda documentelor
+	Total     float64 `json:"total"`      // Suma cu TVA, în moneda documentelor
+	NetBase   float64 `json:"net_base"`   // Baza impozabilă în moneda de bază (MDL)
+	VatBase   float64 `json:"vat_base"`   // Suma TVA în moneda de bază (MDL)
+	TotalBase float64 `json:"total_base"` // Suma cu TVA în moneda de bază (MDL)
+}
+
+// ****************************************************
+
+// ********** IncomeTaxSummary - Impozit reținut pe beneficiar și lună **********
+type IncomeTaxSummary struct {
+	Period         string  `json:"period"`          // Luna ("2006-01")
+	ClientID       uint    `json:"client_id"`       // ID-ul beneficiarului
+	ClientName     string  `json:"client_name"`     // Numele beneficiarului
+	FiscalID       string  `json:"fiscal_id"`       // IDNP / codul fiscal al beneficiarului
+	IncomeTaxName  string  `json:"income_tax_name"` // Denumirea impozitului
+	IncomeTaxRate  float64 `json:"income_tax_rate"` // Rata impozitului
+	Payments       int     `json:"payments"`        // Numărul de plăți
+	Amount         float64 `json:"amount"`          // Suma brută
+	WithheldAmount float64 `json:"withheld_amount"` // Impozitul reținut
+	NetAmount      float64 `json:"net_amount"`      // Suma netă plătită
+}
+
+// ****************************************************
+
+// ********** ContractPriceComparison - Preț convenit comparat cu prețul de listă **********
+type ContractPriceComparison struct {
+	ContractPriceID uint       `json:"contract_price_id"` // Acordul de preț
+	ContractID      uint       `json:"contract_id"`       // Contractul
+	ContractNumber  string     `json:"contract_number"`   // Numărul contractului
+	ClientID        uint       `json:"client_id"`         // Clientul
+	ClientName      string     `json:"client_name"`       // Denumirea clientului
+	ProductID       uint       `json:"product_id"`        // Produsul
+	ProductName     string     `json:"product_name"`      // Denumirea produsului
+	Currency        string     `json:"currency"`          // Moneda contractului
+	ListPrice       float64    `json:"list_price"`        // Prețul de listă, în moneda contractului
+	AgreedPrice     float64    `json:"agreed_price"`      // Prețul convenit, în moneda contractului
+	Discount        *float64   `json:"discount"`          // Reducerea convenită, % (nil - preț fix)
+	Difference      float64    `json:"difference"`        // AgreedPrice - ListPrice
+	DifferencePct   float64    `json:"difference_pct"`    // Diferența față de prețul de listă, %
+	ValidFrom       time.Time  `json:"valid_from"`        // Valabil de la
+	ValidTo         *time.Time `json:"valid_to"`          // Valabil până la
+}
+
+// ****************************************************
+
+// ********** ClientSearchHit - Rezultat al căutării clienților **********
+type ClientSearchHit struct {
+	Client     Client            `json:"client"`     // Clientul găsit
+	Rank       float64           `json:"rank"`       // Relevanța (mai mare = potrivire mai bună)
+	Highlights []SearchHighlight `json:"highlights"` // Câmpurile potrivite, cu fragmentele marcate
+}
+
+// SearchHighlight - un câmp potrivit, cu fragmentele găsite încadrate în <mark></mark> (textul este HTML-escaped)
+type SearchHighlight struct {
+	Field string `json:"field"` // name, fiscal_id, phone, address, contact.name, contact.phone
+	Value string `json:"value"` // Valoarea câmpului cu marcaje
+}
+
+// ****************************************************
+
+// ********** ClientListFilter - Filtrele, sortarea și cursorul listei de clienți **********
+type ClientListFilter struct {
+	ClientTypeID      uint   // Doar clienții de acest tip (0 = toți)
+	ChannelID         uint   // Doar clienții acestui canal de vânzări (0 = toți)
+	HasActiveContract *bool  // Cu / fără contract activ (nil = toți)
+	Sort              string // Coloana de sortare: "name" sau "created_at"
+	Desc              bool   // Sortare descrescătoare
+	AfterValue        string // Cursor: valoarea coloanei de sortare a ultimului client din pagina precedentă
+	AfterID           uint   // Cursor: ID-ul ultimului client din pagina precedentă
+	Limit             int    // Numărul maxim de clienți
+}
+
+// ****************************************************
+
+// ********** ContractListFilter - Filtrele listei de contracte **********
+type ContractListFilter struct {
+	ClientID      uint       // Doar contractele clientului (0 = toți)
+	OwnerID       uint       // Doar contractele ownerului (0 = toți)
+	Status        string     // Doar contractele cu statutul dat ("" = toate)
+	Number        string     // Numărul începe cu
+	ActiveOn      *time.Time // Valabile la data dată (start_date <= dată <= end_date)
+	ExpiresBefore *time.Time // Cu end_date până la data dată (inclusiv)
+	Limit         int        // Numărul maxim de contracte
+	Offset        int        // Câte contracte se sar
+}
+
+// ****************************************************
+
+// ********** StatementLine - Rând din extrasul bancar **********
+type StatementLine struct {
+	Date        string  `json:"date"`        // Data operațiunii (YYYY-MM-DD)
+	Amount      float64 `json:"amount"`      // Suma (pozitivă - încasare, negativă - plată)
+	Currency    string  `json:"currency"`    // Moneda
+	IBAN        string  `json:"iban"`        // IBAN-ul contrapartidei
+	FiscalID    string  `json:"fiscal_code"` // Codul fiscal al contrapartidei
+	Name        string  `json:"name"`        // Denumirea contrapartidei din extras
+	Description string  `json:"description"` // Destinația plății
+}
+
+// StatementMatch - clientul identificat pentru un rând din extras
+type StatementMatch struct {
+	Line          StatementLine `json:"line"`
+	Status        string        `json:"status"`                    // "matched", "ambiguous", "unmatched"
+	MatchedBy     string        `json:"matched_by,omitempty"`      // "iban" sau "fiscal_code"
+	ClientID      *uint         `json:"client_id"`                 // Clientul găsit
+	ClientName    string        `json:"client_name,omitempty"`     // Denumirea clientului găsit
+	BankAccountID *uint         `json:"bank_account_id,omitempty"` // Contul bancar după care s-a găsit clientul
+	Candidates    []uint        `json:"candidates"`                // Clienții posibili (mai mulți la "ambiguous")
+}
+
+// ****************************************************
+
+// ********** InvalidFiscalIDEntry - Client cu cod fiscal invalid **********
+type InvalidFiscalIDEntry struct {
+	ClientID     uint   `json:"client_id"`     // ID-ul clientului
+	ClientName   string `json:"client_name"`   // Numele clientului
+	ClientType   string `json:"client_type"`   // Tipul clientului
+	FiscalID     string `json:"fiscal_id"`     // Codul fiscal salvat
+	ExpectedKind string `json:"expected_kind"` // Tipul de cod așteptat: IDNO, IDNP sau "" (oricare)
+	Reason       string `json:"reason"`        // invalid_format, invalid_checksum, wrong_kind
 }
 
 // ****************************************************
@@ -228,11 +937,3 @@ func (m *UUIDModel) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return
 }
-
-// AfterCreate hook pentru User - dacă este primul utilizator creat, îi setează rolul de admin
-func (u *User) AfterCreate(tx *gorm.DB) (err error) {
-	if u.ID == 1 {
-		tx.Model(u).Update("role", "admin")
-	}
-	return
-}
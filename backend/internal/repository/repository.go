@@ -1,23 +1,124 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"orders/internal/models"
+	"orders/internal/service"
 	"strings"
-
-	"github.com/google/uuid"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Repository - structura principală pentru acces la baza de date
 type Repository struct {
-	db *gorm.DB
+	db      *gorm.DB
+	trigram bool // extensia pg_trgm este instalată (altfel căutarea clienților folosește doar LIKE)
 }
 
 // Creează o nouă instanță de Repository cu conexiunea la DB
 func NewRepository(db *gorm.DB) *Repository {
-	return &Repository{db: db}
+	registerChannelScope(db)
+	registerAPIKeyAudit(db)
+	return &Repository{db: db, trigram: hasExtension(db, "pg_trgm")}
+}
+
+// hasExtension verifică dacă extensia Postgres este instalată în baza de date
+func hasExtension(db *gorm.DB, name string) bool {
+	var count int64
+	if err := db.Raw("SELECT COUNT(*) FROM pg_extension WHERE extname = ?", name).Scan(&count).Error; err != nil {
+		return false
+	}
+	return count > 0
+}
+
+// Cheia din context sub care se păstrează ID-ul cheii API a cererii
+type apiKeyKey struct{}
+
+// WithAPIKey întoarce repository-ul care scrie ID-ul cheii API în toate înregistrările de audit create prin el
+// (păstrează restricția pe canale, dacă există)
+func (repository *Repository) WithAPIKey(keyID uint) service.Repository {
+	ctx := repository.db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Repository{db: repository.db.WithContext(context.WithValue(ctx, apiKeyKey{}, keyID)), trigram: repository.trigram}
+}
+
+// registerAPIKeyAudit adaugă callback-ul GORM care completează api_key_id la crearea înregistrărilor de audit
+func registerAPIKeyAudit(db *gorm.DB) {
+	db.Callback().Create().Before("gorm:create").Register("api_key:audit", func(db *gorm.DB) {
+		if db.Error != nil || db.Statement.Table != "audit_logs" || db.Statement.Context == nil {
+			return
+		}
+		if keyID, ok := db.Statement.Context.Value(apiKeyKey{}).(uint); ok {
+			db.Statement.SetColumn("APIKeyID", &keyID)
+		}
+	})
+}
+
+// Cheia din context sub care se păstrează canalele vizibile utilizatorului (models.ChannelScope)
+type channelScopeKey struct{}
+
+// Tabelele ale căror înregistrări aparțin unui canal de vânzări (coloana channel_id)
+var channelScopedTables = map[string]bool{"clients": true, "contracts": true, "orders": true}
+
+// WithChannelScope întoarce repository-ul restrâns la canalele date: toate citirile, modificările
+// și ștergerile din clients, contracts și orders primesc condiția channel_id IN (...)
+func (repository *Repository) WithChannelScope(scope models.ChannelScope) service.Repository {
+	ctx := context.WithValue(context.Background(), channelScopeKey{}, scope)
+	return &Repository{db: repository.db.WithContext(ctx), trigram: repository.trigram}
+}
+
+// registerChannelScope adaugă callback-urile GORM care aplică restricția pe canale
+func registerChannelScope(db *gorm.DB) {
+	callbacks := db.Callback()
+	callbacks.Query().Before("gorm:query").Register("channel_scope:query", applyChannelScope)
+	callbacks.Row().Before("gorm:row").Register("channel_scope:row", applyChannelScope)
+	callbacks.Update().Before("gorm:update").Register("channel_scope:update", applyChannelScope)
+	callbacks.Delete().Before("gorm:delete").Register("channel_scope:delete", applyChannelScope)
+}
+
+// channelScopeIDs întoarce canalele la care este restrâns query-ul (restricted = false - fără restricții)
+func channelScopeIDs(db *gorm.DB) (ids []uint, restricted bool) {
+	if db.Statement.Context == nil {
+		return nil, false
+	}
+	scope, ok := db.Statement.Context.Value(channelScopeKey{}).(models.ChannelScope)
+	if !ok || scope.All {
+		return nil, false
+	}
+	return scope.ChannelIDs, true
+}
+
+func applyChannelScope(db *gorm.DB) {
+	if db.Error != nil || !channelScopedTables[db.Statement.Table] {
+		return
+	}
+	channelScopeOn(clause.CurrentTable)(db)
+}
+
+// channelScopeOn restrânge query-ul după coloana channel_id a tabelului dat
+// (pentru query-urile pe alte tabele, legate prin JOIN de clients / contracts / orders)
+func channelScopeOn(table string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		ids, restricted := channelScopeIDs(db)
+		if !restricted {
+			return db
+		}
+		values := make([]interface{}, len(ids))
+		for i, id := range ids {
+			values[i] = id
+		}
+		// Fără canale, IN () devine IN (NULL) și nu întoarce nimic
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.IN{Column: clause.Column{Table: table, Name: "channel_id"}, Values: values},
+		}})
+		return db
+	}
 }
 
 // Creează un nou utilizator în baza de date
@@ -32,17 +133,382 @@ func (repository *Repository) FindUserByEmail(email string) (*models.User, error
 	return &user, err
 }
 
-// Client methods
-func (repository *Repository) CreateClient(client *models.Client) error {
-	// Normalize email value
-	em := strings.TrimSpace(client.Email)
-	el := strings.ToLower(em)
-	if em == "" || el == "not inserted" || el == "not_inserted" || el == "n/a" || el == "none" {
-		// If the DB has an email column and it's NOT NULL, we must provide a non-null, unique value.
-		// Use a timestamp-based placeholder to avoid unique constraint collisions.
-		client.Email = fmt.Sprintf("placeholder_%s@local.invalid", uuid.New().String())
+func (repository *Repository) FindUserByID(id uint) (*models.User, error) {
+	var user models.User
+	err := repository.db.First(&user, id).Error
+	return &user, err
+}
+
+// ID-urile canalelor utilizatorului
+func (repository *Repository) FindUserChannelIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	err := repository.db.Table("user_channels").Where("user_id = ?", userID).Order("channel_id").Pluck("channel_id", &ids).Error
+	return ids, err
+}
+
+// Utilizatorii cu canalele lor (role = "" - toți), în ordinea emailului
+func (repository *Repository) FindUsers(role string) ([]models.User, error) {
+	var users []models.User
+	db := repository.db.Preload("Channels")
+	if role != "" {
+		db = db.Where("role = ?", role)
 	}
+	err := db.Order("email").Find(&users).Error
+	return users, err
+}
+
+func (repository *Repository) CountUsersByRole(role string) (int64, error) {
+	var count int64
+	err := repository.db.Model(&models.User{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
+// Schimbă rolul utilizatorului și înregistrează schimbarea în jurnalul de audit
+func (repository *Repository) UpdateUserRole(user *models.User, audit *models.AuditLog) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Update("role", user.Role).Error; err != nil {
+			return err
+		}
+		return tx.Create(audit).Error
+	})
+}
+
+// Invitation methods
+// Salvează invitația cu canalele ei și anulează invitațiile neacceptate trimise anterior pe același email
+func (repository *Repository) CreateInvitation(invitation *models.Invitation, audit func(invitation *models.Invitation) *models.AuditLog) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Invitation{}).
+			Where("email = ? AND accepted_at IS NULL AND revoked_at IS NULL", invitation.Email).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Channels.*").Create(invitation).Error; err != nil {
+			return err
+		}
+		return tx.Create(audit(invitation)).Error
+	})
+}
+
+// Invitațiile cu canalele lor, cele mai noi primele
+func (repository *Repository) FindInvitations() ([]models.Invitation, error) {
+	var invitations []models.Invitation
+	err := repository.db.Preload("Channels").Order("created_at DESC, id DESC").Find(&invitations).Error
+	return invitations, err
+}
+
+func (repository *Repository) FindInvitationByID(id uint) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := repository.db.Preload("Channels").First(&invitation, id).Error
+	return &invitation, err
+}
+
+func (repository *Repository) FindInvitationByTokenHash(hash string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := repository.db.Preload("Channels").Where("token_hash = ?", hash).First(&invitation).Error
+	return &invitation, err
+}
+
+func (repository *Repository) RevokeInvitation(invitation *models.Invitation, audit *models.AuditLog) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(invitation).Update("revoked_at", invitation.RevokedAt).Error; err != nil {
+			return err
+		}
+		return tx.Create(audit).Error
+	})
+}
 
+// AcceptInvitation marchează invitația ca acceptată și creează utilizatorul cu canalele lui.
+// Dacă invitația a fost deja acceptată sau anulată (token folosit de două ori), întoarce gorm.ErrRecordNotFound.
+func (repository *Repository) AcceptInvitation(invitation *models.Invitation, user *models.User, audit func(user *models.User) *models.AuditLog) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.Invitation{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", invitation.ID).
+			Update("accepted_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Omit("Channels.*").Create(user).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Invitation{}).Where("id = ?", invitation.ID).Update("user_id", user.ID).Error; err != nil {
+			return err
+		}
+		invitation.AcceptedAt, invitation.UserID = &now, &user.ID
+		return tx.Create(audit(user)).Error
+	})
+}
+
+// Session methods
+func (repository *Repository) CreateSession(session *models.Session) error {
+	return repository.db.Create(session).Error
+}
+
+func (repository *Repository) FindSessionByID(id uint) (*models.Session, error) {
+	var session models.Session
+	err := repository.db.First(&session, id).Error
+	return &session, err
+}
+
+// Sesiunea după hash-ul refresh token-ului actual sau al celui înlocuit la ultima reînnoire
+func (repository *Repository) FindSessionByTokenHash(hash string) (*models.Session, error) {
+	var session models.Session
+	err := repository.db.Where("token_hash = ? OR previous_token_hash = ?", hash, hash).First(&session).Error
+	return &session, err
+}
+
+// Sesiunile neanulate și neexpirate ale utilizatorului, cele folosite recent primele
+func (repository *Repository) FindActiveSessions(userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := repository.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_used_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// RotateSession salvează refresh token-ul nou doar dacă sesiunea are încă token-ul previousHash și nu e anulată;
+// altfel (două reînnoiri simultane cu același token) întoarce gorm.ErrRecordNotFound.
+func (repository *Repository) RotateSession(session *models.Session, previousHash string) error {
+	result := repository.db.Model(&models.Session{}).
+		Where("id = ? AND token_hash = ? AND revoked_at IS NULL", session.ID, previousHash).
+		Updates(map[string]interface{}{
+			"token_hash":          session.TokenHash,
+			"previous_token_hash": session.PreviousTokenHash,
+			"expires_at":          session.ExpiresAt,
+			"last_used_at":        session.LastUsedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (repository *Repository) RevokeSession(id uint) error {
+	return repository.db.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// Anulează toate sesiunile active ale utilizatorului și înregistrează acțiunea în jurnalul de audit
+func (repository *Repository) RevokeUserSessions(userID uint, audit func(revoked int64) *models.AuditLog) (int64, error) {
+	var revoked int64
+	err := repository.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		revoked = result.RowsAffected
+		return tx.Create(audit(revoked)).Error
+	})
+	return revoked, err
+}
+
+// Șterge definitiv sesiunile expirate
+func (repository *Repository) DeleteExpiredSessions(now time.Time) (int64, error) {
+	result := repository.db.Unscoped().Where("expires_at <= ?", now).Delete(&models.Session{})
+	return result.RowsAffected, result.Error
+}
+
+// Password methods
+// Schimbă parola utilizatorului și închide celelalte sesiuni ale lui (keepSessionID - sesiunea curentă)
+func (repository *Repository) UpdateUserPassword(user *models.User, keepSessionID uint, audit *models.AuditLog) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Update("password", user.Password).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Session{}).
+			Where("user_id = ? AND id <> ? AND revoked_at IS NULL", user.ID, keepSessionID).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(audit).Error
+	})
+}
+
+// Salvează cererea de resetare a parolei; linkurile trimise anterior utilizatorului nu mai sunt valabile
+func (repository *Repository) CreatePasswordReset(reset *models.PasswordReset) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.PasswordReset{}).
+			Where("user_id = ? AND used_at IS NULL", reset.UserID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(reset).Error
+	})
+}
+
+// FindLatestPasswordReset întoarce ultima cerere de resetare a parolei utilizatorului
+func (repository *Repository) FindLatestPasswordReset(userID uint) (*models.PasswordReset, error) {
+	var reset models.PasswordReset
+	err := repository.db.Where("user_id = ?", userID).Order("created_at DESC").First(&reset).Error
+	return &reset, err
+}
+
+func (repository *Repository) FindPasswordResetByTokenHash(hash string) (*models.PasswordReset, error) {
+	var reset models.PasswordReset
+	err := repository.db.Where("token_hash = ?", hash).First(&reset).Error
+	return &reset, err
+}
+
+// ResetPassword marchează token-ul ca folosit, setează parola nouă și închide toate sesiunile utilizatorului.
+// Dacă token-ul a fost deja folosit (două cereri simultane), întoarce gorm.ErrRecordNotFound.
+func (repository *Repository) ResetPassword(reset *models.PasswordReset, user *models.User, audit *models.AuditLog) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.PasswordReset{}).
+			Where("id = ? AND used_at IS NULL", reset.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Model(user).Update("password", user.Password).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		reset.UsedAt = &now
+		return tx.Create(audit).Error
+	})
+}
+
+// API key methods
+func (repository *Repository) CreateAPIKey(key *models.APIKey, audit func(key *models.APIKey) *models.AuditLog) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Channels.*").Create(key).Error; err != nil {
+			return err
+		}
+		return tx.Create(audit(key)).Error
+	})
+}
+
+// Cheile API cu canalele lor, cele mai noi primele
+func (repository *Repository) FindAPIKeys() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := repository.db.Preload("Channels").Order("created_at DESC, id DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (repository *Repository) FindAPIKeyByID(id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	err := repository.db.Preload("Channels").First(&key, id).Error
+	return &key, err
+}
+
+func (repository *Repository) FindAPIKeyByHash(hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := repository.db.Preload("Channels").Where("key_hash = ?", hash).First(&key).Error
+	return &key, err
+}
+
+func (repository *Repository) RevokeAPIKey(key *models.APIKey, audit *models.AuditLog) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(key).Update("revoked_at", key.RevokedAt).Error; err != nil {
+			return err
+		}
+		return tx.Create(audit).Error
+	})
+}
+
+// Notează ultima folosire a cheii (fără updated_at, care arată ultima modificare a cheii)
+func (repository *Repository) TouchAPIKey(id uint, usedAt time.Time, ip string) error {
+	return repository.db.Model(&models.APIKey{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"last_used_at": usedAt, "last_used_ip": ip}).Error
+}
+
+// Channel methods
+func (repository *Repository) CreateChannel(channel *models.Channel) error {
+	return repository.db.Omit(clause.Associations).Create(channel).Error
+}
+
+func (repository *Repository) FindChannelByID(id uint) (*models.Channel, error) {
+	var channel models.Channel
+	err := repository.db.First(&channel, id).Error
+	return &channel, err
+}
+
+func (repository *Repository) FindChannelByName(name string) (*models.Channel, error) {
+	var channel models.Channel
+	err := repository.db.Where("lower(name) = lower(?)", name).First(&channel).Error
+	return &channel, err
+}
+
+// Canalele după ID (ids = nil - toate canalele), în ordinea numelui
+func (repository *Repository) FindChannels(ids []uint) ([]models.Channel, error) {
+	var channels []models.Channel
+	db := repository.db
+	if ids != nil {
+		db = db.Where("id IN ?", ids)
+	}
+	err := db.Order("name").Find(&channels).Error
+	return channels, err
+}
+
+func (repository *Repository) UpdateChannel(channel *models.Channel) error {
+	return repository.db.Omit(clause.Associations).Save(channel).Error
+}
+
+func (repository *Repository) DeleteChannel(id uint) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM user_channels WHERE channel_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Channel{}, id).Error
+	})
+}
+
+// Numărul clienților, contractelor și comenzilor din canal (fără restricția pe canale a utilizatorului)
+func (repository *Repository) CountChannelRecords(id uint) (int64, error) {
+	var count int64
+	err := repository.db.Raw(`SELECT
+		(SELECT COUNT(*) FROM clients WHERE channel_id = @id AND deleted_at IS NULL) +
+		(SELECT COUNT(*) FROM contracts WHERE channel_id = @id AND deleted_at IS NULL) +
+		(SELECT COUNT(*) FROM orders WHERE channel_id = @id AND deleted_at IS NULL)`,
+		map[string]interface{}{"id": id}).Scan(&count).Error
+	return count, err
+}
+
+// Utilizatorii canalului
+func (repository *Repository) FindChannelUsers(channelID uint) ([]models.User, error) {
+	var users []models.User
+	err := repository.db.
+		Joins("JOIN user_channels ON user_channels.user_id = users.id").
+		Where("user_channels.channel_id = ?", channelID).
+		Order("users.email").
+		Find(&users).Error
+	return users, err
+}
+
+// AddChannelUsers adaugă utilizatorii în canal (cei care sunt deja membri se ignoră)
+func (repository *Repository) AddChannelUsers(channelID uint, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	rows := make([]map[string]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		rows[i] = map[string]interface{}{"user_id": id, "channel_id": channelID}
+	}
+	return repository.db.Table("user_channels").Clauses(clause.OnConflict{DoNothing: true}).Create(rows).Error
+}
+
+func (repository *Repository) RemoveChannelUser(channelID, userID uint) error {
+	return repository.db.Exec("DELETE FROM user_channels WHERE channel_id = ? AND user_id = ?", channelID, userID).Error
+}
+
+// Client methods
+func (repository *Repository) CreateClient(client *models.Client) error {
 	// Check if email column exists
 	if !repository.db.Migrator().HasColumn(client, "Email") {
 		return repository.db.Omit("Email").Create(client).Error
@@ -52,27 +518,410 @@ func (repository *Repository) CreateClient(client *models.Client) error {
 
 }
 
-func (repository *Repository) GetFirst1000Clients() ([]models.Client, error) {
-	var clients []models.Client
-	err := repository.db.Preload("ClientType").Limit(1000).Find(&clients).Error
-	return clients, err
+// clientListScope aplică filtrele listei de clienți (fără cursor și sortare)
+func clientListScope(filter models.ClientListFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.ClientTypeID != 0 {
+			db = db.Where("clients.client_type_id = ?", filter.ClientTypeID)
+		}
+		if filter.ChannelID != 0 {
+			db = db.Where("clients.channel_id = ?", filter.ChannelID)
+		}
+		if filter.HasActiveContract != nil {
+			exists := "EXISTS (SELECT 1 FROM contracts WHERE contracts.client_id = clients.id AND contracts.status = 'active' AND contracts.deleted_at IS NULL)"
+			if !*filter.HasActiveContract {
+				exists = "NOT " + exists
+			}
+			db = db.Where(exists)
+		}
+		return db
+	}
+}
+
+// Numărul total de clienți care corespund filtrelor
+func (repository *Repository) CountClients(filter models.ClientListFilter) (int64, error) {
+	var count int64
+	err := repository.db.Model(&models.Client{}).Scopes(clientListScope(filter)).Count(&count).Error
+	return count, err
 }
 
-func (repository *Repository) FindClientsByQuery(query string) ([]models.Client, error) {
-	if len(query) < 3 {
-		return []models.Client{}, nil // Return empty if less than 3 chars
+// StreamClients citește clienții rând cu rând (fără a-i încărca pe toți în memorie) și îi transmite funcției fn.
+// Paginarea este după cursor (keyset): clienții de după (AfterValue, AfterID) în ordinea sortării.
+func (repository *Repository) StreamClients(filter models.ClientListFilter, fn func(client *models.Client) error) error {
+	// Tipurile de client sunt puține: le citim o dată în loc de Preload pe fiecare rând
+	var clientTypes []models.ClientType
+	if err := repository.db.Find(&clientTypes).Error; err != nil {
+		return err
+	}
+	typesByID := make(map[uint]models.ClientType, len(clientTypes))
+	for _, ct := range clientTypes {
+		typesByID[ct.ID] = ct
+	}
+
+	column := "clients.name"
+	if filter.Sort == "created_at" {
+		column = "clients.created_at"
+	}
+	direction, compare := "ASC", ">"
+	if filter.Desc {
+		direction, compare = "DESC", "<"
+	}
+
+	query := repository.db.Model(&models.Client{}).Scopes(clientListScope(filter))
+	if filter.AfterID != 0 {
+		var after interface{} = filter.AfterValue
+		if filter.Sort == "created_at" {
+			t, err := time.Parse(time.RFC3339Nano, filter.AfterValue)
+			if err != nil {
+				return err
+			}
+			after = t
+		}
+		query = query.Where(fmt.Sprintf("(%s, clients.id) %s (?, ?)", column, compare), after, filter.AfterID)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	rows, err := query.Order(fmt.Sprintf("%s %s, clients.id %s", column, direction, direction)).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var client models.Client
+		if err := repository.db.ScanRows(rows, &client); err != nil {
+			return err
+		}
+		client.ClientType = typesByID[client.ClientTypeID]
+		if err := fn(&client); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Condiția de potrivire a căutării clienților. Folosește indexurile trigram create de migrarea
+// "2026_10_client_search_indexes": LIKE pe subșir și operatorul <% (word_similarity) pentru greșeli de tipar.
+const clientSearchFilter = `(
+	f_unaccent(lower(c.name)) LIKE @contains OR @term <% f_unaccent(lower(c.name))
+	OR lower(c.fiscal_id) LIKE @contains
+	OR lower(c.email) LIKE @contains
+	OR (@digits <> '' AND regexp_replace(c.phone, '\D', '', 'g') LIKE @digits_contains)
+	OR f_unaccent(lower(c.address)) LIKE @contains OR @term <% f_unaccent(lower(c.address))
+	OR EXISTS (
+		SELECT 1 FROM client_contacts cc
+		WHERE cc.client_id = c.id AND cc.deleted_at IS NULL AND (
+			f_unaccent(lower(cc.name)) LIKE @contains OR @term <% f_unaccent(lower(cc.name))
+			OR (@digits <> '' AND regexp_replace(cc.phone, '\D', '', 'g') LIKE @digits_contains)
+		)
+	)
+)`
+
+// Relevanța: cea mai bună potrivire dintre câmpuri. Codul fiscal exact și începutul numelui au prioritate,
+// adresa și persoanele de contact contează mai puțin decât numele clientului.
+const clientSearchRank = `GREATEST(
+	word_similarity(@term, f_unaccent(lower(c.name)))
+		+ CASE WHEN f_unaccent(lower(c.name)) LIKE @prefix THEN 0.5
+			WHEN f_unaccent(lower(c.name)) LIKE @contains THEN 0.25 ELSE 0 END,
+	CASE WHEN lower(c.fiscal_id) = @term THEN 2
+		WHEN lower(c.fiscal_id) LIKE @prefix THEN 1
+		WHEN lower(c.fiscal_id) LIKE @contains THEN 0.6 ELSE 0 END,
+	CASE WHEN lower(c.email) = @term THEN 2
+		WHEN lower(c.email) LIKE @prefix THEN 1
+		WHEN lower(c.email) LIKE @contains THEN 0.6 ELSE 0 END,
+	CASE WHEN @digits <> '' AND regexp_replace(c.phone, '\D', '', 'g') LIKE @digits_contains THEN 0.9 ELSE 0 END,
+	0.6 * COALESCE(word_similarity(@term, f_unaccent(lower(c.address))), 0),
+	0.7 * COALESCE((
+		SELECT MAX(GREATEST(
+			word_similarity(@term, f_unaccent(lower(cc.name))),
+			CASE WHEN @digits <> '' AND regexp_replace(cc.phone, '\D', '', 'g') LIKE @digits_contains THEN 1 ELSE 0 END
+		))
+		FROM client_contacts cc
+		WHERE cc.client_id = c.id AND cc.deleted_at IS NULL
+	), 0)
+)`
+
+// Condiția și relevanța fără pg_trgm (extensia nu a putut fi creată): doar potrivire pe subșir, fără greșeli de tipar
+const clientSearchFilterLike = `(
+	f_unaccent(lower(c.name)) LIKE @contains
+	OR lower(c.fiscal_id) LIKE @contains
+	OR lower(c.email) LIKE @contains
+	OR (@digits <> '' AND regexp_replace(c.phone, '\D', '', 'g') LIKE @digits_contains)
+	OR f_unaccent(lower(c.address)) LIKE @contains
+	OR EXISTS (
+		SELECT 1 FROM client_contacts cc
+		WHERE cc.client_id = c.id AND cc.deleted_at IS NULL AND (
+			f_unaccent(lower(cc.name)) LIKE @contains
+			OR (@digits <> '' AND regexp_replace(cc.phone, '\D', '', 'g') LIKE @digits_contains)
+		)
+	)
+)`
+
+const clientSearchRankLike = `GREATEST(
+	CASE WHEN f_unaccent(lower(c.name)) = @term THEN 1.5
+		WHEN f_unaccent(lower(c.name)) LIKE @prefix THEN 1
+		WHEN f_unaccent(lower(c.name)) LIKE @contains THEN 0.75 ELSE 0 END,
+	CASE WHEN lower(c.fiscal_id) = @term THEN 2
+		WHEN lower(c.fiscal_id) LIKE @prefix THEN 1
+		WHEN lower(c.fiscal_id) LIKE @contains THEN 0.6 ELSE 0 END,
+	CASE WHEN lower(c.email) = @term THEN 2
+		WHEN lower(c.email) LIKE @prefix THEN 1
+		WHEN lower(c.email) LIKE @contains THEN 0.6 ELSE 0 END,
+	CASE WHEN @digits <> '' AND regexp_replace(c.phone, '\D', '', 'g') LIKE @digits_contains THEN 0.9 ELSE 0 END,
+	CASE WHEN f_unaccent(lower(c.address)) LIKE @contains THEN 0.6 ELSE 0 END,
+	CASE WHEN EXISTS (
+		SELECT 1 FROM client_contacts cc
+		WHERE cc.client_id = c.id AND cc.deleted_at IS NULL AND (
+			f_unaccent(lower(cc.name)) LIKE @contains
+			OR (@digits <> '' AND regexp_replace(cc.phone, '\D', '', 'g') LIKE @digits_contains)
+		)
+	) THEN 0.7 ELSE 0 END
+)`
+
+// SearchClients caută clienții după nume, cod fiscal, email, telefon, adresă și persoanele de contact, ordonați după relevanță.
+// term trebuie să fie deja normalizat (litere mici, fără diacritice); digits - cifrele telefonului căutat sau "".
+// Întoarce pagina cerută și numărul total de rezultate.
+func (repository *Repository) SearchClients(term, digits string, limit, offset int) ([]models.ClientSearchHit, int64, error) {
+	params := map[string]interface{}{
+		"term":            term,
+		"contains":        "%" + escapeLike(term) + "%",
+		"prefix":          escapeLike(term) + "%",
+		"digits":          digits,
+		"digits_contains": "%" + digits + "%",
+		"limit":           limit,
+		"offset":          offset,
+	}
+
+	filter, rank := clientSearchFilter, clientSearchRank
+	if !repository.trigram {
+		filter, rank = clientSearchFilterLike, clientSearchRankLike
+	}
+	if ids, restricted := channelScopeIDs(repository.db); restricted {
+		filter = "c.channel_id IN @channels AND (" + filter + ")"
+		params["channels"] = ids
+	}
+
+	var ranked []struct {
+		ID   uint
+		Rank float64
+	}
+	var total int64
+	err := repository.db.Transaction(func(tx *gorm.DB) error {
+		// Pragul implicit 0.6 nu prinde greșelile de tipar din numele scurte
+		if repository.trigram {
+			if err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', '0.4', true)").Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Raw(`SELECT COUNT(*) FROM clients c WHERE c.deleted_at IS NULL AND `+filter, params).
+			Scan(&total).Error; err != nil {
+			return err
+		}
+		return tx.Raw(`SELECT c.id, `+rank+` AS rank
+			FROM clients c
+			WHERE c.deleted_at IS NULL AND `+filter+`
+			ORDER BY rank DESC, c.name, c.id
+			LIMIT @limit OFFSET @offset`, params).
+			Scan(&ranked).Error
+	})
+	if err != nil || len(ranked) == 0 {
+		return []models.ClientSearchHit{}, total, err
+	}
+
+	ids := make([]uint, len(ranked))
+	for i, r := range ranked {
+		ids[i] = r.ID
 	}
 	var clients []models.Client
-	err := repository.db.
-		Where("name ILIKE ? OR email ILIKE ?", "%"+query+"%", "%"+query+"%").
-		Limit(50).
-		Find(&clients).Error
-	return clients, err
+	if err := repository.db.Preload("ClientType").Preload("Contacts").Where("id IN ?", ids).Find(&clients).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uint]models.Client, len(clients))
+	for _, client := range clients {
+		byID[client.ID] = client
+	}
+
+	hits := make([]models.ClientSearchHit, 0, len(ranked))
+	for _, r := range ranked {
+		if client, ok := byID[r.ID]; ok {
+			hits = append(hits, models.ClientSearchHit{Client: client, Rank: r.Rank})
+		}
+	}
+	return hits, total, nil
+}
+
+// escapeLike protejează caracterele speciale ale operatorului LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 func (repository *Repository) FindClientByID(id uint) (*models.Client, error) {
 	var client models.Client
-	err := repository.db.Preload("ClientType").First(&client, id).Error
+	err := repository.db.Preload("ClientType").Preload("Contacts").Preload("BankAccounts", clientBankAccountsOrder).First(&client, id).Error
+	return &client, err
+}
+
+// Salvează câmpurile clientului, fără a atinge asocierile (tip, contracte, contacte)
+func (repository *Repository) UpdateClient(client *models.Client) error {
+	return repository.db.Omit(clause.Associations).Save(client).Error
+}
+
+// MoveClient salvează clientul mutat în alt canal și mută în același canal contractele și comenzile lui
+func (repository *Repository) MoveClient(client *models.Client) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(client).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Contract{}).Where("client_id = ?", client.ID).
+			Update("channel_id", client.ChannelID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Order{}).Where("client_id = ?", client.ID).
+			Update("channel_id", client.ChannelID).Error
+	})
+}
+
+// Parcurge toți clienții (cu tipul clientului) în loturi, fără a-i încărca pe toți în memorie
+func (repository *Repository) FindClientsInBatches(batchSize int, fn func(clients []models.Client) error) error {
+	var batch []models.Client
+	return repository.db.Preload("ClientType").
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// Ștergere soft (completează deleted_at)
+func (repository *Repository) DeleteClient(id uint) error {
+	return repository.db.Delete(&models.Client{}, id).Error
+}
+
+func (repository *Repository) CountActiveContractsByClient(clientID uint) (int64, error) {
+	var count int64
+	err := repository.db.Model(&models.Contract{}).
+		Where("client_id = ? AND status = ?", clientID, "active").
+		Count(&count).Error
+	return count, err
+}
+
+// Comenzile deschise sunt cele care nu au ajuns într-un statut final
+func (repository *Repository) CountOpenOrdersByClient(clientID uint) (int64, error) {
+	var count int64
+	err := repository.db.Model(&models.Order{}).
+		Where("client_id = ? AND status NOT IN ?", clientID, []string{"completed", "cancelled", "closed"}).
+		Count(&count).Error
+	return count, err
+}
+
+// ClientContact methods
+func (repository *Repository) CreateClientContact(contact *models.ClientContact) error {
+	return repository.db.Create(contact).Error
+}
+
+func (repository *Repository) FindClientContactByID(id uint) (*models.ClientContact, error) {
+	var contact models.ClientContact
+	err := repository.db.First(&contact, id).Error
+	return &contact, err
+}
+
+func (repository *Repository) UpdateClientContact(contact *models.ClientContact) error {
+	return repository.db.Save(contact).Error
+}
+
+func (repository *Repository) DeleteClientContact(id uint) error {
+	return repository.db.Delete(&models.ClientContact{}, id).Error
+}
+
+// ClientBankAccount methods
+// clientBankAccountsOrder - contul implicit primul, apoi în ordinea adăugării
+func clientBankAccountsOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("is_default DESC, id")
+}
+
+// unsetDefaultBankAccounts scoate marcajul de cont implicit de pe celelalte conturi ale clientului
+func unsetDefaultBankAccounts(tx *gorm.DB, account *models.ClientBankAccount) error {
+	return tx.Model(&models.ClientBankAccount{}).
+		Where("client_id = ? AND id <> ? AND is_default", account.ClientID, account.ID).
+		Update("is_default", false).Error
+}
+
+// Adaugă contul bancar; primul cont al clientului devine implicit
+func (repository *Repository) CreateClientBankAccount(account *models.ClientBankAccount) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.ClientBankAccount{}).Where("client_id = ?", account.ClientID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			account.IsDefault = true
+		}
+		if err := tx.Create(account).Error; err != nil {
+			return err
+		}
+		if account.IsDefault {
+			return unsetDefaultBankAccounts(tx, account)
+		}
+		return nil
+	})
+}
+
+func (repository *Repository) FindClientBankAccountByID(id uint) (*models.ClientBankAccount, error) {
+	var account models.ClientBankAccount
+	err := repository.db.First(&account, id).Error
+	return &account, err
+}
+
+// Conturile bancare active cu IBAN-ul dat, cu clienții lor (pentru identificarea plăților din extras)
+func (repository *Repository) FindClientBankAccountsByIBAN(iban string) ([]models.ClientBankAccount, error) {
+	var accounts []models.ClientBankAccount
+	err := repository.db.
+		Joins("JOIN clients ON clients.id = client_bank_accounts.client_id AND clients.deleted_at IS NULL").
+		Scopes(channelScopeOn("clients")).
+		Where("client_bank_accounts.iban = ?", iban).
+		Order("client_bank_accounts.id").
+		Find(&accounts).Error
+	return accounts, err
+}
+
+func (repository *Repository) UpdateClientBankAccount(account *models.ClientBankAccount) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(account).Error; err != nil {
+			return err
+		}
+		if account.IsDefault {
+			return unsetDefaultBankAccounts(tx, account)
+		}
+		return nil
+	})
+}
+
+// Șterge contul bancar; dacă era implicit, implicit devine cel mai vechi cont rămas
+func (repository *Repository) DeleteClientBankAccount(account *models.ClientBankAccount) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(account).Error; err != nil {
+			return err
+		}
+		if !account.IsDefault {
+			return nil
+		}
+		var next models.ClientBankAccount
+		err := tx.Where("client_id = ?", account.ClientID).Order("id").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_default", true).Error
+	})
+}
+
+// Caută clientul activ după email (fără diferență între litere mari și mici), în toate canalele:
+// indexul unic idx_clients_email_lower cuprinde doar clienții care nu sunt șterși logic
+func (repository *Repository) FindClientByEmail(email string) (*models.Client, error) {
+	var client models.Client
+	err := repository.db.WithContext(context.Background()).Where("lower(email) = lower(?)", email).First(&client).Error
 	return &client, err
 }
 
@@ -89,20 +938,264 @@ func (repository *Repository) CreateContract(contract *models.Contract) error {
 
 func (repository *Repository) FindContractByID(id uint) (*models.Contract, error) {
 	var contract models.Contract
-	err := repository.db.First(&contract, id).Error
+	err := repository.db.
+		Preload("Client").
+		Preload("Addresses.Locality").
+		Preload("Owner").
+		First(&contract, id).Error
 	return &contract, err
 }
 
+// contractListScope aplică filtrele listei de contracte (fără paginare)
+func contractListScope(filter models.ContractListFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.ClientID != 0 {
+			db = db.Where("contracts.client_id = ?", filter.ClientID)
+		}
+		if filter.OwnerID != 0 {
+			db = db.Where("contracts.owner_id = ?", filter.OwnerID)
+		}
+		if filter.Status != "" {
+			db = db.Where("contracts.status = ?", filter.Status)
+		}
+		if filter.Number != "" {
+			db = db.Where("contracts.number ILIKE ?", escapeLike(filter.Number)+"%")
+		}
+		if filter.ActiveOn != nil {
+			db = db.Where("contracts.start_date <= ? AND (contracts.end_date IS NULL OR contracts.end_date >= ?)", *filter.ActiveOn, *filter.ActiveOn)
+		}
+		if filter.ExpiresBefore != nil {
+			db = db.Where("contracts.end_date <= ?", *filter.ExpiresBefore)
+		}
+		return db
+	}
+}
+
+// Numărul total de contracte care corespund filtrelor
+func (repository *Repository) CountContracts(filter models.ContractListFilter) (int64, error) {
+	var count int64
+	err := repository.db.Model(&models.Contract{}).Scopes(contractListScope(filter)).Count(&count).Error
+	return count, err
+}
+
+// Contractele care corespund filtrelor, cu clientul, cele mai noi primele
+func (repository *Repository) FindContracts(filter models.ContractListFilter) ([]models.Contract, error) {
+	var contracts []models.Contract
+	err := repository.db.Scopes(contractListScope(filter)).
+		Preload("Client").
+		Order("contracts.date DESC, contracts.id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&contracts).Error
+	return contracts, err
+}
+
+// CreateContractAmendment salvează versiunea nouă împreună cu contractul actualizat și înregistrarea de audit
+func (repository *Repository) CreateContractAmendment(contract *models.Contract, amendment *models.ContractAmendment, audit *models.AuditLog) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(amendment).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(contract).Error; err != nil {
+			return err
+		}
+		return tx.Create(audit).Error
+	})
+}
+
+// Versiunile contractului, în ordinea numărului
+func (repository *Repository) FindContractAmendments(contractID uint) ([]models.ContractAmendment, error) {
+	var amendments []models.ContractAmendment
+	err := repository.db.Where("contract_id = ?", contractID).Order("version").Find(&amendments).Error
+	return amendments, err
+}
+
+func (repository *Repository) FindContractAmendment(contractID uint, version int) (*models.ContractAmendment, error) {
+	var amendment models.ContractAmendment
+	err := repository.db.Where("contract_id = ? AND version = ?", contractID, version).First(&amendment).Error
+	return &amendment, err
+}
+
+// Versiunea contractului în vigoare la data dată (ultima cu effective_from <= date)
+func (repository *Repository) FindContractVersionAt(contractID uint, date time.Time) (*models.ContractAmendment, error) {
+	var amendment models.ContractAmendment
+	err := repository.db.
+		Where("contract_id = ? AND effective_from <= ?", contractID, date).
+		Order("version DESC").
+		First(&amendment).Error
+	return &amendment, err
+}
+
+// UpdateContract salvează contractul și, dacă există, versiunea corectată și înregistrarea de audit a modificării
+func (repository *Repository) UpdateContract(contract *models.Contract, version *models.ContractAmendment, audit *models.AuditLog) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(contract).Error; err != nil {
+			return err
+		}
+		if version != nil {
+			if err := tx.Save(version).Error; err != nil {
+				return err
+			}
+		}
+		if audit != nil {
+			return tx.Create(audit).Error
+		}
+		return nil
+	})
+}
+
+// Contractele active sau suspendate a căror dată de sfârșit a trecut (end_date < today)
+func (repository *Repository) FindContractsToExpire(today time.Time) ([]models.Contract, error) {
+	var contracts []models.Contract
+	err := repository.db.
+		Where("status IN ? AND end_date < ?", []string{models.ContractStatusActive, models.ContractStatusSuspended}, today).
+		Order("id").
+		Find(&contracts).Error
+	return contracts, err
+}
+
+// Contractele active care expiră până la data until (inclusiv) și al căror owner nu a fost încă anunțat
+func (repository *Repository) FindContractsExpiringBy(until time.Time) ([]models.Contract, error) {
+	var contracts []models.Contract
+	err := repository.db.Preload("Client").
+		Where("status = ? AND end_date <= ? AND expiry_notified_at IS NULL", models.ContractStatusActive, until).
+		Order("end_date, id").
+		Find(&contracts).Error
+	return contracts, err
+}
+
+// MarkContractExpiryNotified salvează notificarea ownerului și marchează contractul ca anunțat
+func (repository *Repository) MarkContractExpiryNotified(contract *models.Contract, notification *models.Notification) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(notification).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Contract{}).Where("id = ?", contract.ID).
+			Update("expiry_notified_at", notification.CreatedAt).Error
+	})
+}
+
+// ContractTemplate methods
+func (repository *Repository) CreateContractTemplate(template *models.ContractTemplate) error {
+	return repository.db.Create(template).Error
+}
+
+func (repository *Repository) FindContractTemplateByID(id uint) (*models.ContractTemplate, error) {
+	var template models.ContractTemplate
+	err := repository.db.First(&template, id).Error
+	return &template, err
+}
+
+// Șabloanele de contract după denumire; activeOnly - doar cele care se pot folosi
+func (repository *Repository) FindContractTemplates(activeOnly bool) ([]models.ContractTemplate, error) {
+	query := repository.db
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	var templates []models.ContractTemplate
+	err := query.Order("name").Find(&templates).Error
+	return templates, err
+}
+
+// UpdateContractTemplate salvează șablonul și, dacă textul s-a schimbat, versiunea nouă
+func (repository *Repository) UpdateContractTemplate(template *models.ContractTemplate, version *models.ContractTemplateVersion) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if version != nil {
+			if err := tx.Create(version).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit(clause.Associations).Save(template).Error
+	})
+}
+
+// Versiunile șablonului, cea mai recentă prima
+func (repository *Repository) FindContractTemplateVersions(templateID uint) ([]models.ContractTemplateVersion, error) {
+	var versions []models.ContractTemplateVersion
+	err := repository.db.Where("template_id = ?", templateID).Order("version DESC").Find(&versions).Error
+	return versions, err
+}
+
+func (repository *Repository) FindContractTemplateVersion(templateID uint, version int) (*models.ContractTemplateVersion, error) {
+	var v models.ContractTemplateVersion
+	err := repository.db.Where("template_id = ? AND version = ?", templateID, version).First(&v).Error
+	return &v, err
+}
+
+// ContractDocument methods
+func (repository *Repository) CreateContractDocument(document *models.ContractDocument) error {
+	return repository.db.Omit(clause.Associations).Create(document).Error
+}
+
+// Documentele generate pentru contract, cu fișierele lor, cele mai recente primele
+func (repository *Repository) FindContractDocuments(contractID uint) ([]models.ContractDocument, error) {
+	var documents []models.ContractDocument
+	err := repository.db.Preload("Attachment").Where("contract_id = ?", contractID).Order("id DESC").Find(&documents).Error
+	return documents, err
+}
+
 func (repository *Repository) CreateContractAddress(addr *models.ContractAddress) error {
-	return repository.db.Create(addr).Error
+	return repository.db.Omit(clause.Associations).Create(addr).Error
 }
 
 func (repository *Repository) FindContractAddressByID(id uint) (*models.ContractAddress, error) {
 	var addr models.ContractAddress
-	err := repository.db.First(&addr, id).Error
+	err := repository.db.Preload("Locality").First(&addr, id).Error
 	return &addr, err
 }
 
+// Adresele contractului, cu localitățile lor
+func (repository *Repository) FindContractAddresses(contractID uint) ([]models.ContractAddress, error) {
+	var addresses []models.ContractAddress
+	err := repository.db.Preload("Locality").Where("contract_id = ?", contractID).Order("type, id").Find(&addresses).Error
+	return addresses, err
+}
+
+func (repository *Repository) UpdateContractAddress(addr *models.ContractAddress) error {
+	return repository.db.Omit(clause.Associations).Save(addr).Error
+}
+
+func (repository *Repository) DeleteContractAddress(id uint) error {
+	return repository.db.Delete(&models.ContractAddress{}, id).Error
+}
+
+// Locality methods
+
+// UpsertLocalities salvează localitățile clasificatorului; cele existente (după cod) se actualizează
+func (repository *Repository) UpsertLocalities(localities []models.Locality) error {
+	if len(localities) == 0 {
+		return nil
+	}
+	return repository.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "code"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"name":       gorm.Expr("excluded.name"),
+			"kind":       gorm.Expr("excluded.kind"),
+			"district":   gorm.Expr("excluded.district"),
+			"updated_at": gorm.Expr("excluded.updated_at"),
+			"deleted_at": nil, // Localitatea ștearsă revine dacă apare din nou în clasificator
+		}),
+	}).CreateInBatches(&localities, 500).Error
+}
+
+func (repository *Repository) FindLocalityByCode(code string) (*models.Locality, error) {
+	var locality models.Locality
+	err := repository.db.Where("code = ?", code).First(&locality).Error
+	return &locality, err
+}
+
+// SearchLocalities caută localitățile după denumire (fără diacritice) sau după începutul codului
+func (repository *Repository) SearchLocalities(query string, limit int) ([]models.Locality, error) {
+	var localities []models.Locality
+	db := repository.db
+	if query != "" {
+		db = db.Where("f_unaccent(lower(name)) LIKE f_unaccent(lower(?)) OR code LIKE ?",
+			"%"+escapeLike(query)+"%", escapeLike(query)+"%")
+	}
+	err := db.Order("name, code").Limit(limit).Find(&localities).Error
+	return localities, err
+}
+
 // Product methods
 func (repository *Repository) CreateProduct(product *models.Product) error {
 	return repository.db.Create(product).Error
@@ -116,16 +1209,152 @@ func (repository *Repository) FindProductGroupByID(id uint) (*models.ProductGrou
 
 func (repository *Repository) FindProductByID(id uint) (*models.Product, error) {
 	var product models.Product
-	err := repository.db.First(&product, id).Error
+	err := repository.db.Preload("Barcodes").First(&product, id).Error
+	return &product, err
+}
+
+func (repository *Repository) FindProductBySKU(sku string) (*models.Product, error) {
+	var product models.Product
+	err := repository.db.Where("sku = ?", sku).First(&product).Error
 	return &product, err
 }
 
+// Barcode methods
+func (repository *Repository) CreateProductBarcode(barcode *models.ProductBarcode) error {
+	return repository.db.Create(barcode).Error
+}
+
+// Găsește codul de bare împreună cu produsul și unitatea de ambalare
+func (repository *Repository) FindProductBarcode(code string) (*models.ProductBarcode, error) {
+	var barcode models.ProductBarcode
+	err := repository.db.
+		Preload("Product").
+		Preload("Product.Unit").
+		Preload("Product.VatTax").
+		Preload("Unit").
+		Where("barcode = ?", code).
+		First(&barcode).Error
+	return &barcode, err
+}
+
+func (repository *Repository) FindPriceProduct(productID, priceTypeID uint) (*models.PriceProduct, error) {
+	var price models.PriceProduct
+	err := repository.db.Where("product_id = ? AND price_type_id = ?", productID, priceTypeID).First(&price).Error
+	return &price, err
+}
+
+// ContractPrice methods
+func (repository *Repository) CreateContractPrice(price *models.ContractPrice) error {
+	return repository.db.Create(price).Error
+}
+
+func (repository *Repository) FindContractPriceByID(id uint) (*models.ContractPrice, error) {
+	var price models.ContractPrice
+	err := repository.db.Preload("Product").First(&price, id).Error
+	return &price, err
+}
+
+func (repository *Repository) UpdateContractPrice(price *models.ContractPrice) error {
+	return repository.db.Omit(clause.Associations).Save(price).Error
+}
+
+func (repository *Repository) DeleteContractPrice(id uint) error {
+	return repository.db.Delete(&models.ContractPrice{}, id).Error
+}
+
+// contractPriceValidAt - acordurile valabile la data dată
+func contractPriceValidAt(date time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("contract_prices.valid_from <= ? AND (contract_prices.valid_to IS NULL OR contract_prices.valid_to >= ?)", date, date)
+	}
+}
+
+// Acordurile de preț ale contractului (date != nil - doar cele valabile la acea dată), cu produsul
+func (repository *Repository) FindContractPrices(contractID uint, date *time.Time) ([]models.ContractPrice, error) {
+	query := repository.db.Preload("Product").Where("contract_id = ?", contractID)
+	if date != nil {
+		query = query.Scopes(contractPriceValidAt(*date))
+	}
+	var prices []models.ContractPrice
+	err := query.Order("product_id, valid_from").Find(&prices).Error
+	return prices, err
+}
+
+// Numărul acordurilor aceluiași produs din contract a căror perioadă se suprapune cu cea a acordului dat
+func (repository *Repository) CountOverlappingContractPrices(price *models.ContractPrice) (int64, error) {
+	query := repository.db.Model(&models.ContractPrice{}).
+		Where("contract_id = ? AND product_id = ? AND id <> ?", price.ContractID, price.ProductID, price.ID).
+		Where("valid_to IS NULL OR valid_to >= ?", price.ValidFrom)
+	if price.ValidTo != nil {
+		query = query.Where("valid_from <= ?", *price.ValidTo)
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// Acordul de preț al produsului valabil în contract la data dată
+func (repository *Repository) FindContractPriceAt(contractID, productID uint, date time.Time) (*models.ContractPrice, error) {
+	var price models.ContractPrice
+	err := repository.db.Scopes(contractPriceValidAt(date)).
+		Where("contract_id = ? AND product_id = ?", contractID, productID).
+		Order("valid_from DESC").
+		First(&price).Error
+	return &price, err
+}
+
+// Acordurile valabile la data dată din contractele active (filtrate după contract / client, 0 - toate),
+// cu contractul, clientul și produsul
+func (repository *Repository) FindContractPricesValidAt(contractID, clientID uint, date time.Time) ([]models.ContractPrice, error) {
+	query := repository.db.
+		Joins("JOIN contracts ON contracts.id = contract_prices.contract_id AND contracts.deleted_at IS NULL").
+		Where("contracts.status = ?", models.ContractStatusActive).
+		Scopes(contractPriceValidAt(date), channelScopeOn("contracts"))
+	if contractID != 0 {
+		query = query.Where("contract_prices.contract_id = ?", contractID)
+	}
+	if clientID != 0 {
+		query = query.Where("contracts.client_id = ?", clientID)
+	}
+	var prices []models.ContractPrice
+	err := query.
+		Preload("Contract").
+		Preload("Contract.Client").
+		Preload("Product").
+		Order("contract_prices.contract_id, contract_prices.product_id").
+		Find(&prices).Error
+	return prices, err
+}
+
 func (repository *Repository) FindVatTaxByID(id uint) (*models.VatTax, error) {
 	var vatTax models.VatTax
 	err := repository.db.First(&vatTax, id).Error
 	return &vatTax, err
 }
 
+func (repository *Repository) FindAllVatTaxes() ([]models.VatTax, error) {
+	var vatTaxes []models.VatTax
+	err := repository.db.
+		Preload("Rates", func(db *gorm.DB) *gorm.DB { return db.Order("valid_from") }).
+		Order("id").
+		Find(&vatTaxes).Error
+	return vatTaxes, err
+}
+
+// Găsește versiunea ratei TVA în vigoare la data dată (ultima cu valid_from <= data)
+func (repository *Repository) FindVatTaxRateAt(vatTaxID uint, date time.Time) (*models.VatTaxRate, error) {
+	var rate models.VatTaxRate
+	err := repository.db.
+		Where("vat_tax_id = ? AND valid_from <= ?", vatTaxID, date).
+		Order("valid_from DESC").
+		First(&rate).Error
+	return &rate, err
+}
+
+func (repository *Repository) CreateVatTaxRate(rate *models.VatTaxRate) error {
+	return repository.db.Create(rate).Error
+}
+
 func (repository *Repository) FindUnitByID(id uint) (*models.Unit, error) {
 	var unit models.Unit
 	err := repository.db.First(&unit, id).Error
@@ -143,8 +1372,569 @@ func (repository *Repository) FindOrdersByUserID(userID uint) ([]models.Order, e
 	return orders, err
 }
 
+// Schimbă statusul comenzii și înregistrează schimbarea în jurnalul de audit
+func (repository *Repository) UpdateOrderStatus(order *models.Order, audit *models.AuditLog) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(order).Update("status", order.Status).Error; err != nil {
+			return err
+		}
+		return tx.Create(audit).Error
+	})
+}
+
 func (repository *Repository) FindOrderByID(id uint) (*models.Order, error) {
 	var order models.Order
 	err := repository.db.Preload("OrderItems").First(&order, id).Error
 	return &order, err
 }
+
+// Găsește comanda cu toate datele necesare pentru documentul tipărit
+func (repository *Repository) FindOrderDocument(id uint) (*models.Order, error) {
+	var order models.Order
+	err := repository.db.
+		Preload("Client").
+		Preload("Client.BankAccounts", clientBankAccountsOrder).
+		Preload("Contract").
+		Preload("OrderItems").
+		Preload("OrderItems.Product").
+		First(&order, id).Error
+	return &order, err
+}
+
+// Payment methods
+func (repository *Repository) CreatePayment(payment *models.Payment) error {
+	return repository.db.Create(payment).Error
+}
+
+func (repository *Repository) FindPaymentByID(id uint) (*models.Payment, error) {
+	var payment models.Payment
+	err := repository.db.
+		Preload("Client").
+		Preload("Contract").
+		Preload("IncomeTax").
+		First(&payment, id).Error
+	return &payment, err
+}
+
+func (repository *Repository) FindIncomeTaxByID(id uint) (*models.IncomeTax, error) {
+	var incomeTax models.IncomeTax
+	err := repository.db.First(&incomeTax, id).Error
+	return &incomeTax, err
+}
+
+func (repository *Repository) FindClientTypeByID(id uint) (*models.ClientType, error) {
+	var clientType models.ClientType
+	err := repository.db.First(&clientType, id).Error
+	return &clientType, err
+}
+
+// Exchange rate methods
+// Salvează cursurile; cursul existent pentru aceeași monedă și dată se actualizează
+func (repository *Repository) UpsertExchangeRates(rates []models.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return repository.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"nominal", "rate", "updated_at"}),
+	}).Create(&rates).Error
+}
+
+// Găsește cursul în vigoare la data dată (ultimul publicat până la acea dată inclusiv)
+func (repository *Repository) FindExchangeRateAt(currency string, date time.Time) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := repository.db.
+		Where("currency = ? AND date <= ?", currency, date).
+		Order("date DESC").
+		First(&rate).Error
+	return &rate, err
+}
+
+func (repository *Repository) FindExchangeRatesByDate(date time.Time) ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	err := repository.db.Where("date = ?", date).Order("currency").Find(&rates).Error
+	return rates, err
+}
+
+// Report methods
+// Totaluri TVA pe categorie și rată pentru comenzile din perioada [from, to]
+func (repository *Repository) VatReport(from, to time.Time) ([]models.VatSummary, error) {
+	var rows []models.VatSummary
+	err := repository.db.
+		Table("order_items").
+		Select(`order_items.vat_category AS category, order_items.vat_rate AS rate, orders.currency AS currency,
+			SUM(order_items.summ) AS base, SUM(order_items.vat_summ) AS vat, SUM(order_items.summ_with_vat) AS total,
+			SUM(order_items.summ_base) AS net_base, SUM(order_items.vat_summ_base) AS vat_base,
+			SUM(order_items.summ_with_vat_base) AS total_base`).
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Scopes(channelScopeOn("orders")).
+		Where("order_items.deleted_at IS NULL AND orders.date BETWEEN ? AND ?", from, to).
+		Group("order_items.vat_category, order_items.vat_rate, orders.currency").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// Attachment methods
+func (repository *Repository) CreateAttachment(attachment *models.Attachment) error {
+	return repository.db.Create(attachment).Error
+}
+
+func (repository *Repository) FindAttachmentByID(id uint) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := repository.db.First(&attachment, id).Error
+	return &attachment, err
+}
+
+func (repository *Repository) FindAttachmentsByOwner(ownerType string, ownerID uint) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := repository.db.
+		Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Order("created_at").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+func (repository *Repository) DeleteAttachment(id uint) error {
+	return repository.db.Delete(&models.Attachment{}, id).Error
+}
+
+// Impozitul pe venit reținut, grupat pe lună și beneficiar, pentru plățile din perioada [from, to]
+func (repository *Repository) IncomeTaxReport(from, to time.Time) ([]models.IncomeTaxSummary, error) {
+	var rows []models.IncomeTaxSummary
+	err := repository.db.
+		Table("payments").
+		Select(`to_char(payments.date, 'YYYY-MM') AS period,
+			clients.id AS client_id, clients.name AS client_name, clients.fiscal_id AS fiscal_id,
+			income_taxes.name AS income_tax_name, payments.income_tax_rate AS income_tax_rate,
+			COUNT(*) AS payments, SUM(payments.amount) AS amount,
+			SUM(payments.withheld_amount) AS withheld_amount, SUM(payments.net_amount) AS net_amount`).
+		Joins("JOIN clients ON clients.id = payments.client_id").
+		Joins("JOIN income_taxes ON income_taxes.id = payments.income_tax_id").
+		Scopes(channelScopeOn("clients")).
+		Where("payments.deleted_at IS NULL AND payments.direction = ? AND payments.date BETWEEN ? AND ?",
+			models.PaymentOutgoing, from, to).
+		Group("period, clients.id, clients.name, clients.fiscal_id, income_taxes.name, payments.income_tax_rate").
+		Order("period, clients.name").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// Import methods
+func (repository *Repository) CreateImport(imp *models.Import) error {
+	return repository.db.Create(imp).Error
+}
+
+func (repository *Repository) FindImportByID(id uint) (*models.Import, error) {
+	var imp models.Import
+	err := repository.db.First(&imp, id).Error
+	return &imp, err
+}
+
+func (repository *Repository) FindAllClientTypes() ([]models.ClientType, error) {
+	var clientTypes []models.ClientType
+	err := repository.db.Find(&clientTypes).Error
+	return clientTypes, err
+}
+
+// Exchange (1C) methods
+// Înregistrările primite din 1C se găsesc după UUID (Ид-ul din 1C) și se salvează fără asocieri
+func (repository *Repository) FindProductGroupByUUID(uuid string) (*models.ProductGroup, error) {
+	var group models.ProductGroup
+	err := repository.db.Where("uuid = ?", uuid).First(&group).Error
+	return &group, err
+}
+
+func (repository *Repository) FindProductGroupByName(name string) (*models.ProductGroup, error) {
+	var group models.ProductGroup
+	err := repository.db.Where("name = ?", name).First(&group).Error
+	return &group, err
+}
+
+func (repository *Repository) SaveProductGroup(group *models.ProductGroup) error {
+	return repository.db.Omit(clause.Associations).Save(group).Error
+}
+
+func (repository *Repository) FindPriceTypeByUUID(uuid string) (*models.PriceType, error) {
+	var priceType models.PriceType
+	err := repository.db.Where("uuid = ?", uuid).First(&priceType).Error
+	return &priceType, err
+}
+
+func (repository *Repository) FindPriceTypeByName(name string) (*models.PriceType, error) {
+	var priceType models.PriceType
+	err := repository.db.Where("name = ?", name).First(&priceType).Error
+	return &priceType, err
+}
+
+func (repository *Repository) SavePriceType(priceType *models.PriceType) error {
+	return repository.db.Save(priceType).Error
+}
+
+func (repository *Repository) FindProductByUUID(uuid string) (*models.Product, error) {
+	var product models.Product
+	err := repository.db.Where("uuid = ?", uuid).First(&product).Error
+	return &product, err
+}
+
+func (repository *Repository) SaveProduct(product *models.Product) error {
+	return repository.db.Omit(clause.Associations).Save(product).Error
+}
+
+func (repository *Repository) SavePriceProduct(price *models.PriceProduct) error {
+	return repository.db.Omit(clause.Associations).Save(price).Error
+}
+
+// Unitatea de măsură după denumire, fără a ține cont de majuscule ("шт" = "ШТ")
+func (repository *Repository) FindUnitByName(name string) (*models.Unit, error) {
+	var unit models.Unit
+	err := repository.db.Where("lower(name) = lower(?)", name).First(&unit).Error
+	return &unit, err
+}
+
+func (repository *Repository) CreateUnit(unit *models.Unit) error {
+	return repository.db.Create(unit).Error
+}
+
+func (repository *Repository) FindClientByUUID(uuid string) (*models.Client, error) {
+	var client models.Client
+	err := repository.db.Where("uuid = ?", uuid).First(&client).Error
+	return &client, err
+}
+
+// Comenzile modificate după momentul since (nil = toate comenzile active), cu tot ce trebuie pentru export.
+// La exportul incremental se includ și comenzile șterse, ca 1C să le poată marca pentru ștergere.
+func (repository *Repository) FindOrdersChangedSince(since *time.Time) ([]models.Order, error) {
+	query := repository.db
+	if since != nil {
+		query = query.Unscoped().Where("orders.updated_at > ? OR orders.deleted_at > ?", *since, *since)
+	}
+	var orders []models.Order
+	err := query.
+		Preload("Client").
+		Preload("Contract").
+		Preload("OrderItems").
+		Preload("OrderItems.Product").
+		Preload("OrderItems.Unit").
+		Order("orders.id").
+		Find(&orders).Error
+	return orders, err
+}
+
+func (repository *Repository) CreateExchangeLog(log *models.ExchangeLog) error {
+	return repository.db.Create(log).Error
+}
+
+// Ultimul schimb reușit de tipul dat
+func (repository *Repository) FindLastExchangeLog(system, direction, kind string) (*models.ExchangeLog, error) {
+	var log models.ExchangeLog
+	err := repository.db.
+		Where("system = ? AND direction = ? AND kind = ? AND status = ?", system, direction, kind, "success").
+		Order("started_at DESC").
+		First(&log).Error
+	return &log, err
+}
+
+func (repository *Repository) FindExchangeLogs(system string, limit int) ([]models.ExchangeLog, error) {
+	var logs []models.ExchangeLog
+	err := repository.db.Where("system = ?", system).Order("started_at DESC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+// Perechile de clienți activi cu nume asemănătoare (trigrame, pragul minNameSimilarity) sau cu aceleași
+// ultime 8 cifre ale telefonului. Întoarce doar ID-urile (ClientAID < ClientBID); scorul îl calculează serviciul.
+// Fără pg_trgm se compară doar numele identice (fără diacritice).
+func (repository *Repository) FindDuplicatePairs(minNameSimilarity float64) ([]models.DuplicateCandidate, error) {
+	var pairs []models.DuplicateCandidate
+	nameMatch := "f_unaccent(lower(a.name)) = f_unaccent(lower(b.name))"
+	if repository.trigram {
+		nameMatch = "f_unaccent(lower(a.name)) % f_unaccent(lower(b.name))"
+	}
+	err := repository.db.Transaction(func(tx *gorm.DB) error {
+		if repository.trigram {
+			if err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)",
+				fmt.Sprintf("%.2f", minNameSimilarity)).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Raw(`
+			SELECT a.id AS client_a_id, b.id AS client_b_id
+			FROM clients a
+			JOIN clients b ON b.id > a.id AND ` + nameMatch + `
+			WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL
+			UNION
+			SELECT a.id, b.id
+			FROM clients a
+			JOIN clients b ON b.id > a.id
+				AND right(regexp_replace(a.phone, '\D', '', 'g'), 8) = right(regexp_replace(b.phone, '\D', '', 'g'), 8)
+			WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL
+				AND length(regexp_replace(a.phone, '\D', '', 'g')) >= 6
+			ORDER BY 1, 2`).
+			Scan(&pairs).Error
+	})
+	return pairs, err
+}
+
+func (repository *Repository) FindClientsByIDs(ids []uint) ([]models.Client, error) {
+	var clients []models.Client
+	err := repository.db.Where("id IN ?", ids).Find(&clients).Error
+	return clients, err
+}
+
+// Salvează perechile detectate. Perechile existente își păstrează statutul (o pereche respinsă nu se redeschide),
+// se actualizează doar scorurile și data detectării.
+func (repository *Repository) UpsertDuplicateCandidates(candidates []models.DuplicateCandidate) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+	return repository.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "client_a_id"}, {Name: "client_b_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"score", "name_score", "phone_score", "address_score", "detected_at", "updated_at"}),
+	}).CreateInBatches(candidates, 500).Error
+}
+
+// Șterge perechile deschise care nu au mai fost găsite la ultima detectare (clienți corectați sau șterși)
+func (repository *Repository) DeleteStaleDuplicateCandidates(detectedBefore time.Time) error {
+	return repository.db.Unscoped().
+		Where("status = ? AND detected_at < ?", models.DuplicateOpen, detectedBefore).
+		Delete(&models.DuplicateCandidate{}).Error
+}
+
+func (repository *Repository) FindDuplicateCandidates(status string, minScore float64, limit int) ([]models.DuplicateCandidate, error) {
+	query := repository.db.Preload("ClientA").Preload("ClientB").Where("score >= ?", minScore)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var candidates []models.DuplicateCandidate
+	err := query.Order("score DESC, id").Limit(limit).Find(&candidates).Error
+	return candidates, err
+}
+
+func (repository *Repository) FindDuplicateCandidateByID(id uint) (*models.DuplicateCandidate, error) {
+	var candidate models.DuplicateCandidate
+	err := repository.db.First(&candidate, id).Error
+	return &candidate, err
+}
+
+func (repository *Repository) UpdateDuplicateCandidateStatus(id uint, status string) error {
+	return repository.db.Model(&models.DuplicateCandidate{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// Tabelele cu înregistrări care aparțin clientului și se mută la unire
+var clientMergeTables = []struct {
+	entityType string
+	table      string
+}{
+	{"contract", "contracts"},
+	{"order", "orders"},
+	{"payment", "payments"},
+	{"contact", "client_contacts"},
+	{"bank_account", "client_bank_accounts"},
+}
+
+// MergeClients mută contractele, comenzile, plățile, persoanele de contact și conturile bancare ale clientului merge.MergedID
+// la merge.SurvivorID și șterge logic clientul unit. Totul se face într-o singură tranzacție,
+// împreună cu înregistrarea unirii (cu lista înregistrărilor mutate) și jurnalul de audit;
+// audit primește unirea completată (ID, numărul înregistrărilor mutate).
+func (repository *Repository) MergeClients(merge *models.ClientMerge, audit func(merge *models.ClientMerge) *models.AuditLog) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		for _, t := range clientMergeTables {
+			var ids []uint
+			// Se mută și înregistrările șterse logic, ca istoricul să rămână la clientul care rămâne
+			if err := tx.Table(t.table).Where("client_id = ?", merge.MergedID).Order("id").Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			if err := tx.Table(t.table).Where("id IN ?", ids).
+				Updates(map[string]interface{}{"client_id": merge.SurvivorID, "updated_at": time.Now()}).Error; err != nil {
+				return err
+			}
+			for _, id := range ids {
+				merge.Items = append(merge.Items, models.ClientMergeItem{EntityType: t.entityType, EntityID: id})
+			}
+			switch t.entityType {
+			case "contract":
+				merge.Contracts = len(ids)
+			case "order":
+				merge.Orders = len(ids)
+			case "payment":
+				merge.Payments = len(ids)
+			case "contact":
+				merge.Contacts = len(ids)
+			case "bank_account":
+				merge.BankAccounts = len(ids)
+			}
+		}
+		// Clientul care rămâne are un singur cont implicit
+		if err := repository.fixDefaultBankAccount(tx, merge.SurvivorID); err != nil {
+			return err
+		}
+
+		if err := tx.Create(merge).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Client{}, merge.MergedID).Error; err != nil {
+			return err
+		}
+
+		a, b := merge.SurvivorID, merge.MergedID
+		if a > b {
+			a, b = b, a
+		}
+		if err := tx.Model(&models.DuplicateCandidate{}).
+			Where("client_a_id = ? AND client_b_id = ?", a, b).
+			Update("status", models.DuplicateMerged).Error; err != nil {
+			return err
+		}
+		return tx.Create(audit(merge)).Error
+	})
+}
+
+// ClientIdentityTaken verifică dacă un alt client activ are codul fiscal sau emailul dat. Se caută în toate
+// canalele (fără restricția utilizatorului), ca indexurile unice idx_clients_fiscal_id_active și idx_clients_email_lower.
+func (repository *Repository) ClientIdentityTaken(fiscalID string, email *string, exceptID uint) (bool, error) {
+	db := repository.db.WithContext(context.Background()).Model(&models.Client{}).Where("id <> ?", exceptID)
+	if email != nil && *email != "" {
+		db = db.Where("fiscal_id = ? OR lower(email) = lower(?)", fiscalID, *email)
+	} else {
+		db = db.Where("fiscal_id = ?", fiscalID)
+	}
+	var count int64
+	err := db.Count(&count).Error
+	return count > 0, err
+}
+
+// UndoClientMerge restabilește clientul unit și îi mută înapoi înregistrările mutate la unire.
+// Înregistrările create ulterior pentru clientul care a rămas nu se mută.
+func (repository *Repository) UndoClientMerge(merge *models.ClientMerge, audit *models.AuditLog) error {
+	return repository.db.Transaction(func(tx *gorm.DB) error {
+		for _, t := range clientMergeTables {
+			var ids []uint
+			for _, item := range merge.Items {
+				if item.EntityType == t.entityType {
+					ids = append(ids, item.EntityID)
+				}
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			if err := tx.Table(t.table).Where("id IN ? AND client_id = ?", ids, merge.SurvivorID).
+				Updates(map[string]interface{}{"client_id": merge.MergedID, "updated_at": time.Now()}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Unscoped().Model(&models.Client{}).Where("id = ?", merge.MergedID).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		for _, clientID := range []uint{merge.SurvivorID, merge.MergedID} {
+			if err := repository.fixDefaultBankAccount(tx, clientID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(merge).Select("undone_at", "undone_by_id").Updates(merge).Error; err != nil {
+			return err
+		}
+		if merge.CandidateID != nil {
+			if err := tx.Model(&models.DuplicateCandidate{}).Where("id = ?", *merge.CandidateID).
+				Update("status", models.DuplicateOpen).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(audit).Error
+	})
+}
+
+// fixDefaultBankAccount lasă clientului exact un cont implicit (cel mai vechi dintre cele marcate
+// sau, dacă niciunul nu este marcat, cel mai vechi cont), după mutarea conturilor între clienți
+func (repository *Repository) fixDefaultBankAccount(tx *gorm.DB, clientID uint) error {
+	var ids []uint
+	if err := tx.Model(&models.ClientBankAccount{}).Where("client_id = ?", clientID).
+		Order("is_default DESC, id").Pluck("id", &ids).Error; err != nil || len(ids) == 0 {
+		return err
+	}
+	if err := tx.Model(&models.ClientBankAccount{}).Where("client_id = ? AND id <> ?", clientID, ids[0]).
+		Update("is_default", false).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.ClientBankAccount{}).Where("id = ?", ids[0]).Update("is_default", true).Error
+}
+
+// Unirea cu înregistrările mutate; clienții se încarcă și dacă sunt șterși logic
+func (repository *Repository) FindClientMergeByID(id uint) (*models.ClientMerge, error) {
+	var merge models.ClientMerge
+	err := repository.db.
+		Preload("Survivor", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Merged", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Items").
+		First(&merge, id).Error
+	return &merge, err
+}
+
+// Unirile în care a participat clientul (ca cel care rămâne sau ca cel unit), cele mai recente primele
+func (repository *Repository) FindClientMerges(clientID uint) ([]models.ClientMerge, error) {
+	var merges []models.ClientMerge
+	err := repository.db.
+		Preload("Survivor", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Merged", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("survivor_id = ? OR merged_id = ?", clientID, clientID).
+		Order("id DESC").
+		Find(&merges).Error
+	return merges, err
+}
+
+func (repository *Repository) CreateAuditLog(log *models.AuditLog) error {
+	return repository.db.Create(log).Error
+}
+
+// Jurnalul de audit, filtrat după tipul și ID-ul entității (valorile goale nu filtrează)
+func (repository *Repository) FindAuditLogs(entityType string, entityID uint, limit int) ([]models.AuditLog, error) {
+	query := repository.db
+	if entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+	if entityID != 0 {
+		query = query.Where("entity_id = ?", entityID)
+	}
+	var logs []models.AuditLog
+	err := query.Order("id DESC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+// Notification methods
+func (repository *Repository) CreateNotification(notification *models.Notification) error {
+	return repository.db.Create(notification).Error
+}
+
+// Notificările utilizatorului, cele mai recente primele; unreadOnly - doar cele necitite
+func (repository *Repository) FindNotifications(userID uint, unreadOnly bool, limit int) ([]models.Notification, error) {
+	query := repository.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	var notifications []models.Notification
+	err := query.Order("id DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+// MarkNotificationRead marchează notificarea utilizatorului ca citită; gorm.ErrRecordNotFound dacă nu există
+func (repository *Repository) MarkNotificationRead(userID, id uint, at time.Time) error {
+	result := repository.db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := repository.db.Model(&models.Notification{}).Where("id = ? AND user_id = ?", id, userID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"orders/internal/models"
+	"orders/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sqlRecorder - logger-ul GORM care păstrează instrucțiunile SQL generate (cu DryRun nu se execută nimic)
+type sqlRecorder struct {
+	statements []string
+}
+
+func (r *sqlRecorder) LogMode(logger.LogLevel) logger.Interface      { return r }
+func (r *sqlRecorder) Info(context.Context, string, ...interface{})  {}
+func (r *sqlRecorder) Warn(context.Context, string, ...interface{})  {}
+func (r *sqlRecorder) Error(context.Context, string, ...interface{}) {}
+func (r *sqlRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.statements = append(r.statements, sql)
+}
+
+// statement întoarce prima instrucțiune care conține textul dat
+func (r *sqlRecorder) statement(t *testing.T, contains string) string {
+	t.Helper()
+	for _, sql := range r.statements {
+		if strings.Contains(sql, contains) {
+			return sql
+		}
+	}
+	t.Fatalf("no statement contains %q in %q", contains, r.statements)
+	return ""
+}
+
+// newDryRunRepository - repository-ul peste Postgres în modul DryRun: SQL-ul se generează, dar nu se trimite
+// (fără tranzacții implicite, care ar deschide conexiunea)
+func newDryRunRepository(t *testing.T) (*Repository, *sqlRecorder) {
+	t.Helper()
+	recorder := &sqlRecorder{}
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 recorder,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := NewRepository(db)
+	recorder.statements = nil
+	return repo, recorder
+}
+
+func TestChannelScope(t *testing.T) {
+	tests := []struct {
+		name      string
+		call      func(repo service.Repository)
+		statement string // Textul după care se găsește instrucțiunea verificată
+		scoped    bool
+	}{
+		{"find client", func(repo service.Repository) { repo.FindClientByID(1) }, `FROM "clients"`, true},
+		{"find client by fiscal code", func(repo service.Repository) { repo.FindClientByFiscalID("1003600001232") }, `FROM "clients"`, true},
+		{"count client contracts", func(repo service.Repository) { repo.CountActiveContractsByClient(1) }, `FROM "contracts"`, true},
+		{"find order", func(repo service.Repository) { repo.FindOrderByID(1) }, `FROM "orders"`, true},
+		{"delete client", func(repo service.Repository) { repo.DeleteClient(1) }, `UPDATE "clients"`, true},
+		{"find user", func(repo service.Repository) { repo.FindUserByID(1) }, `FROM "users"`, false},
+		// Verificările de unicitate caută în toate canalele
+		{"client email uniqueness", func(repo service.Repository) { repo.FindClientByEmail("a@b.md") }, `FROM "clients"`, false},
+		{"client identity uniqueness", func(repo service.Repository) { repo.ClientIdentityTaken("1003600001232", nil, 1) }, `FROM "clients"`, false},
+	}
+	scopes := []struct {
+		name  string
+		scope models.ChannelScope
+		want  string
+	}{
+		{"two channels", models.ChannelScope{ChannelIDs: []uint{2, 5}}, `"channel_id" IN (2,5)`},
+		{"no channels", models.ChannelScope{}, `"channel_id" IN (NULL)`},
+	}
+	for _, tt := range tests {
+		for _, sc := range scopes {
+			t.Run(tt.name+"/"+sc.name, func(t *testing.T) {
+				repo, recorder := newDryRunRepository(t)
+				tt.call(repo.WithChannelScope(sc.scope))
+				sql := recorder.statement(t, tt.statement)
+				if got := strings.Contains(sql, sc.want); got != tt.scoped {
+					t.Errorf("scoped = %v, want %v: %s", got, tt.scoped, sql)
+				}
+			})
+		}
+		t.Run(tt.name+"/all channels", func(t *testing.T) {
+			repo, recorder := newDryRunRepository(t)
+			tt.call(repo.WithChannelScope(models.ChannelScope{All: true}))
+			if sql := recorder.statement(t, tt.statement); strings.Contains(sql, "channel_id") {
+				t.Errorf("admin query is scoped: %s", sql)
+			}
+		})
+	}
+}
+
+// Cheia API se adaugă peste restricția pe canale, fără s-o piardă
+func TestChannelScopeWithAPIKey(t *testing.T) {
+	repo, recorder := newDryRunRepository(t)
+	scoped := repo.WithChannelScope(models.ChannelScope{ChannelIDs: []uint{3}}).WithAPIKey(7)
+	scoped.FindClientByID(1)
+	if sql := recorder.statement(t, `FROM "clients"`); !strings.Contains(sql, `"clients"."channel_id" = 3`) {
+		t.Errorf("scope lost after WithAPIKey: %s", sql)
+	}
+
+	recorder.statements = nil
+	repo.FindClientByID(1)
+	if sql := recorder.statement(t, `FROM "clients"`); strings.Contains(sql, "channel_id") {
+		t.Errorf("unscoped repository query is scoped: %s", sql)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"orders/internal/models"
 	"sync"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -30,6 +31,11 @@ func RunAllSeeds(db *gorm.DB) {
         }(s.name, s.fn)
     }
     wg.Wait()
+
+    // Seeders that depend on data created above run sequentially
+    if err := SeedClientTypeIncomeTaxes(db); err != nil {
+        log.Printf("Error seeding ClientTypeIncomeTaxes: %v", err)
+    }
 }
 
 // SeedClientTypes populates the ClientType table with initial data
@@ -64,12 +70,12 @@ func SeedClientTypes(db *gorm.DB) error {
 
 func SeedVatTaxes(db *gorm.DB) error {
 	vatTaxes := []models.VatTax{
-		{Name: "VAT 20%", Rate: 20.0, Description: "20-00"},
-		{Name: "VAT 10%", Rate: 10.0, Description: "10-00"},
-		{Name: "VAT 6%", Rate: 6.0, Description: "6-00"},
-		{Name: "VAT 5%", Rate: 5.0, Description: "5-00"},
-		{Name: "VAT 0%", Rate: 0.0, Description: "0-00"},
-		{Name: "VAT Exempt", Rate: 0.0, Description: "exempt"},
+		{Name: "VAT 20%", Rate: 20.0, Category: models.VatCategoryStandard, Description: "20-00"},
+		{Name: "VAT 10%", Rate: 10.0, Category: models.VatCategoryReduced, Description: "10-00"},
+		{Name: "VAT 6%", Rate: 6.0, Category: models.VatCategoryReduced, Description: "6-00"},
+		{Name: "VAT 5%", Rate: 5.0, Category: models.VatCategoryReduced, Description: "5-00"},
+		{Name: "VAT 0%", Rate: 0.0, Category: models.VatCategoryZeroRated, Description: "0-00"},
+		{Name: "VAT Exempt", Rate: 0.0, Category: models.VatCategoryExempt, Description: "exempt"},
 	}
 
 	for _, vatTax := range vatTaxes {
@@ -81,16 +87,51 @@ func SeedVatTaxes(db *gorm.DB) error {
 				log.Printf("❌ Failed to seed VatTax '%s': %v\n", vatTax.Name, err)
 				return err
 			}
+			existing = vatTax
 			log.Printf("✅ Seeded VatTax: %s\n", vatTax.Name)
 		} else if err != nil {
 			return err
+		} else if existing.Category != vatTax.Category {
+			// Rândurile vechi au primit categoria implicită "standard" la migrare
+			if err := db.Model(&existing).Update("category", vatTax.Category).Error; err != nil {
+				return err
+			}
+			log.Printf("✅ Updated VatTax '%s' category: %s\n", vatTax.Name, vatTax.Category)
 		} else {
 			log.Printf("⏭️ VatTax '%s' already exists\n", vatTax.Name)
 		}
+
+		if err := seedInitialVatTaxRate(db, &existing); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// seedInitialVatTaxRate creează prima versiune datată a ratei, dacă taxa nu are niciuna,
+// astfel încât documentele existente să găsească o rată valabilă la data lor.
+func seedInitialVatTaxRate(db *gorm.DB, vatTax *models.VatTax) error {
+	var count int64
+	if err := db.Model(&models.VatTaxRate{}).Where("vat_tax_id = ?", vatTax.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	rate := models.VatTaxRate{
+		VatTaxID:  vatTax.ID,
+		Rate:      vatTax.Rate,
+		ValidFrom: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := db.Create(&rate).Error; err != nil {
+		log.Printf("❌ Failed to seed VatTaxRate for '%s': %v\n", vatTax.Name, err)
+		return err
+	}
+	log.Printf("✅ Seeded VatTaxRate: %s = %.2f\n", vatTax.Name, vatTax.Rate)
+	return nil
+}
+
 func SeedIncomeTaxes(db *gorm.DB) error {
 	incomeTaxes := []models.IncomeTax{
 		{Name: "Income 12%", Rate: 12.0, Description: "12-00"},
@@ -116,6 +157,35 @@ func SeedIncomeTaxes(db *gorm.DB) error {
 	return nil
 }
 
+// SeedClientTypeIncomeTaxes leagă tipurile de clienți de impozitul pe venit reținut implicit.
+// Legătura se setează doar dacă lipsește, pentru a nu suprascrie modificările făcute manual.
+func SeedClientTypeIncomeTaxes(db *gorm.DB) error {
+	links := map[string]string{
+		"individual": "Income 12%",
+	}
+
+	for clientTypeName, incomeTaxName := range links {
+		var incomeTax models.IncomeTax
+		if err := db.Where("name = ?", incomeTaxName).First(&incomeTax).Error; err != nil {
+			return err
+		}
+
+		result := db.Model(&models.ClientType{}).
+			Where("name = ? AND income_tax_id IS NULL", clientTypeName).
+			Update("income_tax_id", incomeTax.ID)
+		if result.Error != nil {
+			log.Printf("❌ Failed to link ClientType '%s' to IncomeTax '%s': %v\n", clientTypeName, incomeTaxName, result.Error)
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("✅ Linked ClientType '%s' to IncomeTax '%s'\n", clientTypeName, incomeTaxName)
+		} else {
+			log.Printf("⏭️ ClientType '%s' income tax already set\n", clientTypeName)
+		}
+	}
+	return nil
+}
+
 func SeedUnits(db *gorm.DB) error {
 	units := []models.Unit{
 		{Name: "buc", Description: "bucăți"},
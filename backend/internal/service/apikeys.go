@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Prefixul cheilor API (ca să se recunoască în configurări și în loguri)
+const apiKeyPrefix = "ok_"
+
+// Erori pentru cheile API
+var (
+	ErrAPIKeyNameRequired = errors.New("api_key_name_required")
+	ErrAPIKeyPermissions  = errors.New("invalid_permissions")
+	ErrAPIKeyExpiry       = errors.New("invalid_expires_at")
+	ErrAPIKeyInvalid      = errors.New("api_key_invalid")
+	ErrAPIKeyRevoked      = errors.New("api_key_revoked")
+)
+
+// Permisiunile care nu se dau cheilor API: cheia nu poate emite alte chei și nu poate schimba utilizatorii
+var apiKeyForbiddenPermissions = map[string]bool{
+	PermUsersManage: true,
+}
+
+// CreateAPIKey emite o cheie API cu permisiunile, canalele și termenul date. Întoarce cheia salvată și textul ei,
+// care se arată o singură dată; în baza de date se păstrează doar hash-ul.
+func (service *Service) CreateAPIKey(adminID uint, name string, permissions []string, channelIDs []uint, expiresAt time.Time) (*models.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", ErrAPIKeyNameRequired
+	}
+	if !expiresAt.After(time.Now()) {
+		return nil, "", ErrAPIKeyExpiry
+	}
+	if len(permissions) == 0 {
+		return nil, "", ErrAPIKeyPermissions
+	}
+	adminRole, err := service.UserRole(adminID)
+	if err != nil {
+		return nil, "", err
+	}
+	seen := make(map[string]bool, len(permissions))
+	granted := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		p = strings.ToLower(strings.TrimSpace(p))
+		// Cheia nu poate avea mai mult decât administratorul care o emite
+		if !HasPermission(adminRole, p) || apiKeyForbiddenPermissions[p] {
+			return nil, "", ErrAPIKeyPermissions
+		}
+		if !seen[p] {
+			seen[p] = true
+			granted = append(granted, p)
+		}
+	}
+
+	channels := make([]models.Channel, 0, len(channelIDs))
+	for _, id := range channelIDs {
+		channel, err := service.repository.FindChannelByID(id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrChannelNotFound
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		channels = append(channels, *channel)
+	}
+
+	token, _, err := newSecretToken()
+	if err != nil {
+		return nil, "", err
+	}
+	secret := apiKeyPrefix + token
+	key := &models.APIKey{
+		Name:        name,
+		Prefix:      secret[:len(apiKeyPrefix)+8],
+		KeyHash:     hashToken(secret),
+		Permissions: granted,
+		Channels:    channels,
+		ExpiresAt:   expiresAt,
+		CreatedByID: adminID,
+	}
+	audit := func(key *models.APIKey) *models.AuditLog {
+		return newAuditLog(adminID, AuditAPIKeyCreate, "api_key", key.ID, map[string]interface{}{
+			"name": name, "permissions": granted, "channel_ids": channelIDs, "expires_at": expiresAt,
+		})
+	}
+	if err := service.repository.CreateAPIKey(key, audit); err != nil {
+		return nil, "", err
+	}
+	return key, secret, nil
+}
+
+// FindAPIKeys întoarce cheile API cu canalele lor, cele mai noi primele
+func (service *Service) FindAPIKeys() ([]models.APIKey, error) {
+	return service.repository.FindAPIKeys()
+}
+
+// RevokeAPIKey anulează cheia API; cererile cu ea se resping imediat
+func (service *Service) RevokeAPIKey(adminID, id uint) (*models.APIKey, error) {
+	key, err := service.repository.FindAPIKeyByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+	now := time.Now()
+	key.RevokedAt = &now
+	audit := newAuditLog(adminID, AuditAPIKeyRevoke, "api_key", key.ID, map[string]string{"name": key.Name})
+	if err := service.repository.RevokeAPIKey(key, audit); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// AuthenticateAPIKey verifică cheia din header-ul X-API-Key și notează ultima folosire
+func (service *Service) AuthenticateAPIKey(secret, ip string) (*models.APIKey, error) {
+	secret = strings.TrimSpace(secret)
+	if !strings.HasPrefix(secret, apiKeyPrefix) {
+		return nil, ErrAPIKeyInvalid
+	}
+	key, err := service.repository.FindAPIKeyByHash(hashToken(secret))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !key.Active(now) {
+		return nil, ErrAPIKeyInvalid
+	}
+	// Cererile se fac în numele administratorului care a emis cheia: dacă a fost șters sau nu mai are
+	// toate permisiunile cheii (i s-a schimbat rolul), cheia nu mai este valabilă
+	creator, err := service.repository.FindUserByID(key.CreatedByID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range key.Permissions {
+		if !HasPermission(creator.Role, p) {
+			return nil, ErrAPIKeyInvalid
+		}
+	}
+	ip = truncate(ip, 45)
+	if err := service.repository.TouchAPIKey(key.ID, now, ip); err != nil {
+		return nil, err
+	}
+	key.LastUsedAt, key.LastUsedIP = &now, ip
+	return key, nil
+}
+
+// APIKeyChannelScope întoarce canalele vizibile cererilor cu cheia (toate - cu permisiunea channels:all)
+func (service *Service) APIKeyChannelScope(key *models.APIKey) models.ChannelScope {
+	if key.HasPermission(PermChannelsAll) {
+		return models.ChannelScope{All: true}
+	}
+	ids := make([]uint, 0, len(key.Channels))
+	for _, channel := range key.Channels {
+		ids = append(ids, channel.ID)
+	}
+	return models.ChannelScope{ChannelIDs: ids}
+}
+
+// RecordAPIKeyRequest înregistrează cererea făcută cu cheia API în jurnalul de audit
+// (în numele administratorului care a emis cheia)
+func (service *Service) RecordAPIKeyRequest(key *models.APIKey, method, path string, status int) error {
+	audit := newAuditLog(key.CreatedByID, AuditAPIKeyRequest, "api_key", key.ID, map[string]interface{}{
+		"name": key.Name, "method": method, "path": path, "status": status,
+	})
+	audit.APIKeyID = &key.ID
+	return service.repository.CreateAuditLog(audit)
+}
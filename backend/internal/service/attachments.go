@@ -0,0 +1,288 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"orders/internal/models"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/google/uuid"
+)
+
+// Erori pentru fișierele atașate
+var (
+	ErrUnknownAttachmentOwner = errors.New("unknown_attachment_owner")
+	ErrUnsupportedContentType = errors.New("unsupported_content_type")
+	ErrFileTooLarge           = errors.New("file_too_large")
+	ErrEmptyFile              = errors.New("empty_file")
+)
+
+// Dimensiunea maximă (în pixeli) a laturii mari a miniaturii
+const thumbnailSize = 320
+
+// Numărul maxim de pixeli ai imaginii pentru care se face miniatura: decodarea ține toată imaginea în memorie,
+// iar un PNG mic poate declara dimensiuni uriașe
+const thumbnailMaxPixels = 25_000_000
+
+// attachmentRule descrie ce fișiere se pot atașa la un tip de entitate
+type attachmentRule struct {
+	kind         string
+	contentTypes map[string]bool
+}
+
+var attachmentRules = map[string]attachmentRule{
+	"product": {
+		kind:         "image",
+		contentTypes: map[string]bool{"image/jpeg": true, "image/png": true, "image/gif": true, "image/webp": true},
+	},
+	"contract": {
+		kind:         "signed_scan",
+		contentTypes: map[string]bool{"application/pdf": true, "image/jpeg": true, "image/png": true},
+	},
+	"order": {
+		kind:         "photo",
+		contentTypes: map[string]bool{"image/jpeg": true, "image/png": true, "image/webp": true},
+	},
+}
+
+// ensureAttachmentOwner verifică existența entității la care se atașează fișierul
+func (service *Service) ensureAttachmentOwner(ownerType string, ownerID uint) error {
+	var err error
+	switch ownerType {
+	case "product":
+		_, err = service.repository.FindProductByID(ownerID)
+	case "contract":
+		_, err = service.repository.FindContractByID(ownerID)
+	case "order":
+		_, err = service.repository.FindOrderByID(ownerID)
+	default:
+		return ErrUnknownAttachmentOwner
+	}
+	return err
+}
+
+// UploadAttachment validează fișierul (tip detectat din conținut, dimensiune), îl salvează în stocare,
+// generează miniatura pentru imagini și înregistrează metadatele în baza de date.
+func (service *Service) UploadAttachment(ownerType string, ownerID, userID uint, fileName string, r io.Reader) (*models.Attachment, error) {
+	rule, ok := attachmentRules[ownerType]
+	if !ok {
+		return nil, ErrUnknownAttachmentOwner
+	}
+	if err := service.ensureAttachmentOwner(ownerType, ownerID); err != nil {
+		return nil, err
+	}
+
+	maxSize := service.cfg.MaxUploadMB << 20
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrEmptyFile
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrFileTooLarge
+	}
+
+	// Nu ne bazăm pe Content-Type-ul trimis de client, ci pe conținutul efectiv
+	contentType := http.DetectContentType(data)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	if !rule.contentTypes[contentType] {
+		return nil, ErrUnsupportedContentType
+	}
+
+	return service.storeAttachment(ownerType, ownerID, userID, rule.kind, fileName, contentType, data)
+}
+
+// storeAttachment salvează fișierul deja validat în stocare (cu miniatură pentru imagini)
+// și înregistrează metadatele în baza de date
+func (service *Service) storeAttachment(ownerType string, ownerID, userID uint, kind, fileName, contentType string, data []byte) (*models.Attachment, error) {
+	sum := sha256.Sum256(data)
+	base := fmt.Sprintf("%s/%d/%s", ownerType, ownerID, uuid.New().String())
+	attachment := &models.Attachment{
+		OwnerType:    ownerType,
+		OwnerID:      ownerID,
+		Kind:         kind,
+		FileName:     filepath.Base(fileName),
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		Checksum:     hex.EncodeToString(sum[:]),
+		StorageKey:   base + strings.ToLower(filepath.Ext(fileName)),
+		UploadedByID: userID,
+	}
+
+	if _, err := service.storage.Save(attachment.StorageKey, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	// Miniatura nu este obligatorie: dacă formatul nu poate fi decodat (ex. webp), rămâne goală
+	if strings.HasPrefix(contentType, "image/") {
+		if thumb, err := makeThumbnail(data); err == nil {
+			key := base + "_thumb.jpg"
+			if _, err := service.storage.Save(key, bytes.NewReader(thumb)); err == nil {
+				attachment.ThumbnailKey = key
+			}
+		}
+	}
+
+	if err := service.repository.CreateAttachment(attachment); err != nil {
+		service.storage.Delete(attachment.StorageKey)
+		if attachment.ThumbnailKey != "" {
+			service.storage.Delete(attachment.ThumbnailKey)
+		}
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// FindAttachmentByID întoarce fișierul atașat, dacă entitatea lui este vizibilă utilizatorului
+func (service *Service) FindAttachmentByID(id uint) (*models.Attachment, error) {
+	attachment, err := service.repository.FindAttachmentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if service.channelRestricted() {
+		if err := service.ensureAttachmentOwner(attachment.OwnerType, attachment.OwnerID); err != nil {
+			return nil, err
+		}
+	}
+	return attachment, nil
+}
+
+func (service *Service) FindAttachmentsByOwner(ownerType string, ownerID uint) ([]models.Attachment, error) {
+	if _, ok := attachmentRules[ownerType]; !ok {
+		return nil, ErrUnknownAttachmentOwner
+	}
+	if service.channelRestricted() {
+		if err := service.ensureAttachmentOwner(ownerType, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	return service.repository.FindAttachmentsByOwner(ownerType, ownerID)
+}
+
+// OpenAttachment deschide fișierul original sau miniatura acestuia
+func (service *Service) OpenAttachment(attachment *models.Attachment, thumbnail bool) (io.ReadCloser, error) {
+	key := attachment.StorageKey
+	if thumbnail {
+		if attachment.ThumbnailKey == "" {
+			return nil, ErrUnsupportedContentType
+		}
+		key = attachment.ThumbnailKey
+	}
+	return service.storage.Open(key)
+}
+
+// DeleteAttachment marchează înregistrarea ca ștearsă; fișierele rămân în stocare pentru restaurare
+func (service *Service) DeleteAttachment(id uint) error {
+	return service.repository.DeleteAttachment(id)
+}
+
+// SignAttachmentURL întoarce parametrii de interogare pentru un link de descărcare semnat, valabil ttl.
+// Linkul se poate folosi direct în <img src> sau în browser, fără header-ul Authorization.
+func (service *Service) SignAttachmentURL(id uint, thumbnail bool, ttl time.Duration) string {
+	expires := time.Now().Add(ttl).Unix()
+	query := fmt.Sprintf("expires=%d&signature=%s", expires, service.attachmentSignature(id, thumbnail, expires))
+	if thumbnail {
+		query += "&variant=thumbnail"
+	}
+	return fmt.Sprintf("/files/attachments/%d?%s", id, query)
+}
+
+// VerifyAttachmentSignature verifică semnătura și termenul unui link de descărcare
+func (service *Service) VerifyAttachmentSignature(id uint, thumbnail bool, expires, signature string) bool {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return false
+	}
+	expected := service.attachmentSignature(id, thumbnail, exp)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func (service *Service) attachmentSignature(id uint, thumbnail bool, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(service.jwtSecret))
+	fmt.Fprintf(mac, "attachment:%d:%t:%d", id, thumbnail, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ErrImageTooLarge este întoarsă de makeThumbnail pentru imaginile peste thumbnailMaxPixels
+var ErrImageTooLarge = errors.New("image_too_large")
+
+// makeThumbnail micșorează imaginea (media pe zone) și o codifică JPEG pe fundal alb.
+// Dimensiunile se verifică din antet înainte de decodare.
+func makeThumbnail(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > thumbnailMaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return nil, ErrUnsupportedContentType
+	}
+	tw, th := w, h
+	if w > thumbnailSize || h > thumbnailSize {
+		if w >= h {
+			tw, th = thumbnailSize, max(1, h*thumbnailSize/w)
+		} else {
+			tw, th = max(1, w*thumbnailSize/h), thumbnailSize
+		}
+	}
+
+	// Fundal alb pentru imaginile cu transparență (PNG/GIF)
+	flat := image.NewRGBA(b)
+	draw.Draw(flat, b, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, b, src, b.Min, draw.Over)
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+max((y+1)*h/th, y*h/th+1)
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+max((x+1)*w/tw, x*w/tw+1)
+			var r, g, bl, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := flat.RGBAAt(sx, sy)
+					r += uint32(c.R)
+					g += uint32(c.G)
+					bl += uint32(c.B)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / n), uint8(g / n), uint8(bl / n), 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"encoding/json"
+	"orders/internal/models"
+)
+
+// Acțiunile înregistrate în jurnalul de audit
+const (
+	AuditClientMerge      = "client.merge"
+	AuditClientMergeUndo  = "client.merge_undo"
+	AuditContractStatus   = "contract.status"
+	AuditContractPeriod   = "contract.period"
+	AuditContractAmend    = "contract.amendment"
+	AuditOrderApprove     = "order.approve"
+	AuditUserRole         = "user.role"
+	AuditUserInvite       = "user.invite"
+	AuditUserInviteRevoke = "user.invite_revoke"
+	AuditUserInviteAccept = "user.invite_accept"
+	AuditUserLogoutAll    = "user.logout_all"
+	AuditPasswordChange   = "user.password_change"
+	AuditPasswordReset    = "user.password_reset"
+	AuditAPIKeyCreate     = "api_key.create"
+	AuditAPIKeyRevoke     = "api_key.revoke"
+	AuditAPIKeyRequest    = "api_key.request"
+)
+
+const maxAuditLogLimit = 500
+
+// newAuditLog pregătește o înregistrare de audit; details se salvează ca JSON
+func newAuditLog(userID uint, action, entityType string, entityID uint, details interface{}) *models.AuditLog {
+	log := &models.AuditLog{UserID: userID, Action: action, EntityType: entityType, EntityID: entityID}
+	if details != nil {
+		data, _ := json.Marshal(details)
+		log.Details = string(data)
+	}
+	return log
+}
+
+// FindAuditLogs întoarce ultimele acțiuni din jurnalul de audit, cele mai recente primele
+func (service *Service) FindAuditLogs(entityType string, entityID uint, limit int) ([]models.AuditLog, error) {
+	if limit < 1 || limit > maxAuditLogLimit {
+		limit = maxAuditLogLimit
+	}
+	return service.repository.FindAuditLogs(entityType, entityID, limit)
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"orders/internal/validation"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidIBAN - IBAN-ul nu are structura, lungimea sau cifrele de control corecte
+	ErrInvalidIBAN = errors.New("invalid_iban")
+	ErrInvalidBIC  = errors.New("invalid_bic")
+	// ErrDuplicateIBAN - clientul are deja un cont cu acest IBAN
+	ErrDuplicateIBAN = errors.New("duplicate_iban")
+)
+
+// IBANError descrie de ce IBAN-ul nu este valid (errors.Is(err, ErrInvalidIBAN) == true)
+type IBANError struct {
+	Detail string // invalid_format, invalid_length, invalid_checksum
+}
+
+func (e *IBANError) Error() string { return ErrInvalidIBAN.Error() + ": " + e.Detail }
+
+func (e *IBANError) Unwrap() error { return ErrInvalidIBAN }
+
+// Starea identificării unei plăți din extrasul bancar
+const (
+	StatementMatched   = "matched"   // Un singur client
+	StatementAmbiguous = "ambiguous" // IBAN-ul aparține mai multor clienți
+	StatementUnmatched = "unmatched" // Niciun client
+)
+
+// validateBankAccount normalizează și verifică IBAN-ul, codul BIC și moneda contului
+func (service *Service) validateBankAccount(account *models.ClientBankAccount) error {
+	account.IBAN = validation.NormalizeIBAN(account.IBAN)
+	if detail := validation.CheckIBAN(account.IBAN); detail != "" {
+		return &IBANError{Detail: detail}
+	}
+	account.BankCode = validation.NormalizeBIC(account.BankCode)
+	if account.BankCode != "" && !validation.IsValidBIC(account.BankCode) {
+		return ErrInvalidBIC
+	}
+	currency, err := normalizeCurrency(account.Currency)
+	if err != nil {
+		return err
+	}
+	account.Currency = currency
+	account.BankName = strings.TrimSpace(account.BankName)
+
+	accounts, err := service.repository.FindClientBankAccountsByIBAN(account.IBAN)
+	if err != nil {
+		return err
+	}
+	for _, a := range accounts {
+		if a.ClientID == account.ClientID && a.ID != account.ID {
+			return ErrDuplicateIBAN
+		}
+	}
+	return nil
+}
+
+// CreateClientBankAccount adaugă un cont bancar clientului; primul cont devine implicit
+func (service *Service) CreateClientBankAccount(account *models.ClientBankAccount) error {
+	if err := service.validateBankAccount(account); err != nil {
+		return err
+	}
+	return service.repository.CreateClientBankAccount(account)
+}
+
+func (service *Service) FindClientBankAccountByID(id uint) (*models.ClientBankAccount, error) {
+	return service.repository.FindClientBankAccountByID(id)
+}
+
+// UpdateClientBankAccount salvează contul; marcajul de cont implicit se scoate de pe celelalte conturi ale clientului.
+// Contul implicit nu poate fi demarcat direct: se marchează alt cont ca implicit.
+func (service *Service) UpdateClientBankAccount(account *models.ClientBankAccount) error {
+	stored, err := service.repository.FindClientBankAccountByID(account.ID)
+	if err != nil {
+		return err
+	}
+	if stored.IsDefault {
+		account.IsDefault = true
+	}
+	if err := service.validateBankAccount(account); err != nil {
+		return err
+	}
+	return service.repository.UpdateClientBankAccount(account)
+}
+
+func (service *Service) DeleteClientBankAccount(account *models.ClientBankAccount) error {
+	return service.repository.DeleteClientBankAccount(account)
+}
+
+// MatchStatementLines identifică clienții plăților dintr-un extras bancar: întâi după IBAN-ul contrapartidei,
+// apoi după codul fiscal. Plățile nu se înregistrează; rezultatul arată clientul găsit pentru fiecare rând.
+func (service *Service) MatchStatementLines(lines []models.StatementLine) ([]models.StatementMatch, error) {
+	matches := make([]models.StatementMatch, 0, len(lines))
+	for _, line := range lines {
+		match := models.StatementMatch{Line: line, Status: StatementUnmatched, Candidates: []uint{}}
+
+		if iban := validation.NormalizeIBAN(line.IBAN); iban != "" {
+			match.Line.IBAN = iban
+			accounts, err := service.repository.FindClientBankAccountsByIBAN(iban)
+			if err != nil {
+				return nil, err
+			}
+			clients := make(map[uint]bool)
+			for _, a := range accounts {
+				if !clients[a.ClientID] {
+					clients[a.ClientID] = true
+					match.Candidates = append(match.Candidates, a.ClientID)
+				}
+			}
+			switch {
+			case len(match.Candidates) == 1:
+				match.Status, match.MatchedBy = StatementMatched, "iban"
+				match.ClientID, match.BankAccountID = &accounts[0].ClientID, &accounts[0].ID
+			case len(match.Candidates) > 1:
+				match.Status, match.MatchedBy = StatementAmbiguous, "iban"
+			}
+		}
+
+		if match.Status == StatementUnmatched {
+			if fiscalID := validation.NormalizeFiscalID(line.FiscalID); fiscalID != "" {
+				client, err := service.repository.FindClientByFiscalID(fiscalID)
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil, err
+				}
+				if err == nil {
+					match.Status, match.MatchedBy = StatementMatched, "fiscal_code"
+					match.ClientID = &client.ID
+					match.Candidates = append(match.Candidates, client.ID)
+				}
+			}
+		}
+
+		if match.ClientID != nil {
+			client, err := service.repository.FindClientByID(*match.ClientID)
+			if err != nil {
+				return nil, err
+			}
+			match.ClientName = client.Name
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Erori pentru canalele de vânzări
+var (
+	ErrChannelRequired     = errors.New("channel_required")
+	ErrChannelForbidden    = errors.New("channel_forbidden")
+	ErrChannelNotFound     = errors.New("channel_not_found")
+	ErrDuplicateChannel    = errors.New("duplicate_channel")
+	ErrChannelInUse        = errors.New("channel_in_use")
+	ErrChannelNameRequired = errors.New("channel_name_required")
+	ErrUserNotFound        = errors.New("user_not_found")
+)
+
+// ChannelScope determină canalele vizibile utilizatorului: rolurile cu permisiunea channels:all (administratorul)
+// văd toate înregistrările, ceilalți doar clienții, contractele și comenzile din canalele în care sunt membri
+func (service *Service) ChannelScope(userID uint, role string) (models.ChannelScope, error) {
+	if HasPermission(role, PermChannelsAll) {
+		return models.ChannelScope{All: true}, nil
+	}
+	ids, err := service.repository.FindUserChannelIDs(userID)
+	if err != nil {
+		return models.ChannelScope{}, err
+	}
+	if ids == nil {
+		ids = []uint{}
+	}
+	return models.ChannelScope{ChannelIDs: ids}, nil
+}
+
+// WithChannelScope întoarce o copie a serviciului care lucrează doar cu datele din canalele date
+func (service *Service) WithChannelScope(scope models.ChannelScope) *Service {
+	scoped := *service
+	scoped.repository = service.repository.WithChannelScope(scope)
+	scoped.scope = &scope
+	return &scoped
+}
+
+// WithAPIKey întoarce o copie a serviciului pentru cererile cu cheia API: acțiunile din jurnalul de audit
+// se leagă de cheie, nu doar de administratorul care a emis-o
+func (service *Service) WithAPIKey(keyID uint) *Service {
+	withKey := *service
+	withKey.repository = service.repository.WithAPIKey(keyID)
+	return &withKey
+}
+
+// channelRestricted - serviciul lucrează doar cu canalele unui utilizator (nu administrator, nu sarcină de fundal)
+func (service *Service) channelRestricted() bool {
+	return service.scope != nil && !service.scope.All
+}
+
+// resolveChannel verifică canalul unei înregistrări noi sau mutate în alt canal.
+// Fără canal indicat, utilizatorul cu un singur canal îl primește pe acesta; cu mai multe canale, canalul este obligatoriu.
+// Administratorul (și sarcinile de fundal) pot lăsa înregistrarea fără canal.
+func (service *Service) resolveChannel(channelID *uint) (*uint, error) {
+	if channelID != nil && *channelID == 0 {
+		channelID = nil
+	}
+	if !service.channelRestricted() {
+		if channelID == nil {
+			return nil, nil
+		}
+		if _, err := service.repository.FindChannelByID(*channelID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrChannelNotFound
+			}
+			return nil, err
+		}
+		return channelID, nil
+	}
+
+	if channelID == nil {
+		if len(service.scope.ChannelIDs) != 1 {
+			return nil, ErrChannelRequired
+		}
+		id := service.scope.ChannelIDs[0]
+		return &id, nil
+	}
+	if !service.scope.Has(*channelID) {
+		return nil, ErrChannelForbidden
+	}
+	return channelID, nil
+}
+
+// prepareChannel verifică numele canalului (obligatoriu, unic fără a ține cont de majuscule)
+func (service *Service) prepareChannel(channel *models.Channel) error {
+	channel.Name = strings.TrimSpace(channel.Name)
+	channel.Description = strings.TrimSpace(channel.Description)
+	if channel.Name == "" {
+		return ErrChannelNameRequired
+	}
+	existing, err := service.repository.FindChannelByName(channel.Name)
+	if err == nil && existing.ID != channel.ID {
+		return ErrDuplicateChannel
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (service *Service) CreateChannel(channel *models.Channel) error {
+	if err := service.prepareChannel(channel); err != nil {
+		return err
+	}
+	return service.repository.CreateChannel(channel)
+}
+
+// FindChannels întoarce toate canalele pentru administrator, altfel doar canalele utilizatorului
+func (service *Service) FindChannels() ([]models.Channel, error) {
+	var ids []uint
+	if service.channelRestricted() {
+		ids = service.scope.ChannelIDs
+	}
+	return service.repository.FindChannels(ids)
+}
+
+func (service *Service) FindChannelByID(id uint) (*models.Channel, error) {
+	return service.repository.FindChannelByID(id)
+}
+
+func (service *Service) UpdateChannel(channel *models.Channel) error {
+	if err := service.prepareChannel(channel); err != nil {
+		return err
+	}
+	return service.repository.UpdateChannel(channel)
+}
+
+// DeleteChannel șterge canalul fără clienți, contracte sau comenzi; membrii canalului se scot din el
+func (service *Service) DeleteChannel(id uint) error {
+	if _, err := service.repository.FindChannelByID(id); err != nil {
+		return err
+	}
+	count, err := service.repository.CountChannelRecords(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrChannelInUse
+	}
+	return service.repository.DeleteChannel(id)
+}
+
+func (service *Service) FindChannelUsers(channelID uint) ([]models.User, error) {
+	if _, err := service.repository.FindChannelByID(channelID); err != nil {
+		return nil, err
+	}
+	return service.repository.FindChannelUsers(channelID)
+}
+
+// AddChannelUsers adaugă utilizatorii în canal; toți utilizatorii trebuie să existe
+func (service *Service) AddChannelUsers(channelID uint, userIDs []uint) error {
+	if _, err := service.repository.FindChannelByID(channelID); err != nil {
+		return err
+	}
+	for _, id := range userIDs {
+		if _, err := service.repository.FindUserByID(id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+	}
+	return service.repository.AddChannelUsers(channelID, userIDs)
+}
+
+func (service *Service) RemoveChannelUser(channelID, userID uint) error {
+	if _, err := service.repository.FindChannelByID(channelID); err != nil {
+		return err
+	}
+	return service.repository.RemoveChannelUser(channelID, userID)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func uintPtr(v uint) *uint { return &v }
+
+func TestCreateClientChannel(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   *models.ChannelScope
+		channel *uint
+		want    *uint
+		wantErr error
+	}{
+		{"single channel by default", &models.ChannelScope{ChannelIDs: []uint{2}}, nil, uintPtr(2), nil},
+		{"own channel", &models.ChannelScope{ChannelIDs: []uint{1, 2}}, uintPtr(1), uintPtr(1), nil},
+		{"channel required", &models.ChannelScope{ChannelIDs: []uint{1, 2}}, nil, nil, ErrChannelRequired},
+		{"foreign channel", &models.ChannelScope{ChannelIDs: []uint{1, 2}}, uintPtr(3), nil, ErrChannelForbidden},
+		{"user without channels", &models.ChannelScope{}, nil, nil, ErrChannelRequired},
+		{"admin without channel", &models.ChannelScope{All: true}, nil, nil, nil},
+		{"admin, unknown channel", &models.ChannelScope{All: true}, uintPtr(9), nil, ErrChannelNotFound},
+		{"background job", nil, uintPtr(3), uintPtr(3), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			for _, id := range []uint{1, 2, 3} {
+				repo.channels[id] = &models.Channel{Name: "canal"}
+			}
+			service := newTestService(repo)
+			if tt.scope != nil {
+				service = service.WithChannelScope(*tt.scope)
+			}
+			client := &models.Client{ClientTypeID: 1, Name: "Agro SRL", FiscalID: "1003600001232", ChannelID: tt.channel}
+			err := service.CreateClient(client)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if repo.writes != 0 {
+					t.Error("client was saved")
+				}
+				return
+			}
+			if !sameChannel(client.ChannelID, tt.want) {
+				t.Errorf("channel = %v, want %v", client.ChannelID, tt.want)
+			}
+		})
+	}
+}
+
+// newOrderTestService - clienți în canalele 1 și 2 (unul fără canal) și contractele lor
+func newOrderTestService() (*fakeRepository, map[string]uint) {
+	repo := newFakeRepository()
+	signed := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ids := map[string]uint{}
+	first := repo.addClient(models.Client{Name: "Agro", ChannelID: uintPtr(1)})
+	second := repo.addClient(models.Client{Name: "Vin", ChannelID: uintPtr(2)})
+	unassigned := repo.addClient(models.Client{Name: "Lapte"})
+	ids["client 1"], ids["client 2"], ids["client without channel"] = first.ID, second.ID, unassigned.ID
+	ids["contract 1"] = repo.addContract(models.Contract{ClientID: first.ID, ChannelID: uintPtr(1), Date: signed, Currency: "MDL"}).ID
+	ids["contract 2"] = repo.addContract(models.Contract{ClientID: second.ID, ChannelID: uintPtr(2), Date: signed, Currency: "MDL"}).ID
+	// Contractul păstrează canalul vechi al clientului (clientul a fost mutat fără contracte)
+	ids["contract of client 1 in channel 2"] = repo.addContract(models.Contract{ClientID: first.ID, ChannelID: uintPtr(2), Date: signed, Currency: "MDL"}).ID
+	ids["contract without channel"] = repo.addContract(models.Contract{ClientID: first.ID, Date: signed, Currency: "MDL"}).ID
+	return repo, ids
+}
+
+func TestCreateOrderChannel(t *testing.T) {
+	tests := []struct {
+		name     string
+		scope    models.ChannelScope
+		client   string
+		contract string
+		want     *uint
+		wantErr  error
+	}{
+		{"contract channel", models.ChannelScope{ChannelIDs: []uint{1}}, "client 1", "contract 1", uintPtr(1), nil},
+		{"client channel without contract", models.ChannelScope{ChannelIDs: []uint{1}}, "client 1", "", uintPtr(1), nil},
+		{"client channel for contract without channel", models.ChannelScope{All: true}, "client 1", "contract without channel", uintPtr(1), nil},
+		{"contract channel wins over client", models.ChannelScope{All: true}, "client 1", "contract of client 1 in channel 2", uintPtr(2), nil},
+		{"client without channel", models.ChannelScope{All: true}, "client without channel", "", nil, nil},
+		{"contract of another client", models.ChannelScope{All: true}, "client 1", "contract 2", nil, ErrContractClientMismatch},
+		{"contract of another client, visible", models.ChannelScope{ChannelIDs: []uint{1, 2}}, "client 2", "contract 1", nil, ErrContractClientMismatch},
+		{"client of a foreign channel", models.ChannelScope{ChannelIDs: []uint{1}}, "client 2", "", nil, gorm.ErrRecordNotFound},
+		{"client of a foreign channel, own contract", models.ChannelScope{ChannelIDs: []uint{1}}, "client 2", "contract 1", nil, gorm.ErrRecordNotFound},
+		{"client without channel for restricted user", models.ChannelScope{ChannelIDs: []uint{1}}, "client without channel", "", nil, gorm.ErrRecordNotFound},
+		{"contract of a foreign channel", models.ChannelScope{ChannelIDs: []uint{1}}, "client 1", "contract of client 1 in channel 2", nil, gorm.ErrRecordNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, ids := newOrderTestService()
+			service := newTestService(repo).WithChannelScope(tt.scope)
+			order := &models.Order{
+				ClientID:   ids[tt.client],
+				ContractID: ids[tt.contract],
+				ChannelID:  uintPtr(3), // Canalul trimis de utilizator se ignoră
+				Date:       time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			}
+			err := service.CreateOrder(5, order)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if len(repo.orders) != 0 {
+					t.Error("order was saved")
+				}
+				return
+			}
+			if !sameChannel(order.ChannelID, tt.want) {
+				t.Errorf("channel = %v, want %v", order.ChannelID, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"orders/internal/models"
+	"time"
+)
+
+// ErrInvalidCursor - cursorul listei de clienți nu poate fi decodat sau nu corespunde sortării cerute
+var ErrInvalidCursor = errors.New("invalid_cursor")
+
+const (
+	defaultClientPageSize = 50
+	maxClientPageSize     = 500
+)
+
+// clientCursor - poziția ultimului client din pagină; se transmite clientului ca base64 opac
+type clientCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+func encodeClientCursor(filter models.ClientListFilter, client *models.Client) string {
+	value := client.Name
+	if filter.Sort == "created_at" {
+		value = client.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	data, _ := json.Marshal(clientCursor{Sort: filter.Sort, Desc: filter.Desc, Value: value, ID: client.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeClientCursor(cursor string, filter *models.ClientListFilter) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	var cur clientCursor
+	if err := json.Unmarshal(data, &cur); err != nil || cur.ID == 0 || cur.Sort != filter.Sort || cur.Desc != filter.Desc {
+		return ErrInvalidCursor
+	}
+	if cur.Sort == "created_at" {
+		if _, err := time.Parse(time.RFC3339Nano, cur.Value); err != nil {
+			return ErrInvalidCursor
+		}
+	}
+	filter.AfterValue = cur.Value
+	filter.AfterID = cur.ID
+	return nil
+}
+
+// CountClients întoarce numărul total de clienți care corespund filtrelor (fără cursor)
+func (service *Service) CountClients(filter models.ClientListFilter) (int64, error) {
+	return service.repository.CountClients(filter)
+}
+
+// ListClients transmite lui fn, pe rând, clienții paginii de după cursor (cursor "" = prima pagină).
+// Întoarce cursorul paginii următoare sau "" dacă aceasta a fost ultima.
+func (service *Service) ListClients(filter models.ClientListFilter, cursor string, fn func(client *models.Client) error) (string, error) {
+	if filter.Sort != "created_at" {
+		filter.Sort = "name"
+	}
+	if filter.Limit < 1 || filter.Limit > maxClientPageSize {
+		filter.Limit = defaultClientPageSize
+	}
+	if cursor != "" {
+		if err := decodeClientCursor(cursor, &filter); err != nil {
+			return "", err
+		}
+	}
+
+	// Citim un client în plus ca să știm dacă există pagina următoare
+	pageSize := filter.Limit
+	filter.Limit++
+
+	count := 0
+	next := ""
+	var last *models.Client
+	err := service.repository.StreamClients(filter, func(client *models.Client) error {
+		count++
+		if count > pageSize {
+			next = encodeClientCursor(filter, last)
+			return nil
+		}
+		last = client
+		return fn(client)
+	})
+	return next, err
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"orders/internal/validation"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPhone   = errors.New("invalid_phone")
+	ErrInvalidEmail   = errors.New("invalid_email")
+	ErrDuplicateEmail = errors.New("duplicate_email")
+)
+
+// normalizeClientContacts aduce telefonul clientului la E.164 și emailul la litere mici; emailul lipsă devine null.
+// La actualizare (stored != nil) se verifică doar câmpurile modificate, ca valorile vechi pe care
+// migrarea nu le-a putut recunoaște să nu blocheze editarea altor câmpuri.
+func (service *Service) normalizeClientContacts(client, stored *models.Client) error {
+	if stored == nil || client.Phone != stored.Phone {
+		phone, ok := validation.NormalizePhone(client.Phone, validation.DefaultPhoneRegion)
+		if !ok {
+			return ErrInvalidPhone
+		}
+		client.Phone = phone
+	}
+
+	if client.Email == nil {
+		return nil
+	}
+	if stored != nil && stored.Email != nil && *client.Email == *stored.Email {
+		return nil
+	}
+	email, ok := validation.NormalizeEmail(*client.Email)
+	if !ok {
+		return ErrInvalidEmail
+	}
+	if email == "" {
+		client.Email = nil
+		return nil
+	}
+	client.Email = &email
+
+	existing, err := service.repository.FindClientByEmail(email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil && existing.ID != client.ID {
+		return ErrDuplicateEmail
+	}
+	return nil
+}
+
+// normalizeContactFields verifică telefonul și emailul persoanei de contact (emailul nu trebuie să fie unic)
+func normalizeContactFields(contact, stored *models.ClientContact) error {
+	if stored == nil || contact.Phone != stored.Phone {
+		phone, ok := validation.NormalizePhone(contact.Phone, validation.DefaultPhoneRegion)
+		if !ok {
+			return ErrInvalidPhone
+		}
+		contact.Phone = phone
+	}
+	if stored == nil || contact.Email != stored.Email {
+		email, ok := validation.NormalizeEmail(contact.Email)
+		if !ok {
+			return ErrInvalidEmail
+		}
+		contact.Email = email
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"orders/internal/validation"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Erori pentru adresele contractelor
+var (
+	ErrInvalidAddressType = errors.New("invalid_address_type")
+	ErrLocalityNotFound   = errors.New("locality_not_found")
+	ErrLocalityRequired   = errors.New("locality_required")
+	ErrAddressRequired    = errors.New("address_required")
+	ErrInvalidPostalCode  = errors.New("invalid_postal_code")
+	ErrInvalidCoordinates = errors.New("invalid_coordinates")
+)
+
+var contractAddressTypes = []string{models.AddressTypeBilling, models.AddressTypeShipping, models.AddressTypeLegal}
+
+// formatContractAddress compune adresa completă: strada și numărul, localitatea, raionul, codul poștal
+func formatContractAddress(addr *models.ContractAddress) string {
+	var parts []string
+	if street := strings.TrimSpace(addr.Street + " " + addr.Building); street != "" {
+		parts = append(parts, street)
+	}
+	if addr.Locality != nil {
+		parts = append(parts, addr.Locality.Name)
+		if d := addr.Locality.District; d != "" && d != addr.Locality.Name {
+			parts = append(parts, d)
+		}
+	}
+	if addr.PostalCode != "" {
+		parts = append(parts, addr.PostalCode)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// prepareContractAddress verifică și normalizează adresa înainte de salvare (stored - adresa salvată, la actualizare).
+// Localitatea trebuie să existe în clasificatorul CUATM și este obligatorie la adresele de livrare;
+// când localitatea este indicată, adresa completă se compune din câmpurile structurate.
+func (service *Service) prepareContractAddress(addr, stored *models.ContractAddress) error {
+	addr.Type = strings.ToLower(strings.TrimSpace(addr.Type))
+	if addr.Type == "" {
+		addr.Type = models.AddressTypeShipping
+	}
+	known := false
+	for _, t := range contractAddressTypes {
+		if t == addr.Type {
+			known = true
+		}
+	}
+	if !known {
+		return ErrInvalidAddressType
+	}
+
+	addr.Locality = nil
+	if addr.LocalityCode != nil && strings.TrimSpace(*addr.LocalityCode) == "" {
+		addr.LocalityCode = nil
+	}
+	if addr.LocalityCode != nil {
+		code, ok := validation.NormalizeCUATMCode(*addr.LocalityCode)
+		if !ok {
+			return ErrLocalityNotFound
+		}
+		locality, err := service.repository.FindLocalityByCode(code)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrLocalityNotFound
+		}
+		if err != nil {
+			return err
+		}
+		addr.LocalityCode, addr.Locality = &code, locality
+	} else if addr.Type == models.AddressTypeShipping {
+		return ErrLocalityRequired
+	}
+
+	postalCode, ok := validation.NormalizePostalCode(addr.PostalCode)
+	if !ok {
+		return ErrInvalidPostalCode
+	}
+	addr.PostalCode = postalCode
+
+	if (addr.Latitude == nil) != (addr.Longitude == nil) {
+		return ErrInvalidCoordinates
+	}
+	if addr.Latitude != nil && !validation.IsValidCoordinates(*addr.Latitude, *addr.Longitude) {
+		return ErrInvalidCoordinates
+	}
+
+	if stored == nil || addr.ContactPhone != stored.ContactPhone {
+		phone, ok := validation.NormalizePhone(addr.ContactPhone, validation.DefaultPhoneRegion)
+		if !ok {
+			return ErrInvalidPhone
+		}
+		addr.ContactPhone = phone
+	}
+
+	addr.Street = strings.TrimSpace(addr.Street)
+	addr.Building = strings.TrimSpace(addr.Building)
+	if addr.Locality != nil {
+		addr.Address = formatContractAddress(addr)
+	}
+	addr.Address = strings.TrimSpace(addr.Address)
+	if addr.Address == "" {
+		return ErrAddressRequired
+	}
+	return nil
+}
+
+// CreateContractAddress adaugă adresa la contract
+func (service *Service) CreateContractAddress(addr *models.ContractAddress) error {
+	if _, err := service.repository.FindContractByID(addr.ContractID); err != nil {
+		return err
+	}
+	if err := service.prepareContractAddress(addr, nil); err != nil {
+		return err
+	}
+	return service.repository.CreateContractAddress(addr)
+}
+
+// FindContractAddressByID întoarce adresa, dacă contractul ei este vizibil utilizatorului
+func (service *Service) FindContractAddressByID(id uint) (*models.ContractAddress, error) {
+	addr, err := service.repository.FindContractAddressByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if service.channelRestricted() {
+		if _, err := service.repository.FindContractByID(addr.ContractID); err != nil {
+			return nil, err
+		}
+	}
+	return addr, nil
+}
+
+func (service *Service) FindContractAddresses(contractID uint) ([]models.ContractAddress, error) {
+	return service.repository.FindContractAddresses(contractID)
+}
+
+// UpdateContractAddress salvează adresa modificată
+func (service *Service) UpdateContractAddress(addr *models.ContractAddress) error {
+	stored, err := service.repository.FindContractAddressByID(addr.ID)
+	if err != nil {
+		return err
+	}
+	if err := service.prepareContractAddress(addr, stored); err != nil {
+		return err
+	}
+	return service.repository.UpdateContractAddress(addr)
+}
+
+func (service *Service) DeleteContractAddress(id uint) error {
+	return service.repository.DeleteContractAddress(id)
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"orders/internal/documents"
+	"orders/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Erori pentru șabloanele și documentele contractelor
+var (
+	ErrUnknownPlaceholders   = errors.New("unknown_placeholders")
+	ErrEmptyTemplate         = errors.New("empty_template")
+	ErrTemplateInactive      = errors.New("template_inactive")
+	ErrInvalidDocumentFormat = errors.New("invalid_format")
+)
+
+// PlaceholderError - șablonul conține câmpuri necunoscute
+type PlaceholderError struct {
+	Unknown []string
+}
+
+func (e *PlaceholderError) Error() string {
+	return ErrUnknownPlaceholders.Error() + ": " + strings.Join(e.Unknown, ", ")
+}
+
+func (e *PlaceholderError) Unwrap() error {
+	return ErrUnknownPlaceholders
+}
+
+// Formatele documentelor generate
+const (
+	DocumentFormatPDF  = "pdf"
+	DocumentFormatDOCX = "docx"
+)
+
+// Felul fișierelor atașate generate din șablon
+const attachmentKindGenerated = "generated"
+
+// validateTemplateBody verifică textul șablonului: nu e gol și folosește doar câmpuri cunoscute
+func validateTemplateBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return ErrEmptyTemplate
+	}
+	if unknown := documents.UnknownPlaceholders(body); len(unknown) > 0 {
+		return &PlaceholderError{Unknown: unknown}
+	}
+	return nil
+}
+
+// CreateContractTemplate creează șablonul cu versiunea 1
+func (service *Service) CreateContractTemplate(userID uint, template *models.ContractTemplate) error {
+	if err := validateTemplateBody(template.Body); err != nil {
+		return err
+	}
+	template.Version = 1
+	template.IsActive = true
+	template.Versions = []models.ContractTemplateVersion{{Version: 1, Body: template.Body, UserID: userID}}
+	return service.repository.CreateContractTemplate(template)
+}
+
+// UpdateContractTemplate salvează șablonul; dacă textul s-a schimbat, creează o versiune nouă.
+// Documentele generate anterior păstrează versiunea după care au fost generate.
+func (service *Service) UpdateContractTemplate(userID uint, template *models.ContractTemplate) error {
+	stored, err := service.repository.FindContractTemplateByID(template.ID)
+	if err != nil {
+		return err
+	}
+	template.Version = stored.Version
+	if template.Body == stored.Body {
+		return service.repository.UpdateContractTemplate(template, nil)
+	}
+
+	if err := validateTemplateBody(template.Body); err != nil {
+		return err
+	}
+	template.Version++
+	version := &models.ContractTemplateVersion{TemplateID: template.ID, Version: template.Version, Body: template.Body, UserID: userID}
+	return service.repository.UpdateContractTemplate(template, version)
+}
+
+func (service *Service) FindContractTemplateByID(id uint) (*models.ContractTemplate, error) {
+	return service.repository.FindContractTemplateByID(id)
+}
+
+func (service *Service) FindContractTemplates(activeOnly bool) ([]models.ContractTemplate, error) {
+	return service.repository.FindContractTemplates(activeOnly)
+}
+
+func (service *Service) FindContractTemplateVersions(templateID uint) ([]models.ContractTemplateVersion, error) {
+	return service.repository.FindContractTemplateVersions(templateID)
+}
+
+func (service *Service) FindContractTemplateVersion(templateID uint, version int) (*models.ContractTemplateVersion, error) {
+	return service.repository.FindContractTemplateVersion(templateID, version)
+}
+
+func (service *Service) FindContractDocuments(contractID uint) ([]models.ContractDocument, error) {
+	return service.repository.FindContractDocuments(contractID)
+}
+
+// contractPlaceholderValues pregătește valorile câmpurilor șablonului pentru contract
+func (service *Service) contractPlaceholderValues(contract *models.Contract) (map[string]string, error) {
+	client, err := service.repository.FindClientByID(contract.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	date := func(t time.Time) string { return t.Format("02.01.2006") }
+	values := map[string]string{
+		"client.name":         client.Name,
+		"client.fiscal_code":  client.FiscalID,
+		"client.address":      client.Address,
+		"client.phone":        client.Phone,
+		"contract.number":     contract.Number,
+		"contract.name":       contract.Name,
+		"contract.date":       date(contract.Date),
+		"contract.start_date": date(contract.StartDate),
+		"contract.amount":     strconv.FormatFloat(contract.Amount, 'f', 2, 64),
+		"contract.currency":   contract.Currency,
+		"contract.terms":      contract.Terms,
+		"contract.version":    strconv.Itoa(contract.Version),
+		"today":               date(time.Now()),
+	}
+	if client.Email != nil {
+		values["client.email"] = *client.Email
+	}
+	if contract.EndDate != nil {
+		values["contract.end_date"] = date(*contract.EndDate)
+	}
+	for _, account := range client.BankAccounts {
+		if account.IsDefault {
+			values["client.iban"] = account.IBAN
+			values["client.bic"] = account.BankCode
+			values["client.bank_name"] = account.BankName
+			break
+		}
+	}
+
+	all := make([]string, 0, len(contract.Addresses))
+	for _, addr := range contract.Addresses {
+		all = append(all, addr.Address)
+		key := "addresses." + addr.Type
+		if _, ok := documents.ContractPlaceholders[key]; ok && values[key] == "" {
+			values[key] = addr.Address
+		}
+	}
+	values["addresses.all"] = strings.Join(all, "; ")
+
+	org := service.cfg.Organization
+	values["org.name"] = org.Name
+	values["org.fiscal_code"] = org.FiscalCode
+	values["org.address"] = org.Address
+	values["org.phone"] = org.Phone
+	values["org.email"] = org.Email
+	values["org.iban"] = org.IBAN
+	values["org.bic"] = org.BIC
+	values["org.bank_name"] = org.BankName
+	values["org.director"] = org.Director
+	return values, nil
+}
+
+// RenderContractDocument generează documentul contractului din șablon (templateVersion 0 - versiunea curentă),
+// îl atașează contractului și înregistrează versiunile șablonului și contractului folosite.
+func (service *Service) RenderContractDocument(userID, contractID, templateID uint, templateVersion int, format string) (*models.ContractDocument, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = DocumentFormatPDF
+	}
+	if format != DocumentFormatPDF && format != DocumentFormatDOCX {
+		return nil, ErrInvalidDocumentFormat
+	}
+
+	contract, err := service.repository.FindContractByID(contractID)
+	if err != nil {
+		return nil, err
+	}
+	template, err := service.repository.FindContractTemplateByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+	if !template.IsActive {
+		return nil, ErrTemplateInactive
+	}
+	body := template.Body
+	if templateVersion == 0 {
+		templateVersion = template.Version
+	} else if templateVersion != template.Version {
+		version, err := service.repository.FindContractTemplateVersion(template.ID, templateVersion)
+		if err != nil {
+			return nil, err
+		}
+		body = version.Body
+	}
+
+	values, err := service.contractPlaceholderValues(contract)
+	if err != nil {
+		return nil, err
+	}
+	blocks := documents.ParseBlocks(documents.FillTemplate(body, values))
+	title := fmt.Sprintf("Contract %s", contract.Number)
+
+	var data []byte
+	contentType := "application/pdf"
+	if format == DocumentFormatPDF {
+		if data, err = documents.RenderPDF(title, blocks); err != nil {
+			return nil, err
+		}
+	} else {
+		contentType = documents.DocxContentType
+		if data, err = documents.RenderDOCX(title, blocks); err != nil {
+			return nil, err
+		}
+	}
+
+	fileName := fmt.Sprintf("contract_%s_v%d.%s", safeFileName(contract.Number), contract.Version, format)
+	attachment, err := service.storeAttachment("contract", contract.ID, userID, attachmentKindGenerated, fileName, contentType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	document := &models.ContractDocument{
+		ContractID:      contract.ID,
+		ContractVersion: contract.Version,
+		TemplateID:      template.ID,
+		TemplateVersion: templateVersion,
+		Format:          format,
+		AttachmentID:    attachment.ID,
+		Attachment:      *attachment,
+		UserID:          userID,
+	}
+	if err := service.repository.CreateContractDocument(document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// safeFileName păstrează în numele fișierului doar literele, cifrele, "-" și "_"
+func safeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			return r
+		}
+		return '_'
+	}, s)
+}
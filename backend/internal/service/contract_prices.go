@@ -0,0 +1,164 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"orders/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Erori pentru acordurile de preț
+var (
+	ErrInvalidContractPrice  = errors.New("invalid_price")
+	ErrPriceOutsideContract  = errors.New("price_outside_contract_period")
+	ErrContractPriceOverlap  = errors.New("price_agreement_overlap")
+	ErrContractPriceMismatch = errors.New("price_agreement_not_in_contract")
+)
+
+// agreedPrice calculează prețul convenit pentru unitatea cu coeficientul dat:
+// prețul fix (pentru unitatea de bază) sau prețul de listă al unității minus reducerea
+func agreedPrice(agreement *models.ContractPrice, listPrice, coefficient float64) float64 {
+	if agreement.Price != nil {
+		return roundMoney(*agreement.Price * coefficient)
+	}
+	return roundMoney(listPrice * (1 - *agreement.Discount/100))
+}
+
+// validateContractPrice verifică prețul / reducerea și perioada acordului față de perioada contractului.
+// ValidFrom implicit este începutul contractului.
+func (service *Service) validateContractPrice(contract *models.Contract, price *models.ContractPrice) error {
+	if contract.Status == models.ContractStatusClosed {
+		return ErrContractClosed
+	}
+	if (price.Price == nil) == (price.Discount == nil) {
+		return ErrInvalidContractPrice
+	}
+	if price.Price != nil && *price.Price < 0 {
+		return ErrInvalidContractPrice
+	}
+	if price.Discount != nil && (*price.Discount <= 0 || *price.Discount > 100) {
+		return ErrInvalidContractPrice
+	}
+
+	if price.ValidFrom.IsZero() {
+		price.ValidFrom = contract.StartDate
+	}
+	if price.ValidTo != nil && price.ValidTo.Before(price.ValidFrom) {
+		return ErrInvalidContractPeriod
+	}
+	if price.ValidFrom.Before(contract.StartDate) {
+		return ErrPriceOutsideContract
+	}
+	if contract.EndDate != nil && (price.ValidFrom.After(*contract.EndDate) || (price.ValidTo != nil && price.ValidTo.After(*contract.EndDate))) {
+		return ErrPriceOutsideContract
+	}
+
+	if _, err := service.repository.FindProductByID(price.ProductID); err != nil {
+		return err
+	}
+	overlapping, err := service.repository.CountOverlappingContractPrices(price)
+	if err != nil {
+		return err
+	}
+	if overlapping > 0 {
+		return ErrContractPriceOverlap
+	}
+	return nil
+}
+
+// ContractPrice methods
+func (service *Service) CreateContractPrice(contract *models.Contract, price *models.ContractPrice) error {
+	price.ContractID = contract.ID
+	if err := service.validateContractPrice(contract, price); err != nil {
+		return err
+	}
+	return service.repository.CreateContractPrice(price)
+}
+
+// FindContractPrice întoarce acordul de preț, dacă aparține contractului
+func (service *Service) FindContractPrice(contractID, id uint) (*models.ContractPrice, error) {
+	price, err := service.repository.FindContractPriceByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if price.ContractID != contractID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return price, nil
+}
+
+func (service *Service) UpdateContractPrice(contract *models.Contract, price *models.ContractPrice) error {
+	if price.ContractID != contract.ID {
+		return ErrContractPriceMismatch
+	}
+	if err := service.validateContractPrice(contract, price); err != nil {
+		return err
+	}
+	return service.repository.UpdateContractPrice(price)
+}
+
+func (service *Service) DeleteContractPrice(id uint) error {
+	return service.repository.DeleteContractPrice(id)
+}
+
+func (service *Service) FindContractPrices(contractID uint, date *time.Time) ([]models.ContractPrice, error) {
+	return service.repository.FindContractPrices(contractID, date)
+}
+
+// ContractPriceReport compară prețurile convenite, valabile la data dată în contractele active, cu prețurile de listă:
+// prețul tipului priceTypeID (dacă e indicat și există) sau prețul de bază, convertit în moneda contractului.
+// Prețurile sunt pentru unitatea de bază a produsului.
+func (service *Service) ContractPriceReport(contractID, clientID, priceTypeID uint, date time.Time) ([]models.ContractPriceComparison, error) {
+	prices, err := service.repository.FindContractPricesValidAt(contractID, clientID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]float64)
+	rows := make([]models.ContractPriceComparison, 0, len(prices))
+	for i := range prices {
+		agreement := &prices[i]
+		currency := agreement.Contract.Currency
+		rate, ok := rates[currency]
+		if !ok {
+			if rate, err = service.ExchangeRateAt(currency, date); err != nil {
+				return nil, err
+			}
+			rates[currency] = rate
+		}
+
+		listPrice := agreement.Product.Price
+		if priceTypeID != 0 {
+			if pp, err := service.repository.FindPriceProduct(agreement.ProductID, priceTypeID); err == nil {
+				listPrice = pp.Price
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+		}
+		listPrice = roundMoney(listPrice / rate)
+
+		row := models.ContractPriceComparison{
+			ContractPriceID: agreement.ID,
+			ContractID:      agreement.ContractID,
+			ContractNumber:  agreement.Contract.Number,
+			ClientID:        agreement.Contract.ClientID,
+			ClientName:      agreement.Contract.Client.Name,
+			ProductID:       agreement.ProductID,
+			ProductName:     agreement.Product.Name,
+			Currency:        currency,
+			ListPrice:       listPrice,
+			AgreedPrice:     agreedPrice(agreement, listPrice, 1),
+			Discount:        agreement.Discount,
+			ValidFrom:       agreement.ValidFrom,
+			ValidTo:         agreement.ValidTo,
+		}
+		row.Difference = roundMoney(row.AgreedPrice - row.ListPrice)
+		if row.ListPrice != 0 {
+			row.DifferencePct = math.Round(row.Difference/row.ListPrice*10000) / 100
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"orders/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Erori pentru contracte
+var (
+	ErrInvalidContractStatus = errors.New("invalid_contract_status")
+	ErrContractTransition    = errors.New("invalid_status_transition")
+	ErrInvalidContractPeriod = errors.New("invalid_contract_period")
+	ErrContractEnded         = errors.New("contract_end_date_passed")
+	ErrContractNotActive     = errors.New("contract_not_active")
+	ErrOrderOutsideContract  = errors.New("order_outside_contract_period")
+	ErrContractClosed        = errors.New("contract_closed")
+	ErrInvalidEffectiveFrom  = errors.New("invalid_effective_from")
+	ErrAmendmentRequired     = errors.New("amendment_required")
+)
+
+const (
+	defaultContractListLimit = 50
+	maxContractListLimit     = 500
+)
+
+// Trecerile permise între statutele contractului.
+// În "expired" contractul trece doar după data de sfârșit (de obicei prin sarcina de fundal),
+// iar din "closed" nu se mai iese.
+var contractTransitions = map[string][]string{
+	models.ContractStatusDraft:     {models.ContractStatusActive, models.ContractStatusClosed},
+	models.ContractStatusActive:    {models.ContractStatusSuspended, models.ContractStatusExpired, models.ContractStatusClosed},
+	models.ContractStatusSuspended: {models.ContractStatusActive, models.ContractStatusExpired, models.ContractStatusClosed},
+	models.ContractStatusExpired:   {models.ContractStatusActive, models.ContractStatusClosed},
+	models.ContractStatusClosed:    {},
+}
+
+// today întoarce data curentă (fără oră), în același format ca datele documentelor
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// checkContractTransition verifică dacă contractul poate trece în statutul to la data day
+func checkContractTransition(contract *models.Contract, to string, day time.Time) error {
+	if _, ok := contractTransitions[to]; !ok {
+		return ErrInvalidContractStatus
+	}
+	allowed := false
+	for _, status := range contractTransitions[contract.Status] {
+		if status == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return ErrContractTransition
+	}
+
+	ended := contract.EndDate != nil && contract.EndDate.Before(day)
+	switch to {
+	case models.ContractStatusActive:
+		if ended {
+			return ErrContractEnded
+		}
+	case models.ContractStatusExpired:
+		if !ended {
+			return ErrContractTransition
+		}
+	}
+	return nil
+}
+
+// validateContractPeriod completează data de început (implicit data semnării) și verifică perioada
+func validateContractPeriod(contract *models.Contract) error {
+	if contract.StartDate.IsZero() {
+		contract.StartDate = contract.Date
+	}
+	if contract.EndDate != nil && contract.EndDate.Before(contract.StartDate) {
+		return ErrInvalidContractPeriod
+	}
+	return nil
+}
+
+// Contract methods
+
+// CreateContract creează contractul ca ciornă (implicit) sau direct activ
+func (service *Service) CreateContract(contract *models.Contract) error {
+	// Contractul aparține canalului clientului (clientul trebuie să fie vizibil utilizatorului)
+	client, err := service.repository.FindClientByID(contract.ClientID)
+	if err != nil {
+		return err
+	}
+	contract.ChannelID = client.ChannelID
+
+	code, err := normalizeCurrency(contract.Currency)
+	if err != nil {
+		return err
+	}
+	contract.Currency = code
+
+	contract.Status = strings.ToLower(strings.TrimSpace(contract.Status))
+	switch contract.Status {
+	case "":
+		contract.Status = models.ContractStatusDraft
+	case models.ContractStatusDraft, models.ContractStatusActive:
+	default:
+		return ErrInvalidContractStatus
+	}
+	if err := validateContractPeriod(contract); err != nil {
+		return err
+	}
+	if contract.Status == models.ContractStatusActive && contract.EndDate != nil && contract.EndDate.Before(today()) {
+		return ErrContractEnded
+	}
+
+	// Versiunea 1 - contractul inițial, în vigoare de la începutul valabilității
+	contract.Version = 1
+	contract.Amendments = []models.ContractAmendment{{
+		Version:       1,
+		Date:          contract.Date,
+		EffectiveFrom: contract.StartDate,
+		Name:          contract.Name,
+		Amount:        contract.Amount,
+		StartDate:     contract.StartDate,
+		EndDate:       contract.EndDate,
+		Terms:         contract.Terms,
+		UserID:        contract.OwnerID,
+	}}
+	return service.repository.CreateContract(contract)
+}
+
+// FindContracts întoarce pagina de contracte care corespund filtrelor și numărul lor total
+func (service *Service) FindContracts(filter models.ContractListFilter) ([]models.Contract, int64, error) {
+	if filter.Limit < 1 {
+		filter.Limit = defaultContractListLimit
+	}
+	if filter.Limit > maxContractListLimit {
+		filter.Limit = maxContractListLimit
+	}
+	if filter.Status != "" {
+		if _, ok := contractTransitions[filter.Status]; !ok {
+			return nil, 0, ErrInvalidContractStatus
+		}
+	}
+
+	total, err := service.repository.CountContracts(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	contracts, err := service.repository.FindContracts(filter)
+	return contracts, total, err
+}
+
+// CreateContractAmendment înregistrează acordul adițional ca versiune nouă a contractului.
+// Acordul conține toate condițiile versiunii noi; contractul le primește, iar versiunile anterioare
+// rămân neschimbate (comenzile plasate pe ele le păstrează).
+func (service *Service) CreateContractAmendment(userID uint, contract *models.Contract, amendment *models.ContractAmendment) error {
+	if contract.Status == models.ContractStatusClosed {
+		return ErrContractClosed
+	}
+	current, err := service.repository.FindContractAmendment(contract.ID, contract.Version)
+	if err != nil {
+		return err
+	}
+
+	if amendment.Date.IsZero() {
+		amendment.Date = today()
+	}
+	if amendment.EffectiveFrom.IsZero() {
+		amendment.EffectiveFrom = amendment.Date
+	}
+	if amendment.EffectiveFrom.Before(current.EffectiveFrom) {
+		return ErrInvalidEffectiveFrom
+	}
+	if amendment.EndDate != nil && amendment.EndDate.Before(amendment.StartDate) {
+		return ErrInvalidContractPeriod
+	}
+
+	amendment.ContractID = contract.ID
+	amendment.Version = contract.Version + 1
+	amendment.UserID = userID
+
+	if !sameDate(amendment.EndDate, contract.EndDate) {
+		contract.ExpiryNotifiedAt = nil
+	}
+	contract.Version = amendment.Version
+	contract.Name = amendment.Name
+	contract.Amount = amendment.Amount
+	contract.StartDate = amendment.StartDate
+	contract.EndDate = amendment.EndDate
+	contract.Terms = amendment.Terms
+
+	audit := newAuditLog(userID, AuditContractAmend, "contract", contract.ID, map[string]interface{}{
+		"version": amendment.Version, "number": amendment.Number, "effective_from": amendment.EffectiveFrom,
+	})
+	return service.repository.CreateContractAmendment(contract, amendment, audit)
+}
+
+func (service *Service) FindContractAmendments(contractID uint) ([]models.ContractAmendment, error) {
+	return service.repository.FindContractAmendments(contractID)
+}
+
+func (service *Service) FindContractAmendment(contractID uint, version int) (*models.ContractAmendment, error) {
+	return service.repository.FindContractAmendment(contractID, version)
+}
+
+func (service *Service) FindContractByID(id uint) (*models.Contract, error) {
+	return service.repository.FindContractByID(id)
+}
+
+// UpdateContract salvează modificările contractului (fără statut - acesta se schimbă prin ChangeContractStatus).
+// Condițiile (nume, sumă, perioadă, termeni) se corectează direct doar la ciorne, împreună cu versiunea 1;
+// după semnare ele se schimbă numai prin acord adițional (CreateContractAmendment).
+func (service *Service) UpdateContract(userID uint, contract *models.Contract) error {
+	stored, err := service.repository.FindContractByID(contract.ID)
+	if err != nil {
+		return err
+	}
+	contract.Status = stored.Status
+	contract.Version = stored.Version
+	if err := validateContractPeriod(contract); err != nil {
+		return err
+	}
+
+	termsChanged := contract.Name != stored.Name || contract.Amount != stored.Amount || contract.Terms != stored.Terms ||
+		!contract.StartDate.Equal(stored.StartDate) || !sameDate(contract.EndDate, stored.EndDate)
+	if !termsChanged {
+		return service.repository.UpdateContract(contract, nil, nil)
+	}
+	if stored.Status != models.ContractStatusDraft {
+		return ErrAmendmentRequired
+	}
+
+	version, err := service.repository.FindContractAmendment(contract.ID, contract.Version)
+	if err != nil {
+		return err
+	}
+	version.Name = contract.Name
+	version.Amount = contract.Amount
+	version.StartDate = contract.StartDate
+	version.EndDate = contract.EndDate
+	version.Terms = contract.Terms
+	if version.Version == 1 {
+		version.EffectiveFrom = contract.StartDate
+	}
+
+	var audit *models.AuditLog
+	if !contract.StartDate.Equal(stored.StartDate) || !sameDate(contract.EndDate, stored.EndDate) {
+		contract.ExpiryNotifiedAt = nil
+		audit = newAuditLog(userID, AuditContractPeriod, "contract", contract.ID, map[string]interface{}{
+			"from": map[string]interface{}{"start_date": stored.StartDate, "end_date": stored.EndDate},
+			"to":   map[string]interface{}{"start_date": contract.StartDate, "end_date": contract.EndDate},
+		})
+	}
+	return service.repository.UpdateContract(contract, version, audit)
+}
+
+// sameDate compară două date opționale
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// ChangeContractStatus trece contractul în statutul nou, dacă trecerea e permisă, și o scrie în audit
+func (service *Service) ChangeContractStatus(userID, id uint, status, reason string) (*models.Contract, error) {
+	contract, err := service.repository.FindContractByID(id)
+	if err != nil {
+		return nil, err
+	}
+	status = strings.ToLower(strings.TrimSpace(status))
+	if err := checkContractTransition(contract, status, today()); err != nil {
+		return nil, err
+	}
+
+	audit := newAuditLog(userID, AuditContractStatus, "contract", contract.ID, map[string]string{
+		"from": contract.Status, "to": status, "reason": reason,
+	})
+	contract.Status = status
+	if err := service.repository.UpdateContract(contract, nil, audit); err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
+
+// checkOrderContract permite comenzi doar pe contracte active ale clientului comenzii, cu data comenzii în perioada
+// versiunii contractului în vigoare la acea dată; versiunea și canalul contractului se rețin în comandă
+func (service *Service) checkOrderContract(order *models.Order) error {
+	if order.ContractID == 0 {
+		return nil
+	}
+	contract, err := service.repository.FindContractByID(order.ContractID)
+	if err != nil {
+		return err
+	}
+	if contract.ClientID != order.ClientID {
+		return ErrContractClientMismatch
+	}
+	if contract.Status != models.ContractStatusActive {
+		return ErrContractNotActive
+	}
+	version, err := service.repository.FindContractVersionAt(contract.ID, order.Date)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrOrderOutsideContract
+	}
+	if err != nil {
+		return err
+	}
+	if order.Date.Before(version.StartDate) || (version.EndDate != nil && order.Date.After(*version.EndDate)) {
+		return ErrOrderOutsideContract
+	}
+	order.ContractVersion = version.Version
+	if contract.ChannelID != nil {
+		order.ChannelID = contract.ChannelID
+	}
+	return nil
+}
+
+// RunContractLifecycle trece în "expired" contractele cu data de sfârșit depășită și anunță ownerii
+// contractelor active care expiră în următoarele CONTRACT_EXPIRY_NOTICE_DAYS zile (0 - fără anunțuri).
+// Fiecare contract se anunță o singură dată pentru aceeași dată de sfârșit.
+func (service *Service) RunContractLifecycle() (expired, notified int, err error) {
+	day := today()
+	noticeDays := service.cfg.ContractExpiryNoticeDays
+
+	contracts, err := service.repository.FindContractsToExpire(day)
+	if err != nil {
+		return 0, 0, err
+	}
+	for i := range contracts {
+		contract := &contracts[i]
+		audit := newAuditLog(0, AuditContractStatus, "contract", contract.ID, map[string]string{
+			"from": contract.Status, "to": models.ContractStatusExpired, "reason": "end_date",
+		})
+		contract.Status = models.ContractStatusExpired
+		if err := service.repository.UpdateContract(contract, nil, audit); err != nil {
+			return expired, notified, err
+		}
+		err := service.repository.CreateNotification(&models.Notification{
+			UserID:     contract.OwnerID,
+			Type:       NotificationContractExpired,
+			Title:      fmt.Sprintf("Contractul %s a expirat", contract.Number),
+			Message:    fmt.Sprintf("Contractul %s a expirat la %s. Comenzile pe acest contract nu se mai acceptă.", contract.Number, contract.EndDate.Format("02.01.2006")),
+			EntityType: "contract",
+			EntityID:   contract.ID,
+		})
+		if err != nil {
+			return expired, notified, err
+		}
+		expired++
+	}
+
+	if noticeDays <= 0 {
+		return expired, notified, nil
+	}
+	contracts, err = service.repository.FindContractsExpiringBy(day.AddDate(0, 0, noticeDays))
+	if err != nil {
+		return expired, notified, err
+	}
+	for i := range contracts {
+		contract := &contracts[i]
+		days := int(contract.EndDate.Sub(day).Hours() / 24)
+		notification := &models.Notification{
+			UserID:     contract.OwnerID,
+			Type:       NotificationContractExpiring,
+			Title:      fmt.Sprintf("Contractul %s expiră în %d zile", contract.Number, days),
+			Message:    fmt.Sprintf("Contractul %s cu %s expiră la %s.", contract.Number, contract.Client.Name, contract.EndDate.Format("02.01.2006")),
+			EntityType: "contract",
+			EntityID:   contract.ID,
+		}
+		if err := service.repository.MarkContractExpiryNotified(contract, notification); err != nil {
+			return expired, notified, err
+		}
+		notified++
+	}
+	return expired, notified, nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"orders/internal/currency"
+	"orders/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Erori pentru monede și cursuri
+var (
+	ErrInvalidCurrency      = errors.New("invalid_currency")
+	ErrCurrencyMismatch     = errors.New("currency_mismatch")
+	ErrExchangeRateNotFound = errors.New("exchange_rate_not_found")
+)
+
+// normalizeCurrency aduce codul la forma ISO (majuscule); codul gol înseamnă moneda de bază
+func normalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return models.BaseCurrency, nil
+	}
+	if !currency.IsValidCode(code) {
+		return "", ErrInvalidCurrency
+	}
+	return code, nil
+}
+
+// ImportExchangeRates importă fișierul XML cu cursurile oficiale BNM.
+// Reimportul aceleiași zile actualizează cursurile existente.
+func (service *Service) ImportExchangeRates(r io.Reader) ([]models.ExchangeRate, error) {
+	date, parsed, err := currency.ParseBNM(r)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]models.ExchangeRate, 0, len(parsed))
+	for _, p := range parsed {
+		rates = append(rates, models.ExchangeRate{Currency: p.Currency, Date: date, Nominal: p.Nominal, Rate: p.Value})
+	}
+	if err := service.repository.UpsertExchangeRates(rates); err != nil {
+		return nil, err
+	}
+	return service.repository.FindExchangeRatesByDate(date)
+}
+
+// ExchangeRateAt întoarce câți MDL valorează o unitate din moneda dată, la data dată.
+// Se folosește ultimul curs publicat până la acea dată (BNM nu publică în zilele de odihnă).
+func (service *Service) ExchangeRateAt(code string, date time.Time) (float64, error) {
+	code, err := normalizeCurrency(code)
+	if err != nil {
+		return 0, err
+	}
+	if code == models.BaseCurrency {
+		return 1, nil
+	}
+
+	rate, err := service.repository.FindExchangeRateAt(code, date)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrExchangeRateNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	nominal := rate.Nominal
+	if nominal <= 0 {
+		nominal = 1
+	}
+	return rate.Rate / float64(nominal), nil
+}
+
+// FindExchangeRateAt întoarce înregistrarea cursului în vigoare la data dată
+func (service *Service) FindExchangeRateAt(code string, date time.Time) (*models.ExchangeRate, error) {
+	code, err := normalizeCurrency(code)
+	if err != nil {
+		return nil, err
+	}
+	return service.repository.FindExchangeRateAt(code, date)
+}
+
+// resolveOrderCurrency stabilește moneda comenzii (din cerere sau din contract) și cursul la data documentului
+func (service *Service) resolveOrderCurrency(order *models.Order) error {
+	code := strings.TrimSpace(order.Currency)
+	if order.ContractID != 0 {
+		contract, err := service.repository.FindContractByID(order.ContractID)
+		if err != nil {
+			return err
+		}
+		contractCurrency, err := normalizeCurrency(contract.Currency)
+		if err != nil {
+			return err
+		}
+		if code == "" {
+			code = contractCurrency
+		} else if !strings.EqualFold(code, contractCurrency) {
+			return ErrCurrencyMismatch
+		}
+	}
+
+	code, err := normalizeCurrency(code)
+	if err != nil {
+		return err
+	}
+	rate, err := service.ExchangeRateAt(code, order.Date)
+	if err != nil {
+		return err
+	}
+
+	order.Currency = code
+	order.ExchangeRate = rate
+	return nil
+}
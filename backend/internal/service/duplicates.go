@@ -0,0 +1,292 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"orders/internal/models"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	ErrSameClient             = errors.New("same_client")
+	ErrMergeAlreadyUndone     = errors.New("merge_already_undone")
+	ErrMergeConflict          = errors.New("merge_conflict")
+	ErrCandidateNotOpen       = errors.New("candidate_not_open")
+	ErrCandidateMismatch      = errors.New("candidate_mismatch")
+	ErrInvalidDuplicateStatus = errors.New("invalid_status")
+)
+
+const (
+	// Pragul de similaritate a numelor de la care perechea se verifică (pg_trgm, similarity)
+	duplicateNameThreshold = 0.5
+	// Scorul minim de la care perechea se propune pentru unire
+	duplicateMinScore = 0.6
+	// Ponderile câmpurilor în scorul total; câmpurile necompletate la unul din clienți nu contează
+	duplicateNameWeight    = 0.5
+	duplicatePhoneWeight   = 0.3
+	duplicateAddressWeight = 0.2
+	// Ultimele cifre comparate ale telefonului: ignoră prefixul de țară și 0 de la început
+	duplicatePhoneDigits = 8
+)
+
+// Formele juridice care nu contează la compararea numelor ("SRL Alfa" = "Alfa S.R.L.")
+var legalForms = map[string]bool{
+	"srl": true, "sa": true, "ii": true, "ics": true, "sc": true, "gt": true, "ong": true, "ao": true,
+	"ltd": true, "llc": true, "ооо": true, "оао": true, "зао": true, "ип": true,
+}
+
+// duplicateName aduce numele la forma comparată: fără diacritice, punctuație și forma juridică
+func duplicateName(name string) string {
+	words := strings.FieldsFunc(normalizeSearchText(strings.NewReplacer(".", "").Replace(name)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	res := words[:0]
+	for _, w := range words {
+		if !legalForms[w] {
+			res = append(res, w)
+		}
+	}
+	return strings.Join(res, " ")
+}
+
+// textSimilarity - similaritatea trigramelor a două texte cu mai multe cuvinte, ca similarity() din pg_trgm
+func textSimilarity(a, b string) float64 {
+	ta, tb := textTrigrams(a), textTrigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	common := 0
+	for t := range ta {
+		if tb[t] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(ta)+len(tb)-common)
+}
+
+func textTrigrams(s string) map[string]bool {
+	res := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		for t := range trigrams(word) {
+			res[t] = true
+		}
+	}
+	return res
+}
+
+// lastDigits întoarce ultimele n cifre ale telefonului sau "" dacă numărul are mai puțin de 6 cifre
+func lastDigits(phone string, n int) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	digits := b.String()
+	if len(digits) < 6 {
+		return ""
+	}
+	if len(digits) > n {
+		digits = digits[len(digits)-n:]
+	}
+	return digits
+}
+
+// scoreDuplicate calculează scorurile perechii de clienți
+func scoreDuplicate(a, b *models.Client) models.DuplicateCandidate {
+	c := models.DuplicateCandidate{ClientAID: a.ID, ClientBID: b.ID}
+	c.NameScore = textSimilarity(duplicateName(a.Name), duplicateName(b.Name))
+	total, weights := duplicateNameWeight*c.NameScore, duplicateNameWeight
+
+	if pa, pb := lastDigits(a.Phone, duplicatePhoneDigits), lastDigits(b.Phone, duplicatePhoneDigits); pa != "" && pb != "" {
+		if pa == pb {
+			c.PhoneScore = 1
+		}
+		total += duplicatePhoneWeight * c.PhoneScore
+		weights += duplicatePhoneWeight
+	}
+	if aa, ab := normalizeSearchText(a.Address), normalizeSearchText(b.Address); aa != "" && ab != "" {
+		c.AddressScore = textSimilarity(aa, ab)
+		total += duplicateAddressWeight * c.AddressScore
+		weights += duplicateAddressWeight
+	}
+
+	c.Score = round4(total / weights)
+	c.NameScore, c.AddressScore = round4(c.NameScore), round4(c.AddressScore)
+	return c
+}
+
+func round4(v float64) float64 {
+	return float64(int64(v*10000+0.5)) / 10000
+}
+
+// DetectDuplicateClients caută perechile de clienți posibil duplicați și le salvează pentru verificare.
+// Perechile găsite anterior și respinse nu se redeschid. Întoarce numărul perechilor cu scor suficient.
+func (service *Service) DetectDuplicateClients() (int, error) {
+	// Postgres păstrează microsecunde: perechile găsite acum nu trebuie să pară mai vechi decât startedAt
+	startedAt := time.Now().Truncate(time.Microsecond)
+	pairs, err := service.repository.FindDuplicatePairs(duplicateNameThreshold)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]uint, 0, len(pairs)*2)
+	seen := make(map[uint]bool)
+	for _, p := range pairs {
+		for _, id := range []uint{p.ClientAID, p.ClientBID} {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	byID := make(map[uint]*models.Client, len(ids))
+	if len(ids) > 0 {
+		clients, err := service.repository.FindClientsByIDs(ids)
+		if err != nil {
+			return 0, err
+		}
+		for i := range clients {
+			byID[clients[i].ID] = &clients[i]
+		}
+	}
+
+	candidates := make([]models.DuplicateCandidate, 0)
+	for _, p := range pairs {
+		a, b := byID[p.ClientAID], byID[p.ClientBID]
+		if a == nil || b == nil {
+			continue
+		}
+		c := scoreDuplicate(a, b)
+		if c.Score < duplicateMinScore {
+			continue
+		}
+		c.Status = models.DuplicateOpen
+		c.DetectedAt = startedAt
+		candidates = append(candidates, c)
+	}
+
+	if err := service.repository.UpsertDuplicateCandidates(candidates); err != nil {
+		return 0, err
+	}
+	if err := service.repository.DeleteStaleDuplicateCandidates(startedAt); err != nil {
+		return 0, err
+	}
+	return len(candidates), nil
+}
+
+// FindDuplicateCandidates întoarce perechile detectate, cele cu scor mai mare primele
+func (service *Service) FindDuplicateCandidates(status string, minScore float64, limit int) ([]models.DuplicateCandidate, error) {
+	switch status {
+	case "", models.DuplicateOpen, models.DuplicateDismissed, models.DuplicateMerged:
+	default:
+		return nil, ErrInvalidDuplicateStatus
+	}
+	if limit < 1 || limit > maxAuditLogLimit {
+		limit = maxAuditLogLimit
+	}
+	return service.repository.FindDuplicateCandidates(status, minScore, limit)
+}
+
+// DismissDuplicateCandidate marchează perechea ca fiind clienți diferiți
+func (service *Service) DismissDuplicateCandidate(id uint) error {
+	candidate, err := service.repository.FindDuplicateCandidateByID(id)
+	if err != nil {
+		return err
+	}
+	if candidate.Status != models.DuplicateOpen {
+		return ErrCandidateNotOpen
+	}
+	return service.repository.UpdateDuplicateCandidateStatus(id, models.DuplicateDismissed)
+}
+
+// MergeClients unește clientul mergedID în survivorID: contractele, comenzile, plățile și persoanele de contact
+// trec la survivorID, iar mergedID se șterge logic. Dacă candidateID nu este nil, perechea trebuie să fie
+// chiar cea a celor doi clienți și să fie deschisă.
+func (service *Service) MergeClients(userID, survivorID, mergedID uint, candidateID *uint) (*models.ClientMerge, error) {
+	if survivorID == mergedID {
+		return nil, ErrSameClient
+	}
+	if _, err := service.repository.FindClientByID(survivorID); err != nil {
+		return nil, err
+	}
+	if _, err := service.repository.FindClientByID(mergedID); err != nil {
+		return nil, err
+	}
+	if candidateID != nil {
+		candidate, err := service.repository.FindDuplicateCandidateByID(*candidateID)
+		if err != nil {
+			return nil, err
+		}
+		pair := map[uint]bool{candidate.ClientAID: true, candidate.ClientBID: true}
+		if !pair[survivorID] || !pair[mergedID] {
+			return nil, ErrCandidateMismatch
+		}
+		if candidate.Status != models.DuplicateOpen {
+			return nil, ErrCandidateNotOpen
+		}
+	}
+
+	merge := &models.ClientMerge{SurvivorID: survivorID, MergedID: mergedID, CandidateID: candidateID, UserID: userID}
+	if err := service.repository.MergeClients(merge, func(merge *models.ClientMerge) *models.AuditLog {
+		return newAuditLog(userID, AuditClientMerge, "client", survivorID, mergeAuditDetails(merge))
+	}); err != nil {
+		return nil, err
+	}
+	return service.repository.FindClientMergeByID(merge.ID)
+}
+
+// mergeAuditDetails - detaliile unirii păstrate în jurnalul de audit
+func mergeAuditDetails(merge *models.ClientMerge) map[string]interface{} {
+	return map[string]interface{}{
+		"merge_id":     merge.ID,
+		"survivor_id":  merge.SurvivorID,
+		"merged_id":    merge.MergedID,
+		"candidate_id": merge.CandidateID,
+		"contracts":    merge.Contracts,
+		"orders":       merge.Orders,
+		"payments":     merge.Payments,
+		"contacts":     merge.Contacts,
+	}
+}
+
+// UndoClientMerge anulează unirea: clientul unit se restabilește și primește înapoi înregistrările mutate.
+// Nu se poate anula dacă între timp clientul care a rămas a fost șters sau unit la rândul lui ori dacă
+// codul fiscal sau emailul clientului unit aparțin acum altui client activ.
+func (service *Service) UndoClientMerge(userID, mergeID uint) (*models.ClientMerge, error) {
+	merge, err := service.repository.FindClientMergeByID(mergeID)
+	if err != nil {
+		return nil, err
+	}
+	if merge.UndoneAt != nil {
+		return nil, ErrMergeAlreadyUndone
+	}
+	if merge.Survivor.DeletedAt.Valid {
+		return nil, ErrMergeConflict
+	}
+	taken, err := service.repository.ClientIdentityTaken(merge.Merged.FiscalID, merge.Merged.Email, merge.MergedID)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, fmt.Errorf("%w: the fiscal code or email of the merged client now belongs to another client", ErrMergeConflict)
+	}
+
+	now := time.Now()
+	merge.UndoneAt, merge.UndoneByID = &now, &userID
+	audit := newAuditLog(userID, AuditClientMergeUndo, "client", merge.SurvivorID, mergeAuditDetails(merge))
+	if err := service.repository.UndoClientMerge(merge, audit); err != nil {
+		return nil, err
+	}
+	return service.repository.FindClientMergeByID(merge.ID)
+}
+
+func (service *Service) FindClientMergeByID(id uint) (*models.ClientMerge, error) {
+	return service.repository.FindClientMergeByID(id)
+}
+
+func (service *Service) FindClientMerges(clientID uint) ([]models.ClientMerge, error) {
+	return service.repository.FindClientMerges(clientID)
+}
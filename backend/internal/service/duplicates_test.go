@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func strPtr(s string) *string { return &s }
+
+// newMergeTestService - doi clienți duplicați (1 rămâne, 2 se unește) și perechea lor deschisă
+func newMergeTestService() (*Service, *fakeRepository, *models.Client, *models.Client, uint) {
+	repo := newFakeRepository()
+	survivor := repo.addClient(models.Client{Name: "Agro SRL", FiscalID: "1003600012345", Email: strPtr("office@agro.md")})
+	merged := repo.addClient(models.Client{Name: "Agro S.R.L.", FiscalID: "1003600054321", Email: strPtr("agro@mail.md")})
+	candidateID := repo.newID()
+	repo.candidates[candidateID] = &models.DuplicateCandidate{ClientAID: survivor.ID, ClientBID: merged.ID, Status: models.DuplicateOpen}
+	return newTestService(repo), repo, survivor, merged, candidateID
+}
+
+func TestMergeClients(t *testing.T) {
+	service, repo, survivor, merged, candidateID := newMergeTestService()
+
+	merge, err := service.MergeClients(7, survivor.ID, merged.ID, &candidateID)
+	if err != nil {
+		t.Fatalf("MergeClients: %v", err)
+	}
+	if merge.SurvivorID != survivor.ID || merge.MergedID != merged.ID || merge.UserID != 7 {
+		t.Errorf("merge = %+v", merge)
+	}
+	if _, err := repo.FindClientByID(merged.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("merged client is still active: err = %v", err)
+	}
+	if repo.candidates[candidateID].Status != models.DuplicateMerged {
+		t.Errorf("candidate status = %q, want %q", repo.candidates[candidateID].Status, models.DuplicateMerged)
+	}
+	if len(repo.audits) != 1 || repo.audits[0].Action != AuditClientMerge {
+		t.Errorf("audit logs = %+v", repo.audits)
+	}
+
+	// Clientul unit nu mai poate fi unit din nou
+	if _, err := service.MergeClients(7, survivor.ID, merged.ID, nil); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("merging a deleted client: err = %v, want %v", err, gorm.ErrRecordNotFound)
+	}
+}
+
+func TestMergeClientsRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(repo *fakeRepository, survivor, merged *models.Client, candidateID uint) (survivorID, mergedID uint, candidate *uint)
+		wantErr error
+	}{
+		{"same client", func(repo *fakeRepository, survivor, merged *models.Client, candidateID uint) (uint, uint, *uint) {
+			return survivor.ID, survivor.ID, nil
+		}, ErrSameClient},
+		{"unknown client", func(repo *fakeRepository, survivor, merged *models.Client, candidateID uint) (uint, uint, *uint) {
+			return survivor.ID, 999, nil
+		}, gorm.ErrRecordNotFound},
+		{"candidate of another pair", func(repo *fakeRepository, survivor, merged *models.Client, candidateID uint) (uint, uint, *uint) {
+			other := repo.addClient(models.Client{Name: "Alt client", FiscalID: "1003600099999"})
+			return survivor.ID, other.ID, &candidateID
+		}, ErrCandidateMismatch},
+		{"dismissed candidate", func(repo *fakeRepository, survivor, merged *models.Client, candidateID uint) (uint, uint, *uint) {
+			repo.candidates[candidateID].Status = models.DuplicateDismissed
+			return survivor.ID, merged.ID, &candidateID
+		}, ErrCandidateNotOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, survivor, merged, candidateID := newMergeTestService()
+			survivorID, mergedID, candidate := tt.setup(repo, survivor, merged, candidateID)
+			if _, err := service.MergeClients(7, survivorID, mergedID, candidate); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.merges) != 0 {
+				t.Errorf("got %d merges, want 0", len(repo.merges))
+			}
+		})
+	}
+}
+
+func TestUndoClientMerge(t *testing.T) {
+	service, repo, survivor, merged, candidateID := newMergeTestService()
+	merge, err := service.MergeClients(7, survivor.ID, merged.ID, &candidateID)
+	if err != nil {
+		t.Fatalf("MergeClients: %v", err)
+	}
+
+	undone, err := service.UndoClientMerge(8, merge.ID)
+	if err != nil {
+		t.Fatalf("UndoClientMerge: %v", err)
+	}
+	if undone.UndoneAt == nil || undone.UndoneByID == nil || *undone.UndoneByID != 8 {
+		t.Errorf("undone merge = %+v", undone)
+	}
+	if _, err := repo.FindClientByID(merged.ID); err != nil {
+		t.Errorf("merged client was not restored: %v", err)
+	}
+	if repo.candidates[candidateID].Status != models.DuplicateOpen {
+		t.Errorf("candidate status = %q, want %q", repo.candidates[candidateID].Status, models.DuplicateOpen)
+	}
+	if last := repo.audits[len(repo.audits)-1]; last.Action != AuditClientMergeUndo {
+		t.Errorf("last audit action = %q, want %q", last.Action, AuditClientMergeUndo)
+	}
+
+	if _, err := service.UndoClientMerge(8, merge.ID); !errors.Is(err, ErrMergeAlreadyUndone) {
+		t.Errorf("second undo: err = %v, want %v", err, ErrMergeAlreadyUndone)
+	}
+}
+
+func TestUndoClientMergeConflict(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(repo *fakeRepository, survivor, merged *models.Client)
+	}{
+		{"survivor deleted", func(repo *fakeRepository, survivor, merged *models.Client) {
+			repo.clients[survivor.ID].DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		}},
+		{"fiscal code taken", func(repo *fakeRepository, survivor, merged *models.Client) {
+			repo.addClient(models.Client{Name: "Nou", FiscalID: merged.FiscalID})
+		}},
+		{"email taken, other case", func(repo *fakeRepository, survivor, merged *models.Client) {
+			repo.addClient(models.Client{Name: "Nou", FiscalID: "1003600077777", Email: strPtr("AGRO@mail.md")})
+		}},
+		{"email moved to survivor", func(repo *fakeRepository, survivor, merged *models.Client) {
+			repo.clients[survivor.ID].Email = merged.Email
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, survivor, merged, _ := newMergeTestService()
+			merge, err := service.MergeClients(7, survivor.ID, merged.ID, nil)
+			if err != nil {
+				t.Fatalf("MergeClients: %v", err)
+			}
+			tt.setup(repo, survivor, merged)
+
+			if _, err := service.UndoClientMerge(8, merge.ID); !errors.Is(err, ErrMergeConflict) {
+				t.Fatalf("err = %v, want %v", err, ErrMergeConflict)
+			}
+			if repo.merges[merge.ID].UndoneAt != nil {
+				t.Error("merge was marked as undone")
+			}
+			if _, err := repo.FindClientByID(merged.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+				t.Error("merged client was restored")
+			}
+		})
+	}
+}
@@ -0,0 +1,648 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"orders/internal/commerceml"
+	"orders/internal/models"
+	"orders/internal/validation"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Sistemul extern cu care se face schimbul CommerceML
+const exchangeSystem1C = "1c"
+
+// ExchangeCounts - câte înregistrări de un anumit tip au fost create / actualizate
+type ExchangeCounts struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// ExchangeSkip - o înregistrare din fișierul 1C care nu a putut fi importată
+type ExchangeSkip struct {
+	Entity string `json:"entity"` // group, counterparty, product, price_type, price
+	ID     string `json:"id"`     // Ид-ul din 1C
+	Name   string `json:"name,omitempty"`
+	Reason string `json:"reason"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ExchangeResult - rezultatul importului unui fișier CommerceML
+type ExchangeResult struct {
+	Groups         ExchangeCounts `json:"groups"`
+	Counterparties ExchangeCounts `json:"counterparties"`
+	Products       ExchangeCounts `json:"products"`
+	PriceTypes     ExchangeCounts `json:"price_types"`
+	Prices         ExchangeCounts `json:"prices"`
+	Skipped        []ExchangeSkip `json:"skipped"`
+}
+
+func (r *ExchangeResult) records() int {
+	n := 0
+	for _, c := range []ExchangeCounts{r.Groups, r.Counterparties, r.Products, r.PriceTypes, r.Prices} {
+		n += c.Created + c.Updated
+	}
+	return n
+}
+
+func (r *ExchangeResult) skip(entity, id, name, reason, detail string) {
+	r.Skipped = append(r.Skipped, ExchangeSkip{Entity: entity, ID: id, Name: name, Reason: reason, Detail: detail})
+}
+
+// count mărește contorul potrivit: created dacă înregistrarea era nouă, altfel updated
+func (c *ExchangeCounts) count(created bool) {
+	if created {
+		c.Created++
+	} else {
+		c.Updated++
+	}
+}
+
+// normalizeGUID verifică Ид-ul din 1C; în coloana UUID se păstrează forma canonică (litere mici)
+func normalizeGUID(id string) (string, bool) {
+	parsed, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return "", false
+	}
+	return parsed.String(), true
+}
+
+// truncate taie textul la lungimea coloanei (în caractere)
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+// ImportCommerceML importă un fișier CommerceML 2 primit din 1C: grupele de produse, contrapartidele (ca clienți),
+// produsele, tipurile de preț și prețurile. Înregistrările se leagă prin UUID de Ид-urile din 1C;
+// la primul schimb, cele existente se recunosc după denumire (grupe, tipuri de preț), SKU (produse) sau cod fiscal (clienți).
+func (service *Service) ImportCommerceML(userID uint, fileName string, r io.Reader) (*ExchangeResult, error) {
+	log := &models.ExchangeLog{
+		System:    exchangeSystem1C,
+		Direction: models.ExchangeImport,
+		Kind:      "catalog",
+		FileName:  truncate(fileName, 255),
+		StartedAt: time.Now(),
+		UserID:    userID,
+	}
+
+	msg, err := commerceml.Parse(r)
+	if err != nil {
+		log.Status, log.Error = "failed", err.Error()
+		service.repository.CreateExchangeLog(log)
+		return nil, err
+	}
+
+	result := &ExchangeResult{Skipped: make([]ExchangeSkip, 0)}
+	steps := []func() error{
+		func() error { return service.importGroups(msg, result) },
+		func() error { return service.importCounterparties(msg, result) },
+		func() error { return service.importProducts(msg, result) },
+		func() error { return service.importPrices(msg, result) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			log.Status, log.Error = "failed", err.Error()
+			log.Records, log.Skipped = result.records(), len(result.Skipped)
+			service.repository.CreateExchangeLog(log)
+			return nil, err
+		}
+	}
+
+	log.Status = "success"
+	log.Records, log.Skipped = result.records(), len(result.Skipped)
+	if err := service.repository.CreateExchangeLog(log); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// notFound - eroarea este "înregistrarea nu există"; celelalte erori opresc importul
+func notFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
+func (service *Service) importGroups(msg *commerceml.Message, result *ExchangeResult) error {
+	if msg.Classifier == nil {
+		return nil
+	}
+	for _, g := range commerceml.Flatten(msg.Classifier.Groups) {
+		guid, ok := normalizeGUID(g.ID)
+		name := truncate(g.Name, 100)
+		if !ok {
+			result.skip("group", g.ID, g.Name, "invalid_guid", "")
+			continue
+		}
+		if name == "" {
+			result.skip("group", g.ID, g.Name, "missing_required_fields", "")
+			continue
+		}
+
+		group, err := service.repository.FindProductGroupByUUID(guid)
+		if notFound(err) {
+			group, err = service.repository.FindProductGroupByName(name)
+		}
+		created := notFound(err)
+		if created {
+			group, err = &models.ProductGroup{}, nil
+		}
+		if err != nil {
+			return err
+		}
+
+		group.UUID = guid
+		group.Name = name
+		if err := service.repository.SaveProductGroup(group); err != nil {
+			result.skip("group", g.ID, g.Name, "database_error", err.Error())
+			continue
+		}
+		result.Groups.count(created)
+	}
+	return nil
+}
+
+func (service *Service) importCounterparties(msg *commerceml.Message, result *ExchangeResult) error {
+	if msg.Classifier == nil || len(msg.Classifier.Counterparties) == 0 {
+		return nil
+	}
+
+	clientTypes, err := service.repository.FindAllClientTypes()
+	if err != nil {
+		return err
+	}
+	typeIDs := make(map[string]uint, len(clientTypes))
+	for _, ct := range clientTypes {
+		typeIDs[ct.Name] = ct.ID
+	}
+
+	for _, cp := range msg.Classifier.Counterparties {
+		guid, ok := normalizeGUID(cp.ID)
+		if !ok {
+			result.skip("counterparty", cp.ID, cp.Name, "invalid_guid", "")
+			continue
+		}
+		if cp.DeletionMark {
+			result.skip("counterparty", cp.ID, cp.Name, "marked_for_deletion", "")
+			continue
+		}
+		fiscalID := validation.NormalizeFiscalID(cp.FiscalID)
+		name := truncate(cp.Name, 100)
+		if name == "" || fiscalID == "" {
+			result.skip("counterparty", cp.ID, cp.Name, "missing_required_fields", "")
+			continue
+		}
+
+		client, err := service.repository.FindClientByUUID(guid)
+		if notFound(err) {
+			client, err = service.repository.FindClientByFiscalID(fiscalID)
+		}
+		created := notFound(err)
+		if err != nil && !created {
+			return err
+		}
+
+		// Tipul clientului: după tipul codului fiscal, iar dacă acesta nu se recunoaște - după atributele din 1C
+		typeName := "company"
+		switch validation.FiscalIDKind(fiscalID) {
+		case validation.FiscalIDKindIDNP:
+			typeName = "individual"
+		case "":
+			if !cp.IsLegalEntity() {
+				typeName = "individual"
+			}
+		}
+
+		if created {
+			client = &models.Client{ClientTypeID: typeIDs[typeName]}
+		}
+		client.UUID = guid
+		client.Name = name
+		client.FiscalID = fiscalID
+		client.ClientType = models.ClientType{}
+		if v := cp.ContactValue("почт", "mail"); v != "" {
+			client.Email = &v
+		}
+		if v := cp.ContactValue("телефон", "phone"); v != "" {
+			client.Phone = truncate(v, 50)
+		}
+		if v := cp.AddressText(); v != "" {
+			client.Address = v
+		}
+
+		if created {
+			err = service.CreateClient(client)
+		} else {
+			err = service.UpdateClient(client)
+		}
+		if err != nil {
+			reason, detail := importErrorReason(err)
+			result.skip("counterparty", cp.ID, cp.Name, reason, detail)
+			continue
+		}
+		result.Counterparties.count(created)
+	}
+	return nil
+}
+
+func (service *Service) importProducts(msg *commerceml.Message, result *ExchangeResult) error {
+	if msg.Catalog == nil {
+		return nil
+	}
+
+	vatTaxes, err := service.repository.FindAllVatTaxes()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range msg.Catalog.Products {
+		guid, ok := normalizeGUID(p.ID)
+		if !ok {
+			result.skip("product", p.ID, p.Name, "invalid_guid", "")
+			continue
+		}
+		if p.DeletionMark {
+			result.skip("product", p.ID, p.Name, "marked_for_deletion", "")
+			continue
+		}
+		name := truncate(p.Name, 100)
+		if name == "" {
+			result.skip("product", p.ID, p.Name, "missing_required_fields", "")
+			continue
+		}
+
+		product, err := service.repository.FindProductByUUID(guid)
+		if notFound(err) && strings.TrimSpace(p.SKU) != "" {
+			product, err = service.repository.FindProductBySKU(strings.TrimSpace(p.SKU))
+		}
+		created := notFound(err)
+		if created {
+			product, err = &models.Product{}, nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Grupa
+		if len(p.GroupIDs) > 0 {
+			groupGUID, _ := normalizeGUID(p.GroupIDs[0])
+			group, err := service.repository.FindProductGroupByUUID(groupGUID)
+			if err != nil && !notFound(err) {
+				return err
+			}
+			if err == nil {
+				product.ProductGroupID = group.ID
+			}
+		}
+		if product.ProductGroupID == 0 {
+			result.skip("product", p.ID, p.Name, "product_group_not_found", strings.Join(p.GroupIDs, ","))
+			continue
+		}
+
+		// Unitatea de măsură de bază; se creează dacă nu există
+		if p.BaseUnit != nil && strings.TrimSpace(p.BaseUnit.Name) != "" {
+			unit, err := service.repository.FindUnitByName(strings.TrimSpace(p.BaseUnit.Name))
+			if notFound(err) {
+				unit = &models.Unit{Name: truncate(p.BaseUnit.Name, 50), Description: p.BaseUnit.FullName, Coefficient: 1}
+				err = service.repository.CreateUnit(unit)
+			}
+			if err != nil {
+				return err
+			}
+			product.UnitID = unit.ID
+		}
+		if product.UnitID == 0 {
+			result.skip("product", p.ID, p.Name, "missing_unit", "")
+			continue
+		}
+
+		// TVA: după rata din 1C; produsele noi fără rată primesc cota standard
+		if vatTaxID, reason := matchVatTax(vatTaxes, p.TaxRates); vatTaxID != 0 {
+			product.VatTaxID = vatTaxID
+		} else if reason != "" {
+			result.skip("product", p.ID, p.Name, reason, "")
+			continue
+		}
+		if product.VatTaxID == 0 {
+			for _, vt := range vatTaxes {
+				if vt.Category == models.VatCategoryStandard {
+					product.VatTaxID = vt.ID
+					break
+				}
+			}
+		}
+		if product.VatTaxID == 0 {
+			result.skip("product", p.ID, p.Name, "vat_tax_not_found", "")
+			continue
+		}
+
+		product.UUID = guid
+		product.Name = name
+		if sku := strings.TrimSpace(p.SKU); sku != "" {
+			product.SKU = truncate(sku, 50)
+		}
+		if p.Description != "" {
+			product.Description = p.Description
+		}
+		if err := service.repository.SaveProduct(product); err != nil {
+			result.skip("product", p.ID, p.Name, "database_error", err.Error())
+			continue
+		}
+		result.Products.count(created)
+	}
+	return nil
+}
+
+// matchVatTax găsește taxa TVA cu rata din 1C. Întoarce 0 și "" dacă 1C nu a trimis rata,
+// sau 0 și motivul dacă rata nu corespunde niciunei taxe.
+func matchVatTax(vatTaxes []models.VatTax, rates []commerceml.TaxRate) (uint, string) {
+	if len(rates) == 0 {
+		return 0, ""
+	}
+	rate, exempt, err := commerceml.ParseTaxRate(rates[0].Rate)
+	if err != nil {
+		return 0, "invalid_vat_rate"
+	}
+
+	today := time.Now()
+	for _, vt := range vatTaxes {
+		if exempt {
+			if vt.Category == models.VatCategoryExempt {
+				return vt.ID, ""
+			}
+			continue
+		}
+		if vt.Category == models.VatCategoryExempt {
+			continue
+		}
+		// Rata în vigoare azi: ultima versiune datată sau rata din VatTax
+		current := vt.Rate
+		for _, r := range vt.Rates {
+			if !r.ValidFrom.After(today) {
+				current = r.Rate
+			}
+		}
+		if current == rate {
+			return vt.ID, ""
+		}
+	}
+	return 0, "vat_tax_not_found"
+}
+
+func (service *Service) importPrices(msg *commerceml.Message, result *ExchangeResult) error {
+	if msg.Offers == nil {
+		return nil
+	}
+
+	// Tipurile de preț din fișier, după Ид
+	priceTypes := make(map[string]*models.PriceType)
+	currencies := make(map[string]string)
+	for _, pt := range msg.Offers.PriceTypes {
+		guid, ok := normalizeGUID(pt.ID)
+		name := truncate(pt.Name, 50)
+		if !ok {
+			result.skip("price_type", pt.ID, pt.Name, "invalid_guid", "")
+			continue
+		}
+		if name == "" {
+			result.skip("price_type", pt.ID, pt.Name, "missing_required_fields", "")
+			continue
+		}
+
+		priceType, err := service.repository.FindPriceTypeByUUID(guid)
+		if notFound(err) {
+			priceType, err = service.repository.FindPriceTypeByName(name)
+		}
+		created := notFound(err)
+		if created {
+			priceType, err = &models.PriceType{}, nil
+		}
+		if err != nil {
+			return err
+		}
+
+		priceType.UUID = guid
+		priceType.Name = name
+		if err := service.repository.SavePriceType(priceType); err != nil {
+			result.skip("price_type", pt.ID, pt.Name, "database_error", err.Error())
+			continue
+		}
+		result.PriceTypes.count(created)
+		priceTypes[guid] = priceType
+		currencies[guid] = pt.Currency
+	}
+
+	for _, offer := range msg.Offers.Offers {
+		guid, ok := normalizeGUID(offer.ProductID())
+		if !ok {
+			result.skip("price", offer.ID, offer.Name, "invalid_guid", "")
+			continue
+		}
+		product, err := service.repository.FindProductByUUID(guid)
+		if notFound(err) {
+			result.skip("price", offer.ID, offer.Name, "product_not_found", "")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, p := range offer.Prices {
+			typeGUID, _ := normalizeGUID(p.PriceTypeID)
+			priceType, ok := priceTypes[typeGUID]
+			if !ok {
+				priceType, err = service.repository.FindPriceTypeByUUID(typeGUID)
+				if notFound(err) {
+					result.skip("price", offer.ID, offer.Name, "price_type_not_found", p.PriceTypeID)
+					continue
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			value, err := commerceml.ParseNumber(p.UnitPrice)
+			if err != nil || value < 0 {
+				result.skip("price", offer.ID, offer.Name, "invalid_price", p.UnitPrice)
+				continue
+			}
+			// Prețul este pentru unitatea din ofertă; în catalog se păstrează prețul unității de bază
+			if p.Coefficient != "" {
+				if k, err := commerceml.ParseNumber(p.Coefficient); err == nil && k > 0 {
+					value /= k
+				}
+			}
+			// Prețurile din catalog sunt în moneda de bază
+			currency := p.Currency
+			if currency == "" {
+				currency = currencies[typeGUID]
+			}
+			if code := strings.ToUpper(strings.TrimSpace(currency)); code != "" && code != models.BaseCurrency && !isBaseCurrencyName(code) {
+				rate, err := service.ExchangeRateAt(code, time.Now())
+				if err != nil {
+					result.skip("price", offer.ID, offer.Name, "exchange_rate_not_found", currency)
+					continue
+				}
+				value *= rate
+			}
+
+			price, err := service.repository.FindPriceProduct(product.ID, priceType.ID)
+			created := notFound(err)
+			if created {
+				price, err = &models.PriceProduct{ProductID: product.ID, PriceTypeID: priceType.ID}, nil
+			}
+			if err != nil {
+				return err
+			}
+			price.Price = roundMoney(value)
+			if err := service.repository.SavePriceProduct(price); err != nil {
+				result.skip("price", offer.ID, offer.Name, "database_error", err.Error())
+				continue
+			}
+			result.Prices.count(created)
+		}
+	}
+	return nil
+}
+
+// isBaseCurrencyName - 1C scrie adesea moneda ca denumire sau cod numeric în loc de codul ISO
+func isBaseCurrencyName(code string) bool {
+	switch code {
+	case "498", "LEI", "ЛЕЙ", "MDL.":
+		return true
+	}
+	return false
+}
+
+// ExportOrdersCommerceML generează fișierul CommerceML cu comenzile pentru 1C.
+// Implicit se trimit doar comenzile modificate după ultimul export reușit; full = true trimite toate comenzile.
+// Întoarce conținutul fișierului și numărul de comenzi.
+func (service *Service) ExportOrdersCommerceML(userID uint, full bool) ([]byte, int, error) {
+	log := &models.ExchangeLog{
+		System:    exchangeSystem1C,
+		Direction: models.ExchangeExport,
+		Kind:      "orders",
+		FileName:  "orders.xml",
+		StartedAt: time.Now(),
+		UserID:    userID,
+	}
+	if !full {
+		last, err := service.repository.FindLastExchangeLog(exchangeSystem1C, models.ExchangeExport, "orders")
+		if err != nil && !notFound(err) {
+			return nil, 0, err
+		}
+		if err == nil {
+			log.ChangedSince = &last.StartedAt
+		}
+	}
+
+	orders, err := service.repository.FindOrdersChangedSince(log.ChangedSince)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	documents := make([]commerceml.Document, 0, len(orders))
+	for i := range orders {
+		documents = append(documents, orderDocument(&orders[i]))
+	}
+
+	var buf bytes.Buffer
+	if err := commerceml.WriteDocuments(&buf, log.StartedAt, documents); err != nil {
+		return nil, 0, err
+	}
+
+	log.Status = "success"
+	log.Records = len(documents)
+	if err := service.repository.CreateExchangeLog(log); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), len(documents), nil
+}
+
+// orderDocument transformă comanda în documentul CommerceML "Заказ товара".
+// Sumele pozițiilor sunt fără TVA (УчтеноВСумме = false), suma documentului este cu TVA.
+func orderDocument(order *models.Order) commerceml.Document {
+	client := order.Client
+	counterparty := commerceml.Counterparty{
+		ID:       client.UUID,
+		Name:     client.Name,
+		Role:     commerceml.RoleBuyer,
+		FullName: client.Name,
+		FiscalID: client.FiscalID,
+	}
+	if client.Address != "" {
+		counterparty.Address = &commerceml.Address{Text: client.Address}
+	}
+	var contacts []commerceml.Contact
+	if client.Phone != "" {
+		contacts = append(contacts, commerceml.Contact{Type: "Телефон рабочий", Value: client.Phone})
+	}
+	if client.Email != nil {
+		contacts = append(contacts, commerceml.Contact{Type: "Почта", Value: *client.Email})
+	}
+	if len(contacts) > 0 {
+		counterparty.Contacts = &commerceml.Contacts{Items: contacts}
+	}
+
+	doc := commerceml.Document{
+		ID:             order.UUID,
+		Number:         strconv.FormatUint(uint64(order.ID), 10),
+		Date:           order.Date.Format("2006-01-02"),
+		Time:           order.CreatedAt.Format("15:04:05"),
+		Operation:      commerceml.OperationOrder,
+		Role:           commerceml.RoleSeller,
+		Currency:       order.Currency,
+		Rate:           commerceml.FormatQuantity(order.ExchangeRate),
+		Amount:         commerceml.FormatMoney(order.TotalPrice),
+		Counterparties: []commerceml.Counterparty{counterparty},
+		Taxes: &commerceml.Taxes{Items: []commerceml.Tax{
+			{Name: commerceml.TaxVAT, IncludedInAmount: false, Amount: commerceml.FormatMoney(order.TotalVat)},
+		}},
+		DeletionMark: order.DeletedAt.Valid,
+	}
+
+	for _, item := range order.OrderItems {
+		unitName := item.UnitName
+		if unitName == "" {
+			unitName = item.Unit.Name
+		}
+		rate := commerceml.FormatQuantity(item.VatRate)
+		if item.VatCategory == models.VatCategoryExempt {
+			rate = "Без НДС"
+		}
+		doc.Items = append(doc.Items, commerceml.DocumentItem{
+			ID:        item.Product.UUID,
+			SKU:       item.Product.SKU,
+			Name:      item.Product.Name,
+			BaseUnit:  &commerceml.Unit{Name: unitName},
+			UnitPrice: commerceml.FormatMoney(item.Price),
+			Quantity:  commerceml.FormatQuantity(item.Quantity),
+			Amount:    commerceml.FormatMoney(item.Summ),
+			Unit:      unitName,
+			Taxes: &commerceml.Taxes{Items: []commerceml.Tax{
+				{Name: commerceml.TaxVAT, IncludedInAmount: false, Amount: commerceml.FormatMoney(item.VatSumm), Rate: rate},
+			}},
+		})
+	}
+
+	requisites := []commerceml.RequisiteValue{{Name: "Статус заказа", Value: order.Status}}
+	if order.Contract.Number != "" {
+		requisites = append(requisites, commerceml.RequisiteValue{Name: "Договор", Value: fmt.Sprintf("%s от %s", order.Contract.Number, order.Contract.Date.Format("02.01.2006"))})
+	}
+	doc.Requisites = &commerceml.Requisites{Items: requisites}
+	return doc
+}
+
+// FindExchangeLogs întoarce ultimele schimburi cu 1C
+func (service *Service) FindExchangeLogs(limit int) ([]models.ExchangeLog, error) {
+	return service.repository.FindExchangeLogs(exchangeSystem1C, limit)
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"orders/internal/config"
+	"orders/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeRepository - depozitul în memorie folosit de testele serviciului.
+// Implementează doar metodele de care au nevoie testele; apelul altor metode oprește testul (interfața încorporată e nil).
+type fakeRepository struct {
+	Repository
+	*fakeStore
+	scope *models.ChannelScope // Restricția adăugată de WithChannelScope
+}
+
+// fakeStore - datele depozitului, comune tuturor copiilor create de WithChannelScope
+type fakeStore struct {
+	users      map[uint]*models.User
+	sessions   map[uint]*models.Session
+	clients    map[uint]*models.Client
+	candidates map[uint]*models.DuplicateCandidate
+	merges     map[uint]*models.ClientMerge
+	types      []models.ClientType
+	channels   map[uint]*models.Channel
+	contracts  map[uint]*models.Contract
+	orders     []*models.Order
+	imports    []*models.Import
+	nextID     uint
+	audits     []*models.AuditLog
+	writes     int // Clienții creați sau modificați
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{fakeStore: &fakeStore{
+		users:      map[uint]*models.User{},
+		sessions:   map[uint]*models.Session{},
+		clients:    map[uint]*models.Client{},
+		candidates: map[uint]*models.DuplicateCandidate{},
+		merges:     map[uint]*models.ClientMerge{},
+		types:      []models.ClientType{{Model: gorm.Model{ID: 1}, Name: "company"}, {Model: gorm.Model{ID: 2}, Name: "individual"}},
+		channels:   map[uint]*models.Channel{},
+		contracts:  map[uint]*models.Contract{},
+	}}
+}
+
+// newTestService construiește serviciul peste depozitul în memorie, fără config.Load()
+func newTestService(repo Repository) *Service {
+	return &Service{
+		repository: repo,
+		jwtSecret:  "test-secret",
+		cfg:        &config.Config{AccessTokenTTLMinutes: 15, RefreshTokenTTLDays: 30, MaxUploadMB: 1},
+	}
+}
+
+func (repo *fakeRepository) newID() uint {
+	repo.nextID++
+	return repo.nextID
+}
+
+// addClient salvează clientul cu un ID nou și îl întoarce
+func (repo *fakeRepository) addClient(client models.Client) *models.Client {
+	client.ID = repo.newID()
+	repo.clients[client.ID] = &client
+	return &client
+}
+
+// visible - înregistrarea din canalul dat este în canalele restricției (ca apelurile GORM pe clients, contracts și orders)
+func (repo *fakeRepository) visible(channelID *uint) bool {
+	if repo.scope == nil || repo.scope.All {
+		return true
+	}
+	return channelID != nil && repo.scope.Has(*channelID)
+}
+
+// WithChannelScope întoarce o copie care vede doar clienții din canalele date; datele rămân comune
+func (repo *fakeRepository) WithChannelScope(scope models.ChannelScope) Repository {
+	scoped := *repo
+	scoped.scope = &scope
+	return &scoped
+}
+
+// Users
+
+func (repo *fakeRepository) FindUserByID(id uint) (*models.User, error) {
+	user, ok := repo.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (repo *fakeRepository) FindUserByEmail(email string) (*models.User, error) {
+	for _, user := range repo.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *fakeRepository) CountUsersByRole(role string) (int64, error) {
+	var count int64
+	for _, user := range repo.users {
+		if user.Role == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo *fakeRepository) UpdateUserRole(user *models.User, audit *models.AuditLog) error {
+	repo.users[user.ID].Role = user.Role
+	repo.audits = append(repo.audits, audit)
+	return nil
+}
+
+// Sessions
+
+func (repo *fakeRepository) CreateSession(session *models.Session) error {
+	session.ID = repo.newID()
+	copied := *session
+	repo.sessions[session.ID] = &copied
+	return nil
+}
+
+func (repo *fakeRepository) FindSessionByID(id uint) (*models.Session, error) {
+	session, ok := repo.sessions[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (repo *fakeRepository) FindSessionByTokenHash(hash string) (*models.Session, error) {
+	for _, session := range repo.sessions {
+		if session.TokenHash == hash || session.PreviousTokenHash == hash {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *fakeRepository) RotateSession(session *models.Session, previousHash string) error {
+	stored, ok := repo.sessions[session.ID]
+	if !ok || stored.TokenHash != previousHash || stored.RevokedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	stored.TokenHash = session.TokenHash
+	stored.PreviousTokenHash = session.PreviousTokenHash
+	stored.ExpiresAt = session.ExpiresAt
+	stored.LastUsedAt = session.LastUsedAt
+	return nil
+}
+
+func (repo *fakeRepository) RevokeSession(id uint) error {
+	if session, ok := repo.sessions[id]; ok && session.RevokedAt == nil {
+		now := time.Now()
+		session.RevokedAt = &now
+	}
+	return nil
+}
+
+func (repo *fakeRepository) RevokeUserSessions(userID uint, audit func(revoked int64) *models.AuditLog) (int64, error) {
+	var revoked int64
+	now := time.Now()
+	for _, session := range repo.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+			revoked++
+		}
+	}
+	repo.audits = append(repo.audits, audit(revoked))
+	return revoked, nil
+}
+
+// Clients
+
+// FindClientByID găsește doar clienții neșterși, ca în baza de date
+func (repo *fakeRepository) FindClientByID(id uint) (*models.Client, error) {
+	client, ok := repo.clients[id]
+	if !ok || client.DeletedAt.Valid || !repo.visible(client.ChannelID) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *client
+	return &copied, nil
+}
+
+func (repo *fakeRepository) ClientIdentityTaken(fiscalID string, email *string, exceptID uint) (bool, error) {
+	for _, client := range repo.clients {
+		if client.ID == exceptID || client.DeletedAt.Valid {
+			continue
+		}
+		if client.FiscalID == fiscalID {
+			return true, nil
+		}
+		if email != nil && *email != "" && client.Email != nil && strings.EqualFold(*client.Email, *email) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Duplicates
+
+func (repo *fakeRepository) FindDuplicateCandidateByID(id uint) (*models.DuplicateCandidate, error) {
+	candidate, ok := repo.candidates[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *candidate
+	return &copied, nil
+}
+
+func (repo *fakeRepository) MergeClients(merge *models.ClientMerge, audit func(merge *models.ClientMerge) *models.AuditLog) error {
+	merge.ID = repo.newID()
+	stored := *merge
+	repo.merges[merge.ID] = &stored
+	repo.clients[merge.MergedID].DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	for _, candidate := range repo.candidates {
+		pair := map[uint]bool{candidate.ClientAID: true, candidate.ClientBID: true}
+		if pair[merge.SurvivorID] && pair[merge.MergedID] {
+			candidate.Status = models.DuplicateMerged
+		}
+	}
+	repo.audits = append(repo.audits, audit(merge))
+	return nil
+}
+
+func (repo *fakeRepository) UndoClientMerge(merge *models.ClientMerge, audit *models.AuditLog) error {
+	stored := repo.merges[merge.ID]
+	stored.UndoneAt, stored.UndoneByID = merge.UndoneAt, merge.UndoneByID
+	repo.clients[merge.MergedID].DeletedAt = gorm.DeletedAt{}
+	if merge.CandidateID != nil {
+		repo.candidates[*merge.CandidateID].Status = models.DuplicateOpen
+	}
+	repo.audits = append(repo.audits, audit)
+	return nil
+}
+
+// FindClientMergeByID încarcă și clienții șterși logic, ca Preload cu Unscoped
+func (repo *fakeRepository) FindClientMergeByID(id uint) (*models.ClientMerge, error) {
+	merge, ok := repo.merges[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *merge
+	copied.Survivor = *repo.clients[merge.SurvivorID]
+	copied.Merged = *repo.clients[merge.MergedID]
+	return &copied, nil
+}
+
+func (repo *fakeRepository) FindClientByFiscalID(fiscalID string) (*models.Client, error) {
+	for _, client := range repo.clients {
+		if client.FiscalID == fiscalID && !client.DeletedAt.Valid && repo.visible(client.ChannelID) {
+			copied := *client
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// FindClientByEmail caută în toate canalele, ca verificarea unicității din depozit
+func (repo *fakeRepository) FindClientByEmail(email string) (*models.Client, error) {
+	for _, client := range repo.clients {
+		if client.Email != nil && strings.EqualFold(*client.Email, email) && !client.DeletedAt.Valid {
+			copied := *client
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *fakeRepository) CreateClient(client *models.Client) error {
+	repo.writes++
+	*client = *repo.addClient(*client)
+	return nil
+}
+
+func (repo *fakeRepository) UpdateClient(client *models.Client) error {
+	repo.writes++
+	copied := *client
+	repo.clients[client.ID] = &copied
+	return nil
+}
+
+func (repo *fakeRepository) MoveClient(client *models.Client) error {
+	return repo.UpdateClient(client)
+}
+
+// Client types and channels
+
+func (repo *fakeRepository) FindAllClientTypes() ([]models.ClientType, error) {
+	return repo.types, nil
+}
+
+func (repo *fakeRepository) FindClientTypeByID(id uint) (*models.ClientType, error) {
+	for _, clientType := range repo.types {
+		if clientType.ID == id {
+			return &clientType, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *fakeRepository) FindChannelByID(id uint) (*models.Channel, error) {
+	channel, ok := repo.channels[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return channel, nil
+}
+
+// Contracts and orders
+
+// addContract salvează contractul activ cu un ID nou și îl întoarce
+func (repo *fakeRepository) addContract(contract models.Contract) *models.Contract {
+	contract.ID = repo.newID()
+	contract.Status = models.ContractStatusActive
+	repo.contracts[contract.ID] = &contract
+	return &contract
+}
+
+func (repo *fakeRepository) FindContractByID(id uint) (*models.Contract, error) {
+	contract, ok := repo.contracts[id]
+	if !ok || !repo.visible(contract.ChannelID) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *contract
+	return &copied, nil
+}
+
+// FindContractVersionAt - contractele din teste au o singură versiune, valabilă de la data semnării
+func (repo *fakeRepository) FindContractVersionAt(contractID uint, date time.Time) (*models.ContractAmendment, error) {
+	contract, ok := repo.contracts[contractID]
+	if !ok || date.Before(contract.Date) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &models.ContractAmendment{ContractID: contractID, Version: 1, StartDate: contract.Date}, nil
+}
+
+func (repo *fakeRepository) CreateOrder(order *models.Order) error {
+	order.ID = repo.newID()
+	repo.orders = append(repo.orders, order)
+	return nil
+}
+
+// Imports
+
+func (repo *fakeRepository) CreateImport(imp *models.Import) error {
+	imp.ID = repo.newID()
+	repo.imports = append(repo.imports, imp)
+	return nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"orders/internal/validation"
+)
+
+// ErrInvalidFiscalID - codul fiscal nu este un IDNO / IDNP valid pentru tipul clientului
+var ErrInvalidFiscalID = errors.New("invalid_fiscal_id")
+
+// FiscalIDError descrie de ce codul fiscal nu este valid (errors.Is(err, ErrInvalidFiscalID) == true)
+type FiscalIDError struct {
+	ExpectedKind string // IDNO, IDNP sau "" (oricare)
+	Detail       string // invalid_format, invalid_checksum, wrong_kind
+}
+
+func (e *FiscalIDError) Error() string { return ErrInvalidFiscalID.Error() + ": " + e.Detail }
+
+func (e *FiscalIDError) Unwrap() error { return ErrInvalidFiscalID }
+
+// Tipul de cod fiscal așteptat pentru fiecare tip de client.
+// Tipurile care lipsesc (ex: "other") acceptă atât IDNO, cât și IDNP.
+var fiscalIDKindByClientType = map[string]string{
+	"individual": validation.FiscalIDKindIDNP,
+	"company":    validation.FiscalIDKindIDNO,
+	"government": validation.FiscalIDKindIDNO,
+	"ngo":        validation.FiscalIDKindIDNO,
+}
+
+// checkClientFiscalID normalizează codul fiscal al clientului și îl verifică după tipul clientului
+func (service *Service) checkClientFiscalID(client *models.Client) error {
+	clientType, err := service.repository.FindClientTypeByID(client.ClientTypeID)
+	if err != nil {
+		return err
+	}
+
+	client.FiscalID = validation.NormalizeFiscalID(client.FiscalID)
+	kind := fiscalIDKindByClientType[clientType.Name]
+	if detail := validation.CheckFiscalID(client.FiscalID, kind); detail != "" {
+		return &FiscalIDError{ExpectedKind: kind, Detail: detail}
+	}
+	return nil
+}
+
+// InvalidFiscalIDReport parcurge toți clienții și îi întoarce pe cei cu cod fiscal invalid
+func (service *Service) InvalidFiscalIDReport() ([]models.InvalidFiscalIDEntry, error) {
+	rows := make([]models.InvalidFiscalIDEntry, 0)
+	err := service.repository.FindClientsInBatches(500, func(clients []models.Client) error {
+		for _, client := range clients {
+			kind := fiscalIDKindByClientType[client.ClientType.Name]
+			detail := validation.CheckFiscalID(validation.NormalizeFiscalID(client.FiscalID), kind)
+			if detail == "" {
+				continue
+			}
+			rows = append(rows, models.InvalidFiscalIDEntry{
+				ClientID:     client.ID,
+				ClientName:   client.Name,
+				ClientType:   client.ClientType.Name,
+				FiscalID:     client.FiscalID,
+				ExpectedKind: kind,
+				Reason:       detail,
+			})
+		}
+		return nil
+	})
+	return rows, err
+}
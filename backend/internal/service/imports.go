@@ -0,0 +1,339 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"orders/internal/models"
+	"orders/internal/spreadsheet"
+	"orders/internal/validation"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Erori pentru importul din fișiere
+var (
+	ErrInvalidImportMode  = errors.New("invalid_mode")
+	ErrUnknownImportField = errors.New("unknown_field")
+	ErrImportNoResult     = errors.New("import_has_no_result_file")
+)
+
+// Modurile de import
+const (
+	ImportModeCreate = "create" // Doar clienți noi; cei existenți (după codul fiscal) se sar
+	ImportModeUpsert = "upsert" // Clienții existenți (după codul fiscal) se actualizează
+)
+
+// MissingColumnsError - fișierul nu conține coloanele obligatorii (după mapare)
+type MissingColumnsError struct {
+	Columns []string
+}
+
+func (e *MissingColumnsError) Error() string {
+	return "missing_columns: " + strings.Join(e.Columns, ", ")
+}
+
+// ImportOptions - parametrii importului
+type ImportOptions struct {
+	Mode    string            // ImportModeCreate sau ImportModeUpsert
+	DryRun  bool              // Doar validare, fără scriere
+	Mapping map[string]string // Câmpul clientului -> denumirea coloanei din fișier (implicit coloana cu același nume)
+}
+
+// Câmpurile clientului care pot fi importate; primele trei sunt obligatorii
+var clientImportFields = []string{"client_type", "name", "fiscal_code", "email", "phone", "address", "channel_id"}
+
+const requiredClientImportFields = 3
+
+// ImportClients importă clienții dintr-un fișier CSV sau XLSX.
+// Fiecare rând se validează separat (câmpuri obligatorii, tipul clientului, codul fiscal, duplicate în fișier);
+// rândurile greșite se sar, iar rezultatul pe rânduri se salvează într-un fișier CSV descărcabil.
+func (service *Service) ImportClients(userID uint, fileName string, r io.Reader, opts ImportOptions) (*models.Import, []models.ImportRow, error) {
+	if opts.Mode == "" {
+		opts.Mode = ImportModeCreate
+	}
+	if opts.Mode != ImportModeCreate && opts.Mode != ImportModeUpsert {
+		return nil, nil, ErrInvalidImportMode
+	}
+
+	maxSize := service.cfg.MaxUploadMB << 20
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil, ErrEmptyFile
+	}
+	if int64(len(data)) > maxSize {
+		return nil, nil, ErrFileTooLarge
+	}
+
+	table, err := spreadsheet.Read(fileName, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(table) == 0 {
+		return nil, nil, ErrEmptyFile
+	}
+
+	columns, err := importColumns(table[0], opts.Mapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clientTypes, err := service.repository.FindAllClientTypes()
+	if err != nil {
+		return nil, nil, err
+	}
+	typesByKey := make(map[string]uint, len(clientTypes)*2)
+	for _, ct := range clientTypes {
+		typesByKey[strings.ToLower(ct.Name)] = ct.ID
+		typesByKey[strconv.FormatUint(uint64(ct.ID), 10)] = ct.ID
+	}
+
+	imp := &models.Import{
+		Kind:     "clients",
+		FileName: filepath.Base(fileName),
+		Mode:     opts.Mode,
+		DryRun:   opts.DryRun,
+		OwnerID:  userID,
+	}
+	results := make([]models.ImportRow, 0, len(table)-1)
+	seen := make(map[string]int)
+	for i := 1; i < len(table); i++ {
+		if isEmptyRow(table[i]) {
+			continue
+		}
+		value := func(field string) string {
+			col, ok := columns[field]
+			if !ok || col >= len(table[i]) {
+				return ""
+			}
+			return strings.TrimSpace(table[i][col])
+		}
+
+		row := service.importClientRow(i+1, value, typesByKey, seen, opts)
+		row.Original = table[i]
+		switch row.Action {
+		case "create":
+			imp.Created++
+		case "update":
+			imp.Updated++
+		default:
+			imp.Skipped++
+		}
+		results = append(results, row)
+	}
+	imp.TotalRows = len(results)
+
+	// Fișierul rezultat: starea fiecărui rând, urmată de coloanele originale
+	var buf bytes.Buffer
+	out := [][]string{append([]string{"row", "action", "reason", "detail", "id"}, table[0]...)}
+	for _, row := range results {
+		id := ""
+		if row.ID != 0 {
+			id = strconv.FormatUint(uint64(row.ID), 10)
+		}
+		out = append(out, append([]string{strconv.Itoa(row.Row), row.Action, row.Reason, row.Detail, id}, row.Original...))
+	}
+	if err := spreadsheet.WriteCSV(&buf, out); err != nil {
+		return nil, nil, err
+	}
+	imp.ResultKey = fmt.Sprintf("import/%s.csv", uuid.New().String())
+	if _, err := service.storage.Save(imp.ResultKey, &buf); err != nil {
+		return nil, nil, err
+	}
+
+	if err := service.repository.CreateImport(imp); err != nil {
+		service.storage.Delete(imp.ResultKey)
+		return nil, nil, err
+	}
+	return imp, results, nil
+}
+
+// importClientRow validează (și, dacă nu e dry-run, salvează) un rând al fișierului
+func (service *Service) importClientRow(rowNumber int, value func(string) string, typesByKey map[string]uint, seen map[string]int, opts ImportOptions) models.ImportRow {
+	fiscalID := validation.NormalizeFiscalID(value("fiscal_code"))
+	row := models.ImportRow{Row: rowNumber, Action: "skip", Key: fiscalID}
+
+	if value("name") == "" || fiscalID == "" || value("client_type") == "" {
+		row.Reason = "missing_required_fields"
+		return row
+	}
+	clientTypeID, ok := typesByKey[strings.ToLower(value("client_type"))]
+	if !ok {
+		row.Reason = "client_type_not_found"
+		row.Detail = value("client_type")
+		return row
+	}
+	if first, ok := seen[fiscalID]; ok {
+		row.Reason = "duplicate_in_file"
+		row.Detail = fmt.Sprintf("row %d", first)
+		return row
+	}
+	seen[fiscalID] = rowNumber
+
+	var channelID *uint
+	if v := value("channel_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || id == 0 {
+			row.Reason = "invalid_channel_id"
+			row.Detail = v
+			return row
+		}
+		channel := uint(id)
+		channelID = &channel
+	}
+
+	existing, err := service.repository.FindClientByFiscalID(fiscalID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		row.Reason, row.Detail = "database_error", err.Error()
+		return row
+	}
+
+	if err != nil {
+		// Client nou
+		email := value("email")
+		client := &models.Client{
+			ClientTypeID: clientTypeID,
+			Name:         value("name"),
+			FiscalID:     fiscalID,
+			Email:        &email,
+			Phone:        value("phone"),
+			Address:      value("address"),
+			ChannelID:    channelID,
+		}
+		// Importul de probă face aceleași verificări (inclusiv canalul), doar că nu salvează
+		if opts.DryRun {
+			err = service.prepareClient(client)
+		} else {
+			err = service.CreateClient(client)
+		}
+		if err != nil {
+			row.Reason, row.Detail = importErrorReason(err)
+			return row
+		}
+		row.Action, row.ID = "create", client.ID
+		return row
+	}
+
+	if opts.Mode != ImportModeUpsert {
+		row.Reason = "duplicate"
+		row.ID = existing.ID
+		return row
+	}
+
+	// Actualizare: se suprascriu doar celulele completate
+	stored := *existing
+	existing.ClientTypeID = clientTypeID
+	existing.ClientType = models.ClientType{}
+	existing.Name = value("name")
+	if v := value("email"); v != "" {
+		existing.Email = &v
+	}
+	if v := value("phone"); v != "" {
+		existing.Phone = v
+	}
+	if v := value("address"); v != "" {
+		existing.Address = v
+	}
+	if channelID != nil {
+		existing.ChannelID = channelID
+		existing.Channel = nil
+	}
+	if opts.DryRun {
+		_, err = service.prepareClientUpdate(existing, &stored)
+	} else {
+		err = service.UpdateClient(existing)
+	}
+	if err != nil {
+		row.Reason, row.Detail = importErrorReason(err)
+		return row
+	}
+	row.Action, row.ID = "update", existing.ID
+	return row
+}
+
+// importColumns găsește indexul coloanei pentru fiecare câmp, după antet și maparea primită
+func importColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	for field := range mapping {
+		known := false
+		for _, f := range clientImportFields {
+			if f == field {
+				known = true
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownImportField, field)
+		}
+	}
+
+	byName := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, ok := byName[key]; !ok {
+			byName[key] = i
+		}
+	}
+
+	columns := make(map[string]int)
+	var missing []string
+	for i, field := range clientImportFields {
+		source := field
+		if m, ok := mapping[field]; ok && strings.TrimSpace(m) != "" {
+			source = m
+		}
+		if col, ok := byName[strings.ToLower(strings.TrimSpace(source))]; ok {
+			columns[field] = col
+		} else if i < requiredClientImportFields {
+			missing = append(missing, source)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &MissingColumnsError{Columns: missing}
+	}
+	return columns, nil
+}
+
+// importErrorReason transformă eroarea de salvare în motivul din raport
+func importErrorReason(err error) (string, string) {
+	var fiscalErr *FiscalIDError
+	switch {
+	case errors.As(err, &fiscalErr):
+		return ErrInvalidFiscalID.Error(), fiscalErr.Detail
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return "not_found", ""
+	case errors.Is(err, ErrInvalidPhone), errors.Is(err, ErrInvalidEmail), errors.Is(err, ErrDuplicateEmail),
+		errors.Is(err, ErrChannelRequired), errors.Is(err, ErrChannelForbidden), errors.Is(err, ErrChannelNotFound):
+		return err.Error(), ""
+	default:
+		return "database_error", err.Error()
+	}
+}
+
+func isEmptyRow(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// FindImportByID întoarce importul după ID
+func (service *Service) FindImportByID(id uint) (*models.Import, error) {
+	return service.repository.FindImportByID(id)
+}
+
+// OpenImportResult deschide fișierul CSV cu rezultatul importului
+func (service *Service) OpenImportResult(imp *models.Import) (io.ReadCloser, error) {
+	if imp.ResultKey == "" {
+		return nil, ErrImportNoResult
+	}
+	return service.storage.Open(imp.ResultKey)
+}
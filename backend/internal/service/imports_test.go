@@ -0,0 +1,117 @@
+package service
+
+import (
+	"orders/internal/models"
+	"orders/internal/storage"
+	"strings"
+	"testing"
+)
+
+const importTestFile = `client_type,name,fiscal_code,email,phone,channel_id
+company,Agro SRL,1003600001209,,,1
+company,Vin SRL,1003600001210,,,3
+company,Lapte SRL,1003600001221,,,
+company,Pâine SRL,1003600001232,,telefon,2
+company,Agro SRL,1003600001209,,,1
+`
+
+// newImportTestService - canalele 1-3 și un client existent în canalul 2; scope nil - fără restricții
+func newImportTestService(t *testing.T, scope *models.ChannelScope) (*Service, *fakeRepository) {
+	t.Helper()
+	repo := newFakeRepository()
+	for _, id := range []uint{1, 2, 3} {
+		repo.channels[id] = &models.Channel{Name: "canal"}
+	}
+	channel := uint(2)
+	repo.addClient(models.Client{ClientTypeID: 1, Name: "Pâine", FiscalID: "1003600001232", ChannelID: &channel})
+	service := newTestService(repo)
+	service.storage = &storage.LocalStorage{Root: t.TempDir()}
+	if scope != nil {
+		service = service.WithChannelScope(*scope)
+	}
+	return service, repo
+}
+
+// Importul de probă trebuie să dea aceleași rezultate ca importul real, fără să scrie clienți
+func TestImportClientsDryRunMatchesImport(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope *models.ChannelScope
+		file  string
+		mode  string
+		want  []string // action/reason pe rânduri
+	}{
+		{
+			name:  "user with two channels",
+			scope: &models.ChannelScope{ChannelIDs: []uint{1, 2}},
+			file:  importTestFile,
+			mode:  ImportModeUpsert,
+			want:  []string{"create", "skip/channel_forbidden", "skip/channel_required", "skip/invalid_phone", "skip/duplicate_in_file"},
+		},
+		{
+			name:  "user with one channel",
+			scope: &models.ChannelScope{ChannelIDs: []uint{1}},
+			file:  "client_type,name,fiscal_code,channel_id\ncompany,Lapte SRL,1003600001221,\ncompany,Vin SRL,1003600001210,2\n",
+			mode:  ImportModeCreate,
+			want:  []string{"create", "skip/channel_forbidden"},
+		},
+		{
+			name:  "client moved to a forbidden channel",
+			scope: &models.ChannelScope{ChannelIDs: []uint{2}},
+			file:  "client_type,name,fiscal_code,channel_id\ncompany,Pâine SRL,1003600001232,3\ncompany,Pâine SRL,1003600001232,\n",
+			mode:  ImportModeUpsert,
+			want:  []string{"skip/channel_forbidden", "skip/duplicate_in_file"},
+		},
+		{
+			name: "admin with unknown channel",
+			file: "client_type,name,fiscal_code,channel_id\ncompany,Vin SRL,1003600001210,9\ncompany,Pâine SRL,1003600001232,1\n",
+			mode: ImportModeUpsert,
+			want: []string{"skip/channel_not_found", "update"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, dryRun := range []bool{true, false} {
+				service, repo := newImportTestService(t, tt.scope)
+				imp, rows, err := service.ImportClients(1, "clients.csv", strings.NewReader(tt.file), ImportOptions{Mode: tt.mode, DryRun: dryRun})
+				if err != nil {
+					t.Fatalf("ImportClients(dry run %v): %v", dryRun, err)
+				}
+				got := make([]string, len(rows))
+				for i, row := range rows {
+					got[i] = row.Action
+					if row.Reason != "" {
+						got[i] += "/" + row.Reason
+					}
+				}
+				if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+					t.Errorf("dry run %v: rows = %q, want %q", dryRun, got, tt.want)
+				}
+				if imp.DryRun != dryRun || len(repo.imports) != 1 {
+					t.Errorf("dry run %v: import = %+v, %d saved", dryRun, imp, len(repo.imports))
+				}
+				if dryRun && repo.writes != 0 {
+					t.Errorf("dry run wrote %d clients", repo.writes)
+				}
+				if !dryRun && repo.writes != imp.Created+imp.Updated {
+					t.Errorf("import wrote %d clients, want %d", repo.writes, imp.Created+imp.Updated)
+				}
+			}
+		})
+	}
+}
+
+func TestImportClientsChannelFromScope(t *testing.T) {
+	service, repo := newImportTestService(t, &models.ChannelScope{ChannelIDs: []uint{3}})
+	_, rows, err := service.ImportClients(1, "clients.csv", strings.NewReader("client_type,name,fiscal_code\ncompany,Vin SRL,1003600001210\n"), ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportClients: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Action != "create" {
+		t.Fatalf("rows = %+v", rows)
+	}
+	client := repo.clients[rows[0].ID]
+	if client.ChannelID == nil || *client.ChannelID != 3 {
+		t.Errorf("channel = %v, want 3", client.ChannelID)
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"orders/internal/validation"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Erori pentru utilizatori și invitații
+var (
+	ErrUserExists           = errors.New("user_exists")
+	ErrPasswordTooShort     = errors.New("password_too_short")
+	ErrInvitationInvalid    = errors.New("invitation_invalid")
+	ErrInvitationExpired    = errors.New("invitation_expired")
+	ErrInvitationNotPending = errors.New("invitation_not_pending")
+)
+
+// Lungimea minimă a parolei
+const minPasswordLength = 8
+
+// hashPassword verifică lungimea parolei și întoarce hash-ul bcrypt
+func hashPassword(password string) (string, error) {
+	if len([]rune(password)) < minPasswordLength {
+		return "", ErrPasswordTooShort
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CreateInitialAdmin creează administratorul din ADMIN_EMAIL / ADMIN_PASSWORD, dacă încă nu există niciun
+// administrator (la prima pornire); ceilalți utilizatori intră doar prin invitații.
+func (service *Service) CreateInitialAdmin(email, password string) (bool, error) {
+	admins, err := service.repository.CountUsersByRole(models.RoleAdmin)
+	if err != nil || admins > 0 {
+		return false, err
+	}
+	email, ok := validation.NormalizeEmail(email)
+	if !ok || email == "" {
+		return false, ErrInvalidEmail
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return false, err
+	}
+	user := &models.User{Email: email, Password: hash, Role: models.RoleAdmin}
+	if err := service.repository.CreateUser(user); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateInvitation invită utilizatorul cu rolul și canalele date. Întoarce invitația și token-ul,
+// care se transmite invitatului și nu mai poate fi citit ulterior. Invitațiile neacceptate
+// trimise anterior pe același email se anulează.
+func (service *Service) CreateInvitation(adminID uint, email, role string, channelIDs []uint) (*models.Invitation, string, error) {
+	email, ok := validation.NormalizeEmail(email)
+	if !ok || email == "" {
+		return nil, "", ErrInvalidEmail
+	}
+	role = strings.ToLower(strings.TrimSpace(role))
+	if !IsValidRole(role) {
+		return nil, "", ErrInvalidRole
+	}
+	if _, err := service.repository.FindUserByEmail(email); err == nil {
+		return nil, "", ErrUserExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", err
+	}
+
+	channels := make([]models.Channel, 0, len(channelIDs))
+	for _, id := range channelIDs {
+		channel, err := service.repository.FindChannelByID(id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrChannelNotFound
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		channels = append(channels, *channel)
+	}
+
+	token, hash, err := newSecretToken()
+	if err != nil {
+		return nil, "", err
+	}
+	invitation := &models.Invitation{
+		Email:       email,
+		Role:        role,
+		TokenHash:   hash,
+		ExpiresAt:   time.Now().Add(time.Duration(service.cfg.InvitationTTLHours) * time.Hour),
+		InvitedByID: adminID,
+		Channels:    channels,
+	}
+	audit := func(invitation *models.Invitation) *models.AuditLog {
+		return newAuditLog(adminID, AuditUserInvite, "invitation", invitation.ID, map[string]interface{}{
+			"email": email, "role": role, "channel_ids": channelIDs,
+		})
+	}
+	if err := service.repository.CreateInvitation(invitation, audit); err != nil {
+		return nil, "", err
+	}
+	return invitation, token, nil
+}
+
+// FindInvitations întoarce invitațiile, cele mai noi primele (status = "" - toate)
+func (service *Service) FindInvitations(status string) ([]models.Invitation, error) {
+	invitations, err := service.repository.FindInvitations()
+	if err != nil || status == "" {
+		return invitations, err
+	}
+	now := time.Now()
+	filtered := make([]models.Invitation, 0, len(invitations))
+	for _, invitation := range invitations {
+		if invitation.Status(now) == status {
+			filtered = append(filtered, invitation)
+		}
+	}
+	return filtered, nil
+}
+
+// RevokeInvitation anulează invitația neacceptată
+func (service *Service) RevokeInvitation(adminID, id uint) (*models.Invitation, error) {
+	invitation, err := service.repository.FindInvitationByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.Status(time.Now()) != models.InvitationPending {
+		return nil, ErrInvitationNotPending
+	}
+	now := time.Now()
+	invitation.RevokedAt = &now
+	audit := newAuditLog(adminID, AuditUserInviteRevoke, "invitation", invitation.ID, map[string]string{"email": invitation.Email})
+	if err := service.repository.RevokeInvitation(invitation, audit); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// FindInvitationByToken întoarce invitația valabilă după token (pentru pagina de setare a parolei)
+func (service *Service) FindInvitationByToken(token string) (*models.Invitation, error) {
+	invitation, err := service.repository.FindInvitationByTokenHash(hashToken(strings.TrimSpace(token)))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvitationInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch invitation.Status(time.Now()) {
+	case models.InvitationPending:
+		return invitation, nil
+	case models.InvitationExpired:
+		return nil, ErrInvitationExpired
+	}
+	return nil, ErrInvitationInvalid
+}
+
+// AcceptInvitation creează utilizatorul invitat cu parola aleasă de el, rolul și canalele din invitație.
+// Token-ul se poate folosi o singură dată.
+func (service *Service) AcceptInvitation(token, password string) (*models.User, error) {
+	invitation, err := service.FindInvitationByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := service.repository.FindUserByEmail(invitation.Email); err == nil {
+		return nil, ErrUserExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	user := &models.User{
+		Email:    invitation.Email,
+		Password: hash,
+		Role:     invitation.Role,
+		Channels: invitation.Channels,
+	}
+	audit := func(user *models.User) *models.AuditLog {
+		return newAuditLog(user.ID, AuditUserInviteAccept, "invitation", invitation.ID, map[string]string{"email": user.Email})
+	}
+	err = service.repository.AcceptInvitation(invitation, user, audit)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Token-ul a fost folosit sau invitația anulată între timp
+		return nil, ErrInvitationInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"io"
+	"orders/internal/models"
+	"orders/internal/spreadsheet"
+	"orders/internal/validation"
+	"strings"
+)
+
+// Coloanele fișierului clasificatorului CUATM: câmpul -> denumirile acceptate în antet.
+// Fișierul publicat de Biroul Național de Statistică are coloanele "Cod", "Denumirea" și "Statutul".
+var localityImportColumns = []struct {
+	field    string
+	names    []string
+	required bool
+}{
+	{"code", []string{"code", "cod", "codul", "cuatm", "cod cuatm"}, true},
+	{"name", []string{"name", "denumire", "denumirea", "nume"}, true},
+	{"kind", []string{"kind", "type", "tip", "statut", "statutul"}, false},
+}
+
+// LocalityImportResult - rezultatul importului clasificatorului
+type LocalityImportResult struct {
+	Imported int                `json:"imported"` // Localități create sau actualizate
+	Skipped  []models.ImportRow `json:"skipped"`  // Rândurile sărite, cu motivul
+}
+
+// ImportLocalities importă clasificatorul localităților (CUATM) dintr-un fișier CSV sau XLSX.
+// Localitățile existente (după cod) se actualizează; raionul se determină după primele două cifre ale codului.
+func (service *Service) ImportLocalities(fileName string, r io.Reader) (*LocalityImportResult, error) {
+	maxSize := service.cfg.MaxUploadMB << 20
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrEmptyFile
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrFileTooLarge
+	}
+
+	table, err := spreadsheet.Read(fileName, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(table) == 0 {
+		return nil, ErrEmptyFile
+	}
+
+	byName := make(map[string]int, len(table[0]))
+	for i, name := range table[0] {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, ok := byName[key]; !ok {
+			byName[key] = i
+		}
+	}
+	columns := make(map[string]int)
+	var missing []string
+	for _, col := range localityImportColumns {
+		for _, name := range col.names {
+			if i, ok := byName[name]; ok {
+				columns[col.field] = i
+				break
+			}
+		}
+		if _, ok := columns[col.field]; !ok && col.required {
+			missing = append(missing, col.field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &MissingColumnsError{Columns: missing}
+	}
+
+	result := &LocalityImportResult{Skipped: make([]models.ImportRow, 0)}
+	localities := make([]models.Locality, 0, len(table)-1)
+	names := make(map[string]string, len(table)-1)
+	for i := 1; i < len(table); i++ {
+		if isEmptyRow(table[i]) {
+			continue
+		}
+		value := func(field string) string {
+			col, ok := columns[field]
+			if !ok || col >= len(table[i]) {
+				return ""
+			}
+			return strings.TrimSpace(table[i][col])
+		}
+
+		row := models.ImportRow{Row: i + 1, Action: "skip", Key: value("code")}
+		code, ok := validation.NormalizeCUATMCode(value("code"))
+		switch {
+		case !ok:
+			row.Reason = "invalid_code"
+		case value("name") == "":
+			row.Reason = "missing_name"
+		case names[code] != "":
+			row.Reason = "duplicate_in_file"
+		}
+		if row.Reason != "" {
+			result.Skipped = append(result.Skipped, row)
+			continue
+		}
+
+		names[code] = value("name")
+		localities = append(localities, models.Locality{Code: code, Name: value("name"), Kind: value("kind")})
+	}
+
+	for i := range localities {
+		if district := validation.CUATMDistrictCode(localities[i].Code); district != localities[i].Code {
+			localities[i].District = names[district]
+		}
+	}
+	if err := service.repository.UpsertLocalities(localities); err != nil {
+		return nil, err
+	}
+	result.Imported = len(localities)
+	return result, nil
+}
+
+// SearchLocalities caută localitățile după denumire sau cod (limit implicit 20, maxim 100)
+func (service *Service) SearchLocalities(query string, limit int) ([]models.Locality, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return service.repository.SearchLocalities(strings.TrimSpace(query), limit)
+}
+
+func (service *Service) FindLocalityByCode(code string) (*models.Locality, error) {
+	code, ok := validation.NormalizeCUATMCode(code)
+	if !ok {
+		return nil, ErrLocalityNotFound
+	}
+	return service.repository.FindLocalityByCode(code)
+}
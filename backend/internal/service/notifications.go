@@ -0,0 +1,27 @@
+package service
+
+import (
+	"orders/internal/models"
+	"time"
+)
+
+// Tipurile notificărilor
+const (
+	NotificationContractExpiring = "contract.expiring"
+	NotificationContractExpired  = "contract.expired"
+)
+
+const maxNotificationLimit = 200
+
+// FindNotifications întoarce notificările utilizatorului, cele mai recente primele
+func (service *Service) FindNotifications(userID uint, unreadOnly bool, limit int) ([]models.Notification, error) {
+	if limit < 1 || limit > maxNotificationLimit {
+		limit = maxNotificationLimit
+	}
+	return service.repository.FindNotifications(userID, unreadOnly, limit)
+}
+
+// MarkNotificationRead marchează notificarea ca citită; notificările altor utilizatori nu se găsesc
+func (service *Service) MarkNotificationRead(userID, id uint) error {
+	return service.repository.MarkNotificationRead(userID, id, time.Now())
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"orders/internal/mailer"
+	"orders/internal/models"
+	"orders/internal/validation"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Erori pentru schimbarea și resetarea parolei
+var (
+	ErrCurrentPasswordInvalid = errors.New("current_password_invalid")
+	ErrResetTokenInvalid      = errors.New("reset_token_invalid")
+	ErrResetTokenExpired      = errors.New("reset_token_expired")
+)
+
+// ChangePassword schimbă parola utilizatorului autentificat după verificarea parolei actuale.
+// Celelalte sesiuni ale lui se închid; sesiunea curentă (sessionID) rămâne deschisă.
+func (service *Service) ChangePassword(userID, sessionID uint, currentPassword, newPassword string) error {
+	user, err := service.repository.FindUserByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return ErrCurrentPasswordInvalid
+	}
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	user.Password = hash
+	audit := newAuditLog(userID, AuditPasswordChange, "user", userID, nil)
+	return service.repository.UpdateUserPassword(user, sessionID, audit)
+}
+
+// Câte scrisori de resetare se pot pregăti și trimite în același timp; cererile peste limită se ignoră,
+// ca ruta publică /password/forgot să nu poată încărca serverul de mail
+const maxConcurrentResetMails = 4
+
+// RequestPasswordReset trimite pe email linkul de resetare a parolei. Cererea se procesează în fundal și
+// erorile doar se scriu în log: răspunsul și durata lui sunt aceleași pentru adresele cu și fără cont.
+func (service *Service) RequestPasswordReset(email, ip string) error {
+	email, ok := validation.NormalizeEmail(email)
+	if !ok || email == "" {
+		return ErrInvalidEmail
+	}
+	select {
+	case service.resetMails <- struct{}{}:
+	default:
+		log.Printf("⚠️  Password reset for %s dropped: too many pending requests", email)
+		return nil
+	}
+	go func() {
+		defer func() { <-service.resetMails }()
+		if err := service.sendPasswordReset(email, ip); err != nil {
+			log.Printf("❌ Password reset for %s failed: %v", email, err)
+		}
+	}()
+	return nil
+}
+
+// sendPasswordReset creează token-ul și trimite scrisoarea; pentru un email necunoscut nu face nimic.
+// Dacă utilizatorul a primit un link valabil în ultimele PASSWORD_RESET_COOLDOWN_MINUTES minute, nu se trimite altul.
+func (service *Service) sendPasswordReset(email, ip string) error {
+	user, err := service.repository.FindUserByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	cooldown := time.Duration(service.cfg.PasswordResetCooldownMinutes) * time.Minute
+	last, err := service.repository.FindLatestPasswordReset(user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil && last.UsedAt == nil && time.Now().Before(last.ExpiresAt) && time.Since(last.CreatedAt) < cooldown {
+		log.Printf("⚠️  Password reset for %s skipped: a link was sent %s ago", email, time.Since(last.CreatedAt).Round(time.Second))
+		return nil
+	}
+
+	token, hash, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+	ttl := time.Duration(service.cfg.PasswordResetTTLMinutes) * time.Minute
+	reset := &models.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(ttl),
+		IP:        truncate(ip, 45),
+	}
+	if err := service.repository.CreatePasswordReset(reset); err != nil {
+		return err
+	}
+	return service.mailer.Send(mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Resetarea parolei",
+		Body:    service.passwordResetBody(token, ttl),
+	})
+}
+
+// ResetPassword setează parola nouă după token-ul din scrisoare. Token-ul se poate folosi o singură dată;
+// toate sesiunile utilizatorului se închid.
+func (service *Service) ResetPassword(token, newPassword string) error {
+	reset, err := service.repository.FindPasswordResetByTokenHash(hashToken(strings.TrimSpace(token)))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrResetTokenInvalid
+	}
+	if err != nil {
+		return err
+	}
+	if reset.UsedAt != nil {
+		return ErrResetTokenInvalid
+	}
+	if !time.Now().Before(reset.ExpiresAt) {
+		return ErrResetTokenExpired
+	}
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	user, err := service.repository.FindUserByID(reset.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrResetTokenInvalid
+	}
+	if err != nil {
+		return err
+	}
+
+	user.Password = hash
+	audit := newAuditLog(user.ID, AuditPasswordReset, "user", user.ID, nil)
+	err = service.repository.ResetPassword(reset, user, audit)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Token-ul a fost folosit între timp de altă cerere
+		return ErrResetTokenInvalid
+	}
+	return err
+}
+
+// passwordResetBody - textul scrisorii; fără APP_URL se trimite doar token-ul
+func (service *Service) passwordResetBody(token string, ttl time.Duration) string {
+	var b strings.Builder
+	b.WriteString("Bună ziua,\n\n")
+	b.WriteString("Am primit o cerere de resetare a parolei pentru contul dumneavoastră.\n")
+	if service.cfg.AppURL != "" {
+		fmt.Fprintf(&b, "Pentru a seta o parolă nouă, deschideți linkul:\n\n%s/reset-password?token=%s\n\n", service.cfg.AppURL, token)
+	} else {
+		fmt.Fprintf(&b, "Codul pentru setarea parolei noi:\n\n%s\n\n", token)
+	}
+	fmt.Fprintf(&b, "Linkul este valabil %d minute și poate fi folosit o singură dată.\n", int(ttl.Minutes()))
+	b.WriteString("Dacă nu ați cerut resetarea parolei, ignorați această scrisoare; parola rămâne neschimbată.\n")
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"time"
+)
+
+// Erori pentru plăți
+var (
+	ErrInvalidPaymentDirection = errors.New("invalid_direction")
+	ErrInvalidPaymentAmount    = errors.New("invalid_amount")
+	ErrContractClientMismatch  = errors.New("contract_client_mismatch")
+	ErrIncomeTaxNotApplicable  = errors.New("income_tax_not_applicable")
+)
+
+// Tipul clientului pentru care se reține impozitul pe venit (persoanele fizice)
+const clientTypeIndividual = "individual"
+
+// CreatePayment înregistrează plata și, pentru plățile către persoane fizice, calculează impozitul reținut.
+// Impozitul se alege în ordinea: specificat explicit, din contract, din tipul clientului.
+func (service *Service) CreatePayment(userID uint, payment *models.Payment) error {
+	if payment.Direction != models.PaymentIncoming && payment.Direction != models.PaymentOutgoing {
+		return ErrInvalidPaymentDirection
+	}
+	if payment.Amount <= 0 {
+		return ErrInvalidPaymentAmount
+	}
+	if payment.Date.IsZero() {
+		now := time.Now()
+		payment.Date = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	client, err := service.repository.FindClientByID(payment.ClientID)
+	if err != nil {
+		return err
+	}
+
+	var contract *models.Contract
+	if payment.ContractID != nil {
+		contract, err = service.repository.FindContractByID(*payment.ContractID)
+		if err != nil {
+			return err
+		}
+		if contract.ClientID != client.ID {
+			return ErrContractClientMismatch
+		}
+	}
+
+	payment.IncomeTaxRate = 0
+	payment.WithheldAmount = 0
+	if payment.Direction == models.PaymentOutgoing {
+		if err := service.applyIncomeTax(payment, client, contract); err != nil {
+			return err
+		}
+	} else {
+		payment.IncomeTaxID = nil
+	}
+	payment.NetAmount = roundMoney(payment.Amount - payment.WithheldAmount)
+	payment.OwnerID = userID
+
+	return service.repository.CreatePayment(payment)
+}
+
+// applyIncomeTax determină impozitul pe venit și suma reținută pentru o plată către client
+func (service *Service) applyIncomeTax(payment *models.Payment, client *models.Client, contract *models.Contract) error {
+	clientType, err := service.repository.FindClientTypeByID(client.ClientTypeID)
+	if err != nil {
+		return err
+	}
+	// Impozitul se reține doar la plățile către persoane fizice; pentru ceilalți clienți
+	// income_tax_id explicit este o greșeală, iar impozitul din contract se ignoră
+	if clientType.Name != clientTypeIndividual {
+		if payment.IncomeTaxID != nil {
+			return ErrIncomeTaxNotApplicable
+		}
+		return nil
+	}
+
+	incomeTaxID := payment.IncomeTaxID
+	if incomeTaxID == nil && contract != nil {
+		incomeTaxID = contract.IncomeTaxID
+	}
+	if incomeTaxID == nil {
+		incomeTaxID = clientType.IncomeTaxID
+	}
+	if incomeTaxID == nil {
+		return nil
+	}
+
+	incomeTax, err := service.repository.FindIncomeTaxByID(*incomeTaxID)
+	if err != nil {
+		return err
+	}
+	payment.IncomeTaxID = &incomeTax.ID
+	payment.IncomeTaxRate = incomeTax.Rate
+	payment.WithheldAmount = roundMoney(payment.Amount * incomeTax.Rate / 100)
+	return nil
+}
+
+// FindPaymentByID întoarce plata, dacă clientul ei este vizibil utilizatorului
+func (service *Service) FindPaymentByID(id uint) (*models.Payment, error) {
+	payment, err := service.repository.FindPaymentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if service.channelRestricted() {
+		if _, err := service.repository.FindClientByID(payment.ClientID); err != nil {
+			return nil, err
+		}
+	}
+	return payment, nil
+}
+
+// IncomeTaxReport întoarce impozitul reținut pe lună și beneficiar, pentru declarațiile fiscale
+func (service *Service) IncomeTaxReport(from, to time.Time) ([]models.IncomeTaxSummary, error) {
+	return service.repository.IncomeTaxReport(from, to)
+}
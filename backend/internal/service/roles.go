@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Permisiunile (resursă:acțiune) verificate pe rute
+const (
+	PermClientsRead      = "clients:read"
+	PermClientsEdit      = "clients:edit"
+	PermClientsDelete    = "clients:delete"
+	PermClientsImport    = "clients:import"
+	PermClientsMerge     = "clients:merge"
+	PermContractsRead    = "contracts:read"
+	PermContractsEdit    = "contracts:edit"
+	PermContractsApprove = "contracts:approve"
+	PermTemplatesEdit    = "templates:edit"
+	PermPricesEdit       = "prices:edit"
+	PermOrdersRead       = "orders:read"
+	PermOrdersCreate     = "orders:create"
+	PermOrdersApprove    = "orders:approve"
+	PermProductsRead     = "products:read"
+	PermProductsEdit     = "products:edit"
+	PermPaymentsRead     = "payments:read"
+	PermPaymentsCreate   = "payments:create"
+	PermReportsRead      = "reports:read"
+	PermReferenceEdit    = "reference:edit" // Clasificatoare: cote TVA, cursuri valutare, localități
+	PermExchange1C       = "exchange:1c"
+	PermChannelsManage   = "channels:manage"
+	PermChannelsAll      = "channels:all" // Vede înregistrările din toate canalele
+	PermUsersManage      = "users:manage"
+	PermAuditRead        = "audit:read"
+	PermJobsRun          = "jobs:run"
+)
+
+// Toate permisiunile, în ordinea afișării
+var permissions = []string{
+	PermClientsRead, PermClientsEdit, PermClientsDelete, PermClientsImport, PermClientsMerge,
+	PermContractsRead, PermContractsEdit, PermContractsApprove, PermTemplatesEdit, PermPricesEdit,
+	PermOrdersRead, PermOrdersCreate, PermOrdersApprove, PermProductsRead, PermProductsEdit,
+	PermPaymentsRead, PermPaymentsCreate, PermReportsRead, PermReferenceEdit, PermExchange1C,
+	PermChannelsManage, PermChannelsAll, PermUsersManage, PermAuditRead, PermJobsRun,
+}
+
+// Matricea permisiunilor: rolul -> permisiunile lui (administratorul le are pe toate)
+var rolePermissions = map[string][]string{
+	models.RoleAdmin: permissions,
+	models.RoleManager: {
+		PermClientsRead, PermClientsEdit, PermClientsDelete, PermClientsImport, PermClientsMerge,
+		PermContractsRead, PermContractsEdit, PermContractsApprove, PermTemplatesEdit, PermPricesEdit,
+		PermOrdersRead, PermOrdersCreate, PermOrdersApprove, PermProductsRead, PermProductsEdit,
+		PermPaymentsRead, PermReportsRead,
+	},
+	models.RoleSalesRep: {
+		PermClientsRead, PermClientsEdit, PermClientsImport,
+		PermContractsRead, PermContractsEdit,
+		PermOrdersRead, PermOrdersCreate, PermProductsRead, PermPaymentsRead,
+	},
+	models.RoleWarehouse: {
+		PermClientsRead, PermContractsRead, PermOrdersRead, PermProductsRead, PermProductsEdit,
+	},
+	models.RoleAccountant: {
+		PermClientsRead, PermContractsRead, PermOrdersRead, PermProductsRead,
+		PermPaymentsRead, PermPaymentsCreate, PermReportsRead, PermReferenceEdit,
+	},
+	models.RoleReadOnly: {
+		PermClientsRead, PermContractsRead, PermOrdersRead, PermProductsRead, PermPaymentsRead, PermReportsRead,
+	},
+}
+
+// Rolurile, în ordinea afișării
+var roles = []string{
+	models.RoleAdmin, models.RoleManager, models.RoleSalesRep,
+	models.RoleWarehouse, models.RoleAccountant, models.RoleReadOnly,
+}
+
+// Erori pentru roluri
+var (
+	ErrInvalidRole = errors.New("invalid_role")
+	ErrLastAdmin   = errors.New("last_admin")
+)
+
+// RolePermissions - rolul cu permisiunile lui
+type RolePermissions struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+// HasPermission verifică dacă rolul are permisiunea dată (rolurile necunoscute nu au nicio permisiune)
+func HasPermission(role, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidRole verifică dacă rolul există în matricea permisiunilor
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// FindRoles întoarce matricea permisiunilor
+func (service *Service) FindRoles() []RolePermissions {
+	result := make([]RolePermissions, 0, len(roles))
+	for _, role := range roles {
+		result = append(result, RolePermissions{Role: role, Permissions: rolePermissions[role]})
+	}
+	return result
+}
+
+// UserRole întoarce rolul actual al utilizatorului (rolul din token poate fi depășit după o schimbare de rol)
+func (service *Service) UserRole(userID uint) (string, error) {
+	user, err := service.repository.FindUserByID(userID)
+	if err != nil {
+		return "", err
+	}
+	return user.Role, nil
+}
+
+// FindUsers întoarce utilizatorii cu canalele lor (role = "" - toți)
+func (service *Service) FindUsers(role string) ([]models.User, error) {
+	return service.repository.FindUsers(role)
+}
+
+// AssignUserRole schimbă rolul utilizatorului; ultimul administrator nu-și poate pierde rolul.
+// Schimbarea se înregistrează în jurnalul de audit.
+func (service *Service) AssignUserRole(adminID, userID uint, role string) (*models.User, error) {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if !IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	user, err := service.repository.FindUserByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+	if user.Role == models.RoleAdmin {
+		admins, err := service.repository.CountUsersByRole(models.RoleAdmin)
+		if err != nil {
+			return nil, err
+		}
+		if admins <= 1 {
+			return nil, ErrLastAdmin
+		}
+	}
+
+	audit := newAuditLog(adminID, AuditUserRole, "user", user.ID, map[string]string{"from": user.Role, "to": role})
+	user.Role = role
+	if err := service.repository.UpdateUserRole(user, audit); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
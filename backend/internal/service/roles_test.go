@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"testing"
+)
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		role       string
+		permission string
+		want       bool
+	}{
+		{models.RoleAdmin, PermUsersManage, true},
+		{models.RoleAdmin, PermChannelsAll, true},
+		{models.RoleManager, PermClientsMerge, true},
+		{models.RoleManager, PermChannelsAll, false},
+		{models.RoleManager, PermUsersManage, false},
+		{models.RoleSalesRep, PermOrdersCreate, true},
+		{models.RoleSalesRep, PermOrdersApprove, false},
+		{models.RoleSalesRep, PermReportsRead, false},
+		{models.RoleWarehouse, PermProductsEdit, true},
+		{models.RoleWarehouse, PermPaymentsRead, false},
+		{models.RoleAccountant, PermPaymentsCreate, true},
+		{models.RoleAccountant, PermReportsRead, true},
+		{models.RoleAccountant, PermClientsEdit, false},
+		{models.RoleReadOnly, PermReportsRead, true},
+		{models.RoleReadOnly, PermOrdersCreate, false},
+		{"", PermClientsRead, false},
+		{"superuser", PermClientsRead, false},
+		{models.RoleAdmin, "clients:unknown", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role+"/"+tt.permission, func(t *testing.T) {
+			if got := HasPermission(tt.role, tt.permission); got != tt.want {
+				t.Errorf("HasPermission(%q, %q) = %v, want %v", tt.role, tt.permission, got, tt.want)
+			}
+		})
+	}
+}
+
+// Administratorul are toate permisiunile, iar fiecare rol are doar permisiuni cunoscute
+func TestRolePermissionsKnown(t *testing.T) {
+	for _, p := range permissions {
+		if !HasPermission(models.RoleAdmin, p) {
+			t.Errorf("admin lacks %q", p)
+		}
+	}
+	known := map[string]bool{}
+	for _, p := range permissions {
+		known[p] = true
+	}
+	for _, role := range roles {
+		for _, p := range rolePermissions[role] {
+			if !known[p] {
+				t.Errorf("role %q has unknown permission %q", role, p)
+			}
+		}
+	}
+}
+
+func TestAssignUserRole(t *testing.T) {
+	newRoleTestService := func(roles ...string) (*Service, *fakeRepository) {
+		repo := newFakeRepository()
+		for i, role := range roles {
+			id := uint(i + 1)
+			repo.users[id] = &models.User{Role: role}
+			repo.users[id].ID = id
+		}
+		return newTestService(repo), repo
+	}
+
+	service, repo := newRoleTestService(models.RoleAdmin, models.RoleSalesRep)
+	user, err := service.AssignUserRole(1, 2, " Accountant ")
+	if err != nil {
+		t.Fatalf("AssignUserRole: %v", err)
+	}
+	if user.Role != models.RoleAccountant || repo.users[2].Role != models.RoleAccountant {
+		t.Errorf("role = %q, want %q", repo.users[2].Role, models.RoleAccountant)
+	}
+	if len(repo.audits) != 1 || repo.audits[0].Action != AuditUserRole {
+		t.Errorf("audit logs = %+v", repo.audits)
+	}
+
+	tests := []struct {
+		name    string
+		roles   []string
+		userID  uint
+		role    string
+		wantErr error
+	}{
+		{"unknown role", []string{models.RoleAdmin, models.RoleSalesRep}, 2, "superuser", ErrInvalidRole},
+		{"unknown user", []string{models.RoleAdmin}, 9, models.RoleManager, ErrUserNotFound},
+		{"last admin", []string{models.RoleAdmin, models.RoleManager}, 1, models.RoleManager, ErrLastAdmin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := newRoleTestService(tt.roles...)
+			if _, err := service.AssignUserRole(1, tt.userID, tt.role); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.audits) != 0 {
+				t.Errorf("audit logs = %+v", repo.audits)
+			}
+		})
+	}
+
+	// Cu doi administratori, unul poate primi alt rol
+	service, _ = newRoleTestService(models.RoleAdmin, models.RoleAdmin)
+	if _, err := service.AssignUserRole(1, 2, models.RoleManager); err != nil {
+		t.Errorf("AssignUserRole with two admins: %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"errors"
+	"html"
+	"orders/internal/models"
+	"strings"
+	"unicode"
+)
+
+// ErrSearchQueryTooShort - textul căutat are mai puțin de minSearchQueryLength caractere
+var ErrSearchQueryTooShort = errors.New("query_too_short")
+
+const (
+	minSearchQueryLength = 2
+	maxSearchPageSize    = 100
+	// Pragul de similaritate (trigrame) de la care un cuvânt cu greșeli de tipar se marchează ca potrivit
+	highlightSimilarity = 0.4
+)
+
+// Diacriticele românești și rusești care se ignoră la căutare (ca unaccent din Postgres)
+var searchFolder = strings.NewReplacer(
+	"ă", "a", "â", "a", "î", "i", "ș", "s", "ş", "s", "ț", "t", "ţ", "t",
+	"é", "e", "è", "e", "ë", "e", "ü", "u", "ö", "o", "ä", "a", "ç", "c", "ё", "е", "й", "и",
+)
+
+// normalizeSearchText aduce textul la forma folosită în indexuri: litere mici, fără diacritice
+func normalizeSearchText(s string) string {
+	return searchFolder.Replace(strings.ToLower(strings.TrimSpace(s)))
+}
+
+// phoneDigits întoarce cifrele textului căutat, dacă acesta arată ca un număr de telefon
+func phoneDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' || r == ' ' || r == '-' || r == '(' || r == ')':
+		default:
+			return ""
+		}
+	}
+	if b.Len() < 3 {
+		return ""
+	}
+	// Telefoanele se păstrează în E.164 (+37369123456): prefixul internațional 00 și 0 de la începutul
+	// numărului național nu apar în ele
+	digits := b.String()
+	if strings.HasPrefix(digits, "00") {
+		return digits[2:]
+	}
+	return strings.TrimPrefix(digits, "0")
+}
+
+// SearchClients caută clienții după nume, cod fiscal, email, telefon, adresă și persoanele de contact,
+// tolerând greșelile de tipar și lipsa diacriticelor. Rezultatele sunt ordonate după relevanță și paginate.
+func (service *Service) SearchClients(query string, page, pageSize int) ([]models.ClientSearchHit, int64, error) {
+	term := normalizeSearchText(query)
+	if len([]rune(term)) < minSearchQueryLength {
+		return nil, 0, ErrSearchQueryTooShort
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > maxSearchPageSize {
+		pageSize = 20
+	}
+
+	digits := phoneDigits(query)
+	hits, total, err := service.repository.SearchClients(term, digits, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range hits {
+		hits[i].Highlights = clientHighlights(&hits[i].Client, term, digits)
+	}
+	return hits, total, nil
+}
+
+// clientHighlights marchează fragmentele potrivite în câmpurile clientului și ale persoanelor de contact
+func clientHighlights(client *models.Client, term, digits string) []models.SearchHighlight {
+	highlights := make([]models.SearchHighlight, 0)
+	add := func(field, marked string, ok bool) {
+		if ok {
+			highlights = append(highlights, models.SearchHighlight{Field: field, Value: marked})
+		}
+	}
+
+	terms := strings.Fields(term)
+	value, ok := highlightText(client.Name, terms)
+	add("name", value, ok)
+	value, ok = highlightText(client.FiscalID, []string{term})
+	add("fiscal_id", value, ok)
+	if client.Email != nil {
+		value, ok = highlightText(*client.Email, []string{term})
+		add("email", value, ok)
+	}
+	value, ok = highlightDigits(client.Phone, digits)
+	add("phone", value, ok)
+	value, ok = highlightText(client.Address, terms)
+	add("address", value, ok)
+	for _, contact := range client.Contacts {
+		value, ok = highlightText(contact.Name, terms)
+		add("contact.name", value, ok)
+		value, ok = highlightDigits(contact.Phone, digits)
+		add("contact.phone", value, ok)
+	}
+	return highlights
+}
+
+// highlightText încadrează în <mark> cuvintele din text care conțin un termen căutat
+// sau îi seamănă (greșeli de tipar). Textul întors este HTML-escaped.
+func highlightText(text string, terms []string) (string, bool) {
+	if text == "" || len(terms) == 0 {
+		return "", false
+	}
+
+	var b strings.Builder
+	found := false
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		// Separatorii (spații, punctuație) se copiază așa cum sunt
+		if !isWordRune(runes[start]) {
+			end := start
+			for end < len(runes) && !isWordRune(runes[end]) {
+				end++
+			}
+			b.WriteString(html.EscapeString(string(runes[start:end])))
+			start = end
+			continue
+		}
+
+		end := start
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		word := string(runes[start:end])
+		if wordMatches(normalizeSearchText(word), terms) {
+			found = true
+			b.WriteString("<mark>" + html.EscapeString(word) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(word))
+		}
+		start = end
+	}
+	return b.String(), found
+}
+
+// highlightDigits marchează numărul de telefon dacă cifrele lui conțin cifrele căutate
+func highlightDigits(phone, digits string) (string, bool) {
+	if phone == "" || digits == "" {
+		return "", false
+	}
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	if !strings.Contains(b.String(), digits) {
+		return "", false
+	}
+	return "<mark>" + html.EscapeString(phone) + "</mark>", true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func wordMatches(word string, terms []string) bool {
+	for _, term := range terms {
+		if strings.Contains(word, term) || (len([]rune(term)) >= 3 && trigramSimilarity(word, term) >= highlightSimilarity) {
+			return true
+		}
+	}
+	return false
+}
+
+// trigramSimilarity calculează similaritatea trigramelor, la fel ca funcția similarity() din pg_trgm
+func trigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	common := 0
+	for t := range ta {
+		if tb[t] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(ta)+len(tb)-common)
+}
+
+// trigrams întoarce trigramele cuvântului, completat cu două spații la început și unul la sfârșit (ca pg_trgm)
+func trigrams(word string) map[string]bool {
+	runes := []rune("  " + word + " ")
+	res := make(map[string]bool, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		res[string(runes[i:i+3])] = true
+	}
+	return res
+}
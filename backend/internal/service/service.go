@@ -1,106 +1,323 @@
 package service
 
 import (
-	"fmt"
+	"errors"
+	"math"
 	"orders/internal/config"
+	"orders/internal/documents"
+	"orders/internal/mailer"
 	"orders/internal/models"
-	"strings"
+	"orders/internal/storage"
+	"orders/internal/validation"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type Repository interface {
+	// Repository-ul restrâns la canalele de vânzări ale utilizatorului
+	WithChannelScope(scope models.ChannelScope) Repository
+	// Repository-ul care marchează înregistrările de audit cu cheia API a cererii
+	WithAPIKey(keyID uint) Repository
 
 	// Authentication methods
 	// User methods
 	CreateUser(user *models.User) error
 	FindUserByEmail(email string) (*models.User, error)
+	FindUserByID(id uint) (*models.User, error)
+	FindUserChannelIDs(userID uint) ([]uint, error)
+	FindUsers(role string) ([]models.User, error)
+	CountUsersByRole(role string) (int64, error)
+	UpdateUserRole(user *models.User, audit *models.AuditLog) error
+
+	// Invitation methods
+	CreateInvitation(invitation *models.Invitation, audit func(invitation *models.Invitation) *models.AuditLog) error
+	FindInvitations() ([]models.Invitation, error)
+	FindInvitationByID(id uint) (*models.Invitation, error)
+	FindInvitationByTokenHash(hash string) (*models.Invitation, error)
+	RevokeInvitation(invitation *models.Invitation, audit *models.AuditLog) error
+	AcceptInvitation(invitation *models.Invitation, user *models.User, audit func(user *models.User) *models.AuditLog) error
+
+	// Session methods
+	CreateSession(session *models.Session) error
+	FindSessionByID(id uint) (*models.Session, error)
+	FindSessionByTokenHash(hash string) (*models.Session, error)
+	FindActiveSessions(userID uint, now time.Time) ([]models.Session, error)
+	RotateSession(session *models.Session, previousHash string) error
+	RevokeSession(id uint) error
+	RevokeUserSessions(userID uint, audit func(revoked int64) *models.AuditLog) (int64, error)
+	DeleteExpiredSessions(now time.Time) (int64, error)
+
+	// Password methods
+	UpdateUserPassword(user *models.User, keepSessionID uint, audit *models.AuditLog) error
+	CreatePasswordReset(reset *models.PasswordReset) error
+	FindLatestPasswordReset(userID uint) (*models.PasswordReset, error)
+	FindPasswordResetByTokenHash(hash string) (*models.PasswordReset, error)
+	ResetPassword(reset *models.PasswordReset, user *models.User, audit *models.AuditLog) error
+
+	// API key methods
+	CreateAPIKey(key *models.APIKey, audit func(key *models.APIKey) *models.AuditLog) error
+	FindAPIKeys() ([]models.APIKey, error)
+	FindAPIKeyByID(id uint) (*models.APIKey, error)
+	FindAPIKeyByHash(hash string) (*models.APIKey, error)
+	RevokeAPIKey(key *models.APIKey, audit *models.AuditLog) error
+	TouchAPIKey(id uint, usedAt time.Time, ip string) error
+
+	// Channel methods
+	CreateChannel(channel *models.Channel) error
+	FindChannelByID(id uint) (*models.Channel, error)
+	FindChannelByName(name string) (*models.Channel, error)
+	FindChannels(ids []uint) ([]models.Channel, error)
+	UpdateChannel(channel *models.Channel) error
+	DeleteChannel(id uint) error
+	CountChannelRecords(id uint) (int64, error)
+	FindChannelUsers(channelID uint) ([]models.User, error)
+	AddChannelUsers(channelID uint, userIDs []uint) error
+	RemoveChannelUser(channelID, userID uint) error
 
 	// Client methods
 	CreateClient(client *models.Client) error
-	GetFirst1000Clients() ([]models.Client, error)
-	FindClientsByQuery(query string) ([]models.Client, error)
+	CountClients(filter models.ClientListFilter) (int64, error)
+	StreamClients(filter models.ClientListFilter, fn func(client *models.Client) error) error
+	SearchClients(term, digits string, limit, offset int) ([]models.ClientSearchHit, int64, error)
 	FindClientByID(id uint) (*models.Client, error)
 	FindClientByFiscalID(fiscalID string) (*models.Client, error)
+	FindClientByEmail(email string) (*models.Client, error)
+	UpdateClient(client *models.Client) error
+	MoveClient(client *models.Client) error
+	DeleteClient(id uint) error
+	FindClientsInBatches(batchSize int, fn func(clients []models.Client) error) error
+	CountActiveContractsByClient(clientID uint) (int64, error)
+	CountOpenOrdersByClient(clientID uint) (int64, error)
+
+	FindAllClientTypes() ([]models.ClientType, error)
+
+	// Import methods
+	CreateImport(imp *models.Import) error
+	FindImportByID(id uint) (*models.Import, error)
+
+	// Exchange (1C) methods
+	FindProductGroupByUUID(uuid string) (*models.ProductGroup, error)
+	FindProductGroupByName(name string) (*models.ProductGroup, error)
+	SaveProductGroup(group *models.ProductGroup) error
+	FindPriceTypeByUUID(uuid string) (*models.PriceType, error)
+	FindPriceTypeByName(name string) (*models.PriceType, error)
+	SavePriceType(priceType *models.PriceType) error
+	FindProductByUUID(uuid string) (*models.Product, error)
+	SaveProduct(product *models.Product) error
+	SavePriceProduct(price *models.PriceProduct) error
+	FindUnitByName(name string) (*models.Unit, error)
+	CreateUnit(unit *models.Unit) error
+	FindClientByUUID(uuid string) (*models.Client, error)
+	FindOrdersChangedSince(since *time.Time) ([]models.Order, error)
+	CreateExchangeLog(log *models.ExchangeLog) error
+	FindLastExchangeLog(system, direction, kind string) (*models.ExchangeLog, error)
+	FindExchangeLogs(system string, limit int) ([]models.ExchangeLog, error)
+
+	// Duplicate methods
+	FindDuplicatePairs(minNameSimilarity float64) ([]models.DuplicateCandidate, error)
+	FindClientsByIDs(ids []uint) ([]models.Client, error)
+	UpsertDuplicateCandidates(candidates []models.DuplicateCandidate) error
+	DeleteStaleDuplicateCandidates(detectedBefore time.Time) error
+	FindDuplicateCandidates(status string, minScore float64, limit int) ([]models.DuplicateCandidate, error)
+	FindDuplicateCandidateByID(id uint) (*models.DuplicateCandidate, error)
+	UpdateDuplicateCandidateStatus(id uint, status string) error
+	MergeClients(merge *models.ClientMerge, audit func(merge *models.ClientMerge) *models.AuditLog) error
+	UndoClientMerge(merge *models.ClientMerge, audit *models.AuditLog) error
+	ClientIdentityTaken(fiscalID string, email *string, exceptID uint) (bool, error)
+	FindClientMergeByID(id uint) (*models.ClientMerge, error)
+	FindClientMerges(clientID uint) ([]models.ClientMerge, error)
+
+	// Audit methods
+	CreateAuditLog(log *models.AuditLog) error
+	FindAuditLogs(entityType string, entityID uint, limit int) ([]models.AuditLog, error)
+
+	// Notification methods
+	CreateNotification(notification *models.Notification) error
+	FindNotifications(userID uint, unreadOnly bool, limit int) ([]models.Notification, error)
+	MarkNotificationRead(userID, id uint, at time.Time) error
+
+	// ClientContact methods
+	CreateClientContact(contact *models.ClientContact) error
+	FindClientContactByID(id uint) (*models.ClientContact, error)
+	UpdateClientContact(contact *models.ClientContact) error
+	DeleteClientContact(id uint) error
+
+	// ClientBankAccount methods
+	CreateClientBankAccount(account *models.ClientBankAccount) error
+	FindClientBankAccountByID(id uint) (*models.ClientBankAccount, error)
+	FindClientBankAccountsByIBAN(iban string) ([]models.ClientBankAccount, error)
+	UpdateClientBankAccount(account *models.ClientBankAccount) error
+	DeleteClientBankAccount(account *models.ClientBankAccount) error
 
 	// Contract methods
 	CreateContract(contract *models.Contract) error
 	FindContractByID(id uint) (*models.Contract, error)
+	UpdateContract(contract *models.Contract, version *models.ContractAmendment, audit *models.AuditLog) error
+	CountContracts(filter models.ContractListFilter) (int64, error)
+	FindContracts(filter models.ContractListFilter) ([]models.Contract, error)
+	CreateContractAmendment(contract *models.Contract, amendment *models.ContractAmendment, audit *models.AuditLog) error
+	FindContractAmendments(contractID uint) ([]models.ContractAmendment, error)
+	FindContractAmendment(contractID uint, version int) (*models.ContractAmendment, error)
+	FindContractVersionAt(contractID uint, date time.Time) (*models.ContractAmendment, error)
+
+	// ContractTemplate methods
+	CreateContractTemplate(template *models.ContractTemplate) error
+	FindContractTemplateByID(id uint) (*models.ContractTemplate, error)
+	FindContractTemplates(activeOnly bool) ([]models.ContractTemplate, error)
+	UpdateContractTemplate(template *models.ContractTemplate, version *models.ContractTemplateVersion) error
+	FindContractTemplateVersions(templateID uint) ([]models.ContractTemplateVersion, error)
+	FindContractTemplateVersion(templateID uint, version int) (*models.ContractTemplateVersion, error)
+	CreateContractDocument(document *models.ContractDocument) error
+	FindContractDocuments(contractID uint) ([]models.ContractDocument, error)
+	FindContractsToExpire(today time.Time) ([]models.Contract, error)
+	FindContractsExpiringBy(until time.Time) ([]models.Contract, error)
+	MarkContractExpiryNotified(contract *models.Contract, notification *models.Notification) error
 	CreateContractAddress(addr *models.ContractAddress) error
 	FindContractAddressByID(id uint) (*models.ContractAddress, error)
+	FindContractAddresses(contractID uint) ([]models.ContractAddress, error)
+	UpdateContractAddress(addr *models.ContractAddress) error
+	DeleteContractAddress(id uint) error
+
+	// Locality methods
+	UpsertLocalities(localities []models.Locality) error
+	FindLocalityByCode(code string) (*models.Locality, error)
+	SearchLocalities(query string, limit int) ([]models.Locality, error)
 
 	// Product methods
 	CreateProduct(product *models.Product) error
 	FindProductByID(id uint) (*models.Product, error)
+	FindProductBySKU(sku string) (*models.Product, error)
 	FindProductGroupByID(id uint) (*models.ProductGroup, error)
 	FindVatTaxByID(id uint) (*models.VatTax, error)
 	FindUnitByID(id uint) (*models.Unit, error)
+	FindPriceProduct(productID, priceTypeID uint) (*models.PriceProduct, error)
+
+	// ContractPrice methods
+	CreateContractPrice(price *models.ContractPrice) error
+	FindContractPriceByID(id uint) (*models.ContractPrice, error)
+	UpdateContractPrice(price *models.ContractPrice) error
+	DeleteContractPrice(id uint) error
+	FindContractPrices(contractID uint, date *time.Time) ([]models.ContractPrice, error)
+	CountOverlappingContractPrices(price *models.ContractPrice) (int64, error)
+	FindContractPriceAt(contractID, productID uint, date time.Time) (*models.ContractPrice, error)
+	FindContractPricesValidAt(contractID, clientID uint, date time.Time) ([]models.ContractPrice, error)
+
+	// VAT methods
+	FindAllVatTaxes() ([]models.VatTax, error)
+	FindVatTaxRateAt(vatTaxID uint, date time.Time) (*models.VatTaxRate, error)
+	CreateVatTaxRate(rate *models.VatTaxRate) error
+
+	// Barcode methods
+	CreateProductBarcode(barcode *models.ProductBarcode) error
+	FindProductBarcode(code string) (*models.ProductBarcode, error)
 
 	// Document methods
 	// Order methods
 	CreateOrder(order *models.Order) error
+	UpdateOrderStatus(order *models.Order, audit *models.AuditLog) error
 	FindOrdersByUserID(userID uint) ([]models.Order, error)
 	FindOrderByID(id uint) (*models.Order, error)
+	FindOrderDocument(id uint) (*models.Order, error)
+
+	// Payment methods
+	CreatePayment(payment *models.Payment) error
+	FindPaymentByID(id uint) (*models.Payment, error)
+	FindIncomeTaxByID(id uint) (*models.IncomeTax, error)
+	FindClientTypeByID(id uint) (*models.ClientType, error)
+
+	// Exchange rate methods
+	UpsertExchangeRates(rates []models.ExchangeRate) error
+	FindExchangeRateAt(currency string, date time.Time) (*models.ExchangeRate, error)
+	FindExchangeRatesByDate(date time.Time) ([]models.ExchangeRate, error)
+
+	// Report methods
+	VatReport(from, to time.Time) ([]models.VatSummary, error)
+	IncomeTaxReport(from, to time.Time) ([]models.IncomeTaxSummary, error)
+
+	// Attachment methods
+	CreateAttachment(attachment *models.Attachment) error
+	FindAttachmentByID(id uint) (*models.Attachment, error)
+	FindAttachmentsByOwner(ownerType string, ownerID uint) ([]models.Attachment, error)
+	DeleteAttachment(id uint) error
+}
+
+// Erori de validare
+var (
+	ErrInvalidBarcode = errors.New("invalid_barcode")
+	ErrInvalidVatRate = errors.New("invalid_vat_rate")
+)
+
+// Erori pentru clienți
+var (
+	ErrClientHasActiveContracts = errors.New("client_has_active_contracts")
+	ErrClientHasOpenOrders      = errors.New("client_has_open_orders")
+	ErrInvalidContactChannel    = errors.New("invalid_preferred_channel")
+)
+
+// Erori pentru comenzi
+var ErrOrderNotPending = errors.New("order_not_pending")
+
+// BarcodeLookup - rezultatul căutării după codul de bare
+type BarcodeLookup struct {
+	Barcode string         `json:"barcode"`
+	Product models.Product `json:"product"`
+	Unit    models.Unit    `json:"unit"`
+	Price   float64        `json:"price"` // Prețul pentru unitatea de ambalare scanată
 }
 
 type Service struct {
 	repository Repository
 	jwtSecret  string
-	cfg        *config.Config // Добавляем конфигурацию
+	cfg        *config.Config       // Добавляем конфигурацию
+	storage    storage.Storage      // Stocarea fișierelor atașate
+	mailer     mailer.Mailer        // Trimiterea scrisorilor
+	scope      *models.ChannelScope // Canalele utilizatorului (nil - fără restricții, ex. sarcinile de fundal)
+	resetMails chan struct{}        // Locurile pentru scrisorile de resetare trimise în paralel
 }
 
-func NewService(repository Repository, jwtSecret string) *Service {
+func NewService(repository Repository, jwtSecret string, store storage.Storage, mail mailer.Mailer) *Service {
 	cfg := config.Load()
-	return &Service{repository: repository, jwtSecret: jwtSecret, cfg: &cfg}
+	return &Service{repository: repository, jwtSecret: jwtSecret, cfg: &cfg, storage: store, mailer: mail,
+		resetMails: make(chan struct{}, maxConcurrentResetMails)}
 }
 
-// Authentication methods
-func (service *Service) Signup(email, password, role string) error {
-	if !service.cfg.Allowsignup {
-		return fmt.Errorf("user registration is disabled")
-	}
-
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return err
-	}
-	user := &models.User{
-		Email:    email,
-		Password: string(hashedPassword),
-		Role:     map[bool]string{true: "admin", false: "user"}[strings.ToLower(role) == "trueadmin"],
-	}
-	return service.repository.CreateUser(user)
+// MaxUploadBytes - dimensiunea maximă a corpului cererilor cu fișiere (MAX_UPLOAD_MB)
+func (service *Service) MaxUploadBytes() int64 {
+	return service.cfg.MaxUploadMB << 20
 }
 
-func (service *Service) Login(email, password string) (string, error) {
-	user, err := service.repository.FindUserByEmail(email)
-	if err != nil {
-		return "", err
-	}
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", err
+// sameChannel compară canalele (nil - fără canal)
+func sameChannel(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": user.ID,
-		"role":    user.Role,
-		"exp":     time.Now().Add(time.Hour * 24 * 7).Unix(),
-	})
-	return token.SignedString([]byte(service.jwtSecret))
+	return *a == *b
 }
 
 // Clients methods
+// CreateClient verifică codul fiscal (IDNO / IDNP după tipul clientului), telefonul și emailul și salvează clientul
 func (service *Service) CreateClient(client *models.Client) error {
+	if err := service.prepareClient(client); err != nil {
+		return err
+	}
 	return service.repository.CreateClient(client)
 }
 
-func (service *Service) GetFirst1000Clients() ([]models.Client, error) {
-	return service.repository.GetFirst1000Clients()
-}
-
-func (service *Service) FindClientsByQuery(query string) ([]models.Client, error) {
-	return service.repository.FindClientsByQuery(query)
+// prepareClient face verificările clientului nou fără să-l salveze (le folosește și importul de probă)
+func (service *Service) prepareClient(client *models.Client) error {
+	if err := service.checkClientFiscalID(client); err != nil {
+		return err
+	}
+	if err := service.normalizeClientContacts(client, nil); err != nil {
+		return err
+	}
+	channelID, err := service.resolveChannel(client.ChannelID)
+	if err != nil {
+		return err
+	}
+	client.ChannelID = channelID
+	return nil
 }
 
 func (service *Service) FindClientByID(id uint) (*models.Client, error) {
@@ -111,22 +328,114 @@ func (service *Service) FindClientByFiscalID(fiscalID string) (*models.Client, e
 	return service.repository.FindClientByFiscalID(fiscalID)
 }
 
-// Contract methods
-func (service *Service) CreateContract(contract *models.Contract) error {
-	return service.repository.CreateContract(contract)
+// UpdateClient salvează modificările clientului.
+// Codul fiscal se verifică doar dacă s-a schimbat el sau tipul clientului, ca clienții vechi cu coduri
+// greșite să poată fi editați în continuare (ei apar în raportul InvalidFiscalIDReport); la fel telefonul și emailul.
+// Mutarea clientului în alt canal mută și contractele și comenzile lui.
+func (service *Service) UpdateClient(client *models.Client) error {
+	stored, err := service.repository.FindClientByID(client.ID)
+	if err != nil {
+		return err
+	}
+	moved, err := service.prepareClientUpdate(client, stored)
+	if err != nil {
+		return err
+	}
+	if moved {
+		return service.repository.MoveClient(client)
+	}
+	return service.repository.UpdateClient(client)
 }
 
-func (service *Service) FindContractByID(id uint) (*models.Contract, error) {
-	return service.repository.FindContractByID(id)
+// prepareClientUpdate face verificările UpdateClient fără salvare; moved - clientul trece în alt canal
+func (service *Service) prepareClientUpdate(client, stored *models.Client) (moved bool, err error) {
+	if stored.FiscalID != client.FiscalID || stored.ClientTypeID != client.ClientTypeID {
+		if err := service.checkClientFiscalID(client); err != nil {
+			return false, err
+		}
+	}
+	if err := service.normalizeClientContacts(client, stored); err != nil {
+		return false, err
+	}
+	if sameChannel(stored.ChannelID, client.ChannelID) {
+		return false, nil
+	}
+	channelID, err := service.resolveChannel(client.ChannelID)
+	if err != nil {
+		return false, err
+	}
+	client.ChannelID = channelID
+	return true, nil
+}
+
+// DeleteClient șterge (soft) clientul, dacă nu are contracte active sau comenzi deschise
+func (service *Service) DeleteClient(id uint) error {
+	if _, err := service.repository.FindClientByID(id); err != nil {
+		return err
+	}
+
+	contracts, err := service.repository.CountActiveContractsByClient(id)
+	if err != nil {
+		return err
+	}
+	if contracts > 0 {
+		return ErrClientHasActiveContracts
+	}
+
+	orders, err := service.repository.CountOpenOrdersByClient(id)
+	if err != nil {
+		return err
+	}
+	if orders > 0 {
+		return ErrClientHasOpenOrders
+	}
+
+	return service.repository.DeleteClient(id)
+}
+
+// ClientContact methods
+func (service *Service) CreateClientContact(contact *models.ClientContact) error {
+	if err := validateContactChannel(contact.PreferredChannel); err != nil {
+		return err
+	}
+	if err := normalizeContactFields(contact, nil); err != nil {
+		return err
+	}
+	return service.repository.CreateClientContact(contact)
+}
+
+func (service *Service) FindClientContactByID(id uint) (*models.ClientContact, error) {
+	return service.repository.FindClientContactByID(id)
+}
+
+func (service *Service) UpdateClientContact(contact *models.ClientContact) error {
+	if err := validateContactChannel(contact.PreferredChannel); err != nil {
+		return err
+	}
+	stored, err := service.repository.FindClientContactByID(contact.ID)
+	if err != nil {
+		return err
+	}
+	if err := normalizeContactFields(contact, stored); err != nil {
+		return err
+	}
+	return service.repository.UpdateClientContact(contact)
 }
 
-// ContractAddress methods
-func (service *Service) CreateContractAddress(addr *models.ContractAddress) error {
-	return service.repository.CreateContractAddress(addr)
+func (service *Service) DeleteClientContact(id uint) error {
+	return service.repository.DeleteClientContact(id)
 }
 
-func (service *Service) FindContractAddressByID(id uint) (*models.ContractAddress, error) {
-	return service.repository.FindContractAddressByID(id)
+func validateContactChannel(channel string) error {
+	if channel == "" {
+		return nil
+	}
+	for _, c := range models.ContactChannels {
+		if c == channel {
+			return nil
+		}
+	}
+	return ErrInvalidContactChannel
 }
 
 // Product methods
@@ -150,23 +459,187 @@ func (service *Service) FindUnitByID(id uint) (*models.Unit, error) {
 	return service.repository.FindUnitByID(id)
 }
 
+func (service *Service) FindProductBySKU(sku string) (*models.Product, error) {
+	return service.repository.FindProductBySKU(sku)
+}
+
+// Barcode methods
+func (service *Service) CreateProductBarcode(barcode *models.ProductBarcode) error {
+	barcode.Barcode = validation.NormalizeBarcode(barcode.Barcode)
+	if !validation.IsValidEAN(barcode.Barcode) {
+		return ErrInvalidBarcode
+	}
+	return service.repository.CreateProductBarcode(barcode)
+}
+
+func (service *Service) FindProductBarcode(code string) (*models.ProductBarcode, error) {
+	return service.repository.FindProductBarcode(validation.NormalizeBarcode(code))
+}
+
+// FindProductByBarcode întoarce produsul, unitatea de ambalare și prețul pentru unitatea scanată.
+// Dacă priceTypeID este completat și există un preț pentru acel tip, se folosește acesta în locul prețului de bază.
+func (service *Service) FindProductByBarcode(code string, priceTypeID uint) (*BarcodeLookup, error) {
+	code = validation.NormalizeBarcode(code)
+	if !validation.IsValidEAN(code) {
+		return nil, ErrInvalidBarcode
+	}
+
+	barcode, err := service.repository.FindProductBarcode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	price := barcode.Product.Price
+	if priceTypeID != 0 {
+		if pp, err := service.repository.FindPriceProduct(barcode.ProductID, priceTypeID); err == nil {
+			price = pp.Price
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	coefficient := barcode.Unit.Coefficient
+	if coefficient == 0 {
+		coefficient = 1
+	}
+
+	return &BarcodeLookup{
+		Barcode: barcode.Barcode,
+		Product: barcode.Product,
+		Unit:    barcode.Unit,
+		Price:   roundMoney(price * coefficient),
+	}, nil
+}
+
 // Order methods
+// CreateOrder calculează prețurile, TVA-ul (rata în vigoare la data documentului) și totalurile comenzii
 func (service *Service) CreateOrder(userID uint, order *models.Order) error {
-	total := 0.0
+	if order.Date.IsZero() {
+		now := time.Now()
+		order.Date = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	// Clientul se citește prin repository-ul restrâns la canalele utilizatorului: clienții altor canale nu se găsesc.
+	// Canalul comenzii este cel al contractului sau, fără contract (ori contract fără canal), al clientului.
+	client, err := service.repository.FindClientByID(order.ClientID)
+	if err != nil {
+		return err
+	}
+	order.ChannelID = client.ChannelID
+	if err := service.checkOrderContract(order); err != nil {
+		return err
+	}
+	if err := service.resolveOrderCurrency(order); err != nil {
+		return err
+	}
+
+	total, totalVat, totalBase, totalVatBase := 0.0, 0.0, 0.0, 0.0
 	for i := range order.OrderItems {
-		product, err := service.repository.FindProductByID(order.OrderItems[i].ProductID)
-		if err != nil {
+		item := &order.OrderItems[i]
+		if err := service.priceOrderItem(order, item); err != nil {
 			return err
 		}
-		order.OrderItems[i].Price = product.Price
-		total += product.Price * float64(order.OrderItems[i].Quantity)
+		total += item.SummWithVat
+		totalVat += item.VatSumm
+		totalBase += item.SummWithVatBase
+		totalVatBase += item.VatSummBase
 	}
 	order.OwnerID = userID
-	order.TotalPrice = total
-	order.Status = "pending"
+	order.TotalPrice = roundMoney(total)
+	order.TotalVat = roundMoney(totalVat)
+	order.TotalPriceBase = roundMoney(totalBase)
+	order.TotalVatBase = roundMoney(totalVatBase)
+	order.Status = models.OrderStatusPending
 	return service.repository.CreateOrder(order)
 }
 
+// priceOrderItem completează prețul, unitatea și sumele TVA ale unei poziții.
+// Prețul de listă vine din tipul de preț al comenzii (dacă există) sau din prețul de bază, înmulțit cu coeficientul unității.
+// Prețurile din catalog sunt în moneda de bază; pentru comenzile în valută se convertesc la cursul comenzii.
+// Acordul de preț al contractului, valabil la data comenzii, are prioritate față de prețul de listă.
+func (service *Service) priceOrderItem(order *models.Order, item *models.OrderItem) error {
+	product, err := service.repository.FindProductByID(item.ProductID)
+	if err != nil {
+		return err
+	}
+
+	if item.UnitID == 0 {
+		item.UnitID = product.UnitID
+	}
+	unit, err := service.repository.FindUnitByID(item.UnitID)
+	if err != nil {
+		return err
+	}
+	coefficient := unit.Coefficient
+	if coefficient == 0 {
+		coefficient = 1
+	}
+
+	price := product.Price
+	if order.PriceTypeID != 0 {
+		if pp, err := service.repository.FindPriceProduct(product.ID, order.PriceTypeID); err == nil {
+			price = pp.Price
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+
+	vatTax, err := service.repository.FindVatTaxByID(product.VatTaxID)
+	if err != nil {
+		return err
+	}
+	rate, err := service.VatRateAt(vatTax, order.Date)
+	if err != nil {
+		return err
+	}
+
+	exchangeRate := order.ExchangeRate
+	if exchangeRate <= 0 {
+		exchangeRate = 1
+	}
+
+	item.UnitName = unit.Name
+	item.ListPrice = roundMoney(price * coefficient / exchangeRate)
+	item.Price = item.ListPrice
+	item.ContractPriceID = nil
+	if order.ContractID != 0 {
+		agreement, err := service.repository.FindContractPriceAt(order.ContractID, product.ID, order.Date)
+		if err == nil {
+			item.Price = agreedPrice(agreement, item.ListPrice, coefficient)
+			item.ContractPriceID = &agreement.ID
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	item.VatTaxID = vatTax.ID
+	item.VatCategory = vatTax.Category
+	item.VatRate = rate
+	item.Summ = roundMoney(item.Price * item.Quantity)
+	item.VatSumm = roundMoney(item.Summ * rate / 100)
+	item.SummWithVat = roundMoney(item.Summ + item.VatSumm)
+	item.SummBase = roundMoney(item.Summ * exchangeRate)
+	item.VatSummBase = roundMoney(item.VatSumm * exchangeRate)
+	item.SummWithVatBase = roundMoney(item.SummBase + item.VatSummBase)
+	return nil
+}
+
+// ApproveOrder aprobă comanda nouă (pending); aprobarea se înregistrează în jurnalul de audit
+func (service *Service) ApproveOrder(userID, id uint) (*models.Order, error) {
+	order, err := service.repository.FindOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderStatusPending {
+		return nil, ErrOrderNotPending
+	}
+	audit := newAuditLog(userID, AuditOrderApprove, "order", order.ID, nil)
+	order.Status = models.OrderStatusApproved
+	if err := service.repository.UpdateOrderStatus(order, audit); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
 func (service *Service) FindOrderByID(id uint) (*models.Order, error) {
 	return service.repository.FindOrderByID(id)
 }
@@ -174,3 +647,58 @@ func (service *Service) FindOrderByID(id uint) (*models.Order, error) {
 func (service *Service) FindOrdersByUserID(userID uint) ([]models.Order, error) {
 	return service.repository.FindOrdersByUserID(userID)
 }
+
+func (service *Service) FindOrderDocument(id uint) (*models.Order, error) {
+	return service.repository.FindOrderDocument(id)
+}
+
+// VAT methods
+func (service *Service) FindAllVatTaxes() ([]models.VatTax, error) {
+	return service.repository.FindAllVatTaxes()
+}
+
+// VatRateAt întoarce rata TVA în vigoare la data dată.
+// Pentru livrările scutite sau cu cota zero rata este întotdeauna 0, indiferent de versiuni.
+func (service *Service) VatRateAt(vatTax *models.VatTax, date time.Time) (float64, error) {
+	if vatTax.Category == models.VatCategoryExempt || vatTax.Category == models.VatCategoryZeroRated {
+		return 0, nil
+	}
+	rate, err := service.repository.FindVatTaxRateAt(vatTax.ID, date)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return vatTax.Rate, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return rate.Rate, nil
+}
+
+// AddVatTaxRate adaugă o nouă versiune a ratei, în vigoare de la ValidFrom
+func (service *Service) AddVatTaxRate(rate *models.VatTaxRate) error {
+	vatTax, err := service.repository.FindVatTaxByID(rate.VatTaxID)
+	if err != nil {
+		return err
+	}
+	if rate.Rate < 0 || rate.Rate > 100 {
+		return ErrInvalidVatRate
+	}
+	if (vatTax.Category == models.VatCategoryExempt || vatTax.Category == models.VatCategoryZeroRated) && rate.Rate != 0 {
+		return ErrInvalidVatRate
+	}
+	return service.repository.CreateVatTaxRate(rate)
+}
+
+// Report methods
+// VatReport întoarce totalurile TVA pe categorii pentru perioada dată (inclusiv capetele)
+func (service *Service) VatReport(from, to time.Time) ([]models.VatSummary, error) {
+	rows, err := service.repository.VatReport(from, to)
+	if err != nil {
+		return nil, err
+	}
+	documents.SortVatSummary(rows)
+	return rows, nil
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Erori pentru autentificare și sesiuni
+var (
+	ErrInvalidCredentials = errors.New("invalid_credentials")
+	ErrSessionInvalid     = errors.New("session_invalid")
+)
+
+// TokenPair - token-ul de acces (JWT de scurtă durată) și refresh token-ul sesiunii
+type TokenPair struct {
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"` // Expirarea token-ului de acces
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// AccessClaims - datele din token-ul de acces verificat
+type AccessClaims struct {
+	UserID    uint
+	SessionID uint
+	Role      string
+}
+
+// Login verifică parola și deschide o sesiune nouă pe dispozitivul dat
+func (service *Service) Login(email, password, userAgent, ip string) (*TokenPair, error) {
+	user, err := service.repository.FindUserByEmail(strings.TrimSpace(email))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	refreshToken, hash, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	session := &models.Session{
+		UserID:     user.ID,
+		TokenHash:  hash,
+		ExpiresAt:  now.Add(service.refreshTokenTTL()),
+		LastUsedAt: now,
+		UserAgent:  truncate(userAgent, 255),
+		IP:         truncate(ip, 45),
+	}
+	if err := service.repository.CreateSession(session); err != nil {
+		return nil, err
+	}
+	return service.issueTokens(user, session, refreshToken)
+}
+
+// RefreshSession schimbă refresh token-ul cu unul nou și emite un token de acces nou.
+// Un refresh token deja înlocuit înseamnă că a fost furat sau copiat: sesiunea se anulează.
+func (service *Service) RefreshSession(refreshToken string) (*TokenPair, error) {
+	hash := hashToken(strings.TrimSpace(refreshToken))
+	session, err := service.repository.FindSessionByTokenHash(hash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSessionInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.TokenHash != hash {
+		if err := service.repository.RevokeSession(session.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrSessionInvalid
+	}
+	now := time.Now()
+	if !session.Active(now) {
+		return nil, ErrSessionInvalid
+	}
+	user, err := service.repository.FindUserByID(session.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSessionInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	newToken, newHash, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	session.PreviousTokenHash = hash
+	session.TokenHash = newHash
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(service.refreshTokenTTL())
+	err = service.repository.RotateSession(session, hash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Token-ul a fost folosit între timp de altă cerere sau sesiunea a fost anulată
+		return nil, ErrSessionInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	return service.issueTokens(user, session, newToken)
+}
+
+// Authenticate verifică token-ul de acces și sesiunea lui; token-urile sesiunilor anulate se resping
+func (service *Service) Authenticate(accessToken string) (*AccessClaims, error) {
+	token, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
+		return []byte(service.jwtSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return nil, ErrSessionInvalid
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrSessionInvalid
+	}
+	userID, okUser := claims["user_id"].(float64)
+	sessionID, okSession := claims["sid"].(float64)
+	role, _ := claims["role"].(string)
+	if !okUser || !okSession {
+		// Token-urile emise înainte de sesiuni nu au "sid"
+		return nil, ErrSessionInvalid
+	}
+
+	session, err := service.repository.FindSessionByID(uint(sessionID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSessionInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != uint(userID) || !session.Active(time.Now()) {
+		return nil, ErrSessionInvalid
+	}
+	return &AccessClaims{UserID: session.UserID, SessionID: session.ID, Role: role}, nil
+}
+
+// FindSessions întoarce sesiunile active ale utilizatorului (dispozitivele pe care este autentificat)
+func (service *Service) FindSessions(userID uint) ([]models.Session, error) {
+	return service.repository.FindActiveSessions(userID, time.Now())
+}
+
+// Logout anulează sesiunea curentă; token-ul de acces și refresh token-ul ei nu mai sunt acceptate
+func (service *Service) Logout(sessionID uint) error {
+	return service.repository.RevokeSession(sessionID)
+}
+
+// LogoutAll anulează toate sesiunile utilizatorului (ieșirea de pe toate dispozitivele).
+// Acțiunea se înregistrează în jurnalul de audit; actorID este utilizatorul însuși sau administratorul.
+func (service *Service) LogoutAll(actorID, userID uint) (int64, error) {
+	if _, err := service.repository.FindUserByID(userID); errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrUserNotFound
+	} else if err != nil {
+		return 0, err
+	}
+	audit := func(revoked int64) *models.AuditLog {
+		return newAuditLog(actorID, AuditUserLogoutAll, "user", userID, map[string]int64{"sessions": revoked})
+	}
+	return service.repository.RevokeUserSessions(userID, audit)
+}
+
+// DeleteExpiredSessions șterge sesiunile expirate (sarcina de fundal)
+func (service *Service) DeleteExpiredSessions() (int64, error) {
+	return service.repository.DeleteExpiredSessions(time.Now())
+}
+
+// issueTokens semnează token-ul de acces pentru sesiune
+func (service *Service) issueTokens(user *models.User, session *models.Session, refreshToken string) (*TokenPair, error) {
+	expiresAt := time.Now().Add(time.Duration(service.cfg.AccessTokenTTLMinutes) * time.Minute)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": user.ID,
+		"role":    user.Role,
+		"sid":     session.ID,
+		"exp":     expiresAt.Unix(),
+	})
+	signed, err := token.SignedString([]byte(service.jwtSecret))
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{Token: signed, ExpiresAt: expiresAt, RefreshToken: refreshToken, RefreshExpiresAt: session.ExpiresAt}, nil
+}
+
+func (service *Service) refreshTokenTTL() time.Duration {
+	return time.Duration(service.cfg.RefreshTokenTTLDays) * 24 * time.Hour
+}
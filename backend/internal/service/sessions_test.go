@@ -0,0 +1,201 @@
+package service
+
+import (
+	"errors"
+	"orders/internal/models"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newSessionTestService - serviciul cu un utilizator (parola "secret") în depozitul în memorie
+func newSessionTestService(t *testing.T) (*Service, *fakeRepository) {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := newFakeRepository()
+	repo.users[1] = &models.User{Email: "ion@example.md", Password: string(hash), Role: models.RoleManager}
+	repo.users[1].ID = 1
+	repo.nextID = 100
+	return newTestService(repo), repo
+}
+
+func login(t *testing.T, service *Service) *TokenPair {
+	t.Helper()
+	pair, err := service.Login(" ion@example.md ", "secret", "test", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return pair
+}
+
+func TestLogin(t *testing.T) {
+	service, repo := newSessionTestService(t)
+
+	if _, err := service.Login("ion@example.md", "wrong", "", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("wrong password: err = %v, want %v", err, ErrInvalidCredentials)
+	}
+	if _, err := service.Login("nimeni@example.md", "secret", "", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("unknown user: err = %v, want %v", err, ErrInvalidCredentials)
+	}
+	if len(repo.sessions) != 0 {
+		t.Fatalf("failed logins created %d sessions", len(repo.sessions))
+	}
+
+	pair := login(t, service)
+	if len(repo.sessions) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(repo.sessions))
+	}
+	for _, session := range repo.sessions {
+		if session.TokenHash != hashToken(pair.RefreshToken) {
+			t.Error("session does not store the hash of the issued refresh token")
+		}
+	}
+	claims, err := service.Authenticate(pair.Token)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if claims.UserID != 1 || claims.Role != models.RoleManager {
+		t.Errorf("Authenticate = %+v", claims)
+	}
+}
+
+func TestRefreshSessionRotatesToken(t *testing.T) {
+	service, repo := newSessionTestService(t)
+	first := login(t, service)
+
+	second, err := service.RefreshSession(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshSession: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+	claims, err := service.Authenticate(second.Token)
+	if err != nil {
+		t.Fatalf("Authenticate after refresh: %v", err)
+	}
+	session := repo.sessions[claims.SessionID]
+	if session.TokenHash != hashToken(second.RefreshToken) || session.PreviousTokenHash != hashToken(first.RefreshToken) {
+		t.Error("session does not store the new and the replaced token")
+	}
+
+	// Token-ul nou se poate reînnoi din nou, în aceeași sesiune
+	third, err := service.RefreshSession(second.RefreshToken)
+	if err != nil {
+		t.Fatalf("second refresh: %v", err)
+	}
+	if len(repo.sessions) != 1 {
+		t.Errorf("got %d sessions, want 1", len(repo.sessions))
+	}
+	if _, err := service.Authenticate(third.Token); err != nil {
+		t.Errorf("Authenticate after second refresh: %v", err)
+	}
+}
+
+func TestRefreshSessionReuseRevokesSession(t *testing.T) {
+	service, repo := newSessionTestService(t)
+	first := login(t, service)
+	second, err := service.RefreshSession(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshSession: %v", err)
+	}
+
+	// Token-ul înlocuit folosit din nou: sesiunea se anulează cu totul
+	if _, err := service.RefreshSession(first.RefreshToken); !errors.Is(err, ErrSessionInvalid) {
+		t.Fatalf("reused token: err = %v, want %v", err, ErrSessionInvalid)
+	}
+	for _, session := range repo.sessions {
+		if session.RevokedAt == nil {
+			t.Error("session was not revoked after token reuse")
+		}
+	}
+	if _, err := service.RefreshSession(second.RefreshToken); !errors.Is(err, ErrSessionInvalid) {
+		t.Errorf("current refresh token after revoke: err = %v, want %v", err, ErrSessionInvalid)
+	}
+	if _, err := service.Authenticate(second.Token); !errors.Is(err, ErrSessionInvalid) {
+		t.Errorf("access token after revoke: err = %v, want %v", err, ErrSessionInvalid)
+	}
+}
+
+func TestRefreshSessionRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(session *models.Session)
+	}{
+		{"expired", func(session *models.Session) { session.ExpiresAt = time.Now().Add(-time.Minute) }},
+		{"revoked", func(session *models.Session) {
+			revokedAt := time.Now()
+			session.RevokedAt = &revokedAt
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := newSessionTestService(t)
+			pair := login(t, service)
+			for _, session := range repo.sessions {
+				tt.modify(session)
+			}
+			if _, err := service.RefreshSession(pair.RefreshToken); !errors.Is(err, ErrSessionInvalid) {
+				t.Errorf("err = %v, want %v", err, ErrSessionInvalid)
+			}
+		})
+	}
+
+	service, _ := newSessionTestService(t)
+	if _, err := service.RefreshSession("necunoscut"); !errors.Is(err, ErrSessionInvalid) {
+		t.Errorf("unknown token: err = %v, want %v", err, ErrSessionInvalid)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	service, _ := newSessionTestService(t)
+	phone := login(t, service)
+	laptop := login(t, service)
+
+	claims, err := service.Authenticate(phone.Token)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if err := service.Logout(claims.SessionID); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := service.Authenticate(phone.Token); !errors.Is(err, ErrSessionInvalid) {
+		t.Errorf("access token after logout: err = %v, want %v", err, ErrSessionInvalid)
+	}
+	if _, err := service.RefreshSession(phone.RefreshToken); !errors.Is(err, ErrSessionInvalid) {
+		t.Errorf("refresh token after logout: err = %v, want %v", err, ErrSessionInvalid)
+	}
+	// Celelalte dispozitive rămân autentificate
+	if _, err := service.Authenticate(laptop.Token); err != nil {
+		t.Errorf("other device session: %v", err)
+	}
+}
+
+func TestLogoutAll(t *testing.T) {
+	service, repo := newSessionTestService(t)
+	phone := login(t, service)
+	laptop := login(t, service)
+
+	revoked, err := service.LogoutAll(1, 1)
+	if err != nil {
+		t.Fatalf("LogoutAll: %v", err)
+	}
+	if revoked != 2 {
+		t.Errorf("revoked = %d, want 2", revoked)
+	}
+	if len(repo.audits) != 1 || repo.audits[0].Action != AuditUserLogoutAll {
+		t.Errorf("audit logs = %+v", repo.audits)
+	}
+	for _, pair := range []*TokenPair{phone, laptop} {
+		if _, err := service.Authenticate(pair.Token); !errors.Is(err, ErrSessionInvalid) {
+			t.Errorf("Authenticate after LogoutAll: err = %v, want %v", err, ErrSessionInvalid)
+		}
+	}
+	if _, err := service.LogoutAll(1, 99); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("unknown user: err = %v, want %v", err, ErrUserNotFound)
+	}
+}
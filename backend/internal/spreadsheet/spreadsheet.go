@@ -0,0 +1,88 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrUnknownFormat - fișierul nu este nici CSV, nici XLSX
+var ErrUnknownFormat = errors.New("unknown_file_format")
+
+// Read citește primul tabel dintr-un fișier CSV sau XLSX.
+// Formatul se determină după conținut (XLSX este o arhivă ZIP), apoi după extensia fișierului.
+// Rândul i din rezultat corespunde rândului i+1 din fișier (rândurile goale din XLSX se păstrează ca rânduri goale).
+func Read(fileName string, data []byte) ([][]string, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return ReadXLSX(data)
+	case strings.HasSuffix(strings.ToLower(fileName), ".xlsx"):
+		return nil, ErrUnknownFormat
+	default:
+		return ReadCSV(bytes.NewReader(data))
+	}
+}
+
+// ReadCSV citește un fișier CSV. Separatorul (",", ";" sau tab) se detectează din primul rând,
+// deoarece Excel cu setări regionale românești/rusești salvează CSV cu ";".
+func ReadCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // BOM UTF-8 scris de Excel
+
+	firstLine := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		firstLine = data[:i]
+	}
+	delimiter := ','
+	best := bytes.Count(firstLine, []byte(","))
+	for _, d := range []rune{';', '\t'} {
+		if n := bytes.Count(firstLine, []byte(string(d))); n > best {
+			delimiter, best = d, n
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	// csv.Reader sare peste rândurile goale: le păstrăm ca rânduri goale, ca numerotarea să fie cea din Excel.
+	// O celulă pe mai multe linii (între ghilimele) rămâne un singur rând.
+	var rows [][]string
+	lastLine := 0 // Linia din fișier pe care s-a terminat înregistrarea precedentă
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		for ; lastLine < line-1; lastLine++ {
+			rows = append(rows, nil)
+		}
+		rows = append(rows, record)
+
+		last := len(record) - 1
+		endLine, _ := reader.FieldPos(last)
+		lastLine = endLine + strings.Count(record[last], "\n")
+	}
+}
+
+// WriteCSV scrie rândurile în format CSV (cu BOM, ca Excel să recunoască UTF-8)
+func WriteCSV(w io.Writer, rows [][]string) error {
+	if _, err := w.Write([]byte("\xef\xbb\xbf")); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
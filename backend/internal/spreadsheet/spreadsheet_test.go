@@ -0,0 +1,44 @@
+package spreadsheet
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want [][]string
+	}{
+		{"comma", "name,fiscal_id\nAgro,1003600001232\n", [][]string{{"name", "fiscal_id"}, {"Agro", "1003600001232"}}},
+		{"semicolon from excel", "\xef\xbb\xbfname;fiscal_id\nAgro, SRL;1003600001232\n", [][]string{{"name", "fiscal_id"}, {"Agro, SRL", "1003600001232"}}},
+		{"tab", "name\tphone\nAgro\t069123456\n", [][]string{{"name", "phone"}, {"Agro", "069123456"}}},
+		{"blank rows are kept", "name\n\nAgro\n", [][]string{{"name"}, nil, {"Agro"}}},
+		{"multi-line cell", "name;address\n\"Agro\";\"str. 1\nChișinău\"\nBeta;x\n", [][]string{{"name", "address"}, {"Agro", "str. 1\nChișinău"}, {"Beta", "x"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ReadCSV(strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("ReadCSV: %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("rows = %q, want %q", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	rows, err := Read("clients.csv", []byte("name\nAgro\n"))
+	if err != nil || !reflect.DeepEqual(rows, [][]string{{"name"}, {"Agro"}}) {
+		t.Errorf("Read(csv) = %q, %v", rows, err)
+	}
+	// Extensia .xlsx fără conținut ZIP nu se citește ca CSV
+	if _, err := Read("clients.xlsx", []byte("name\nAgro\n")); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Read(fake xlsx) err = %v, want %v", err, ErrUnknownFormat)
+	}
+}
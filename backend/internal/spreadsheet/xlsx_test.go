@@ -0,0 +1,139 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// buildXLSX creează o arhivă XLSX minimă cu fișierele date (cale -> conținut)
+func buildXLSX(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const (
+	testWorkbook = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+		xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+		<sheets><sheet name="Clienți" sheetId="1" r:id="rId1"/><sheet name="Altă foaie" sheetId="2" r:id="rId2"/></sheets>
+	</workbook>`
+	testSharedStrings = `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+		<si><t>Denumire</t></si>
+		<si><t>Cod fiscal</t></si>
+		<si><r><t>SRL </t></r><r><t>Agrotehnica</t></r></si>
+	</sst>`
+	testSheet = `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+		<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+		<row r="3"><c r="A3" t="s"><v>2</v></c><c r="C3"><v>1003600001232</v></c></row>
+		<row r="4"><c r="B4" t="inlineStr"><is><t>Chișinău</t></is></c></row>
+		<row><c t="inlineStr"><is><t>fără referințe</t></is></c><c><v>42</v></c></row>
+	</sheetData></worksheet>`
+)
+
+func TestReadXLSX(t *testing.T) {
+	tests := []struct {
+		name string
+		rels string
+		path string
+	}{
+		{"relative target", `<Relationships><Relationship Id="rId2" Target="worksheets/sheet2.xml"/><Relationship Id="rId1" Target="worksheets/first.xml"/></Relationships>`, "xl/worksheets/first.xml"},
+		{"absolute target", `<Relationships><Relationship Id="rId1" Target="/xl/worksheets/first.xml"/></Relationships>`, "xl/worksheets/first.xml"},
+		{"no relationships", "", "xl/worksheets/sheet1.xml"},
+	}
+	want := [][]string{
+		{"Denumire", "Cod fiscal"},
+		nil,
+		{"SRL Agrotehnica", "", "1003600001232"},
+		{"", "Chișinău"},
+		{"fără referințe", "42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{
+				"xl/workbook.xml":      testWorkbook,
+				"xl/sharedStrings.xml": testSharedStrings,
+				tt.path:                testSheet,
+			}
+			if tt.rels != "" {
+				files["xl/_rels/workbook.xml.rels"] = tt.rels
+			}
+			rows, err := ReadXLSX(buildXLSX(t, files))
+			if err != nil {
+				t.Fatalf("ReadXLSX: %v", err)
+			}
+			if !reflect.DeepEqual(rows, want) {
+				t.Errorf("rows = %q, want %q", rows, want)
+			}
+		})
+	}
+}
+
+func TestReadXLSXErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"not a zip", []byte("Denumire;Cod fiscal"), ErrUnknownFormat},
+		{"zip without workbook", buildXLSX(t, map[string]string{"word/document.xml": "<document/>"}), ErrUnknownFormat},
+		{"missing sheet", buildXLSX(t, map[string]string{"xl/workbook.xml": testWorkbook}), nil},
+		{"invalid shared string index", buildXLSX(t, map[string]string{
+			"xl/workbook.xml":          testWorkbook,
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="s"><v>5</v></c></row></sheetData></worksheet>`,
+		}), nil},
+		{"rows out of order", buildXLSX(t, map[string]string{
+			"xl/workbook.xml":          testWorkbook,
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="2"><c><v>1</v></c></row><row r="1"><c><v>2</v></c></row></sheetData></worksheet>`,
+		}), nil},
+		{"invalid cell reference", buildXLSX(t, map[string]string{
+			"xl/workbook.xml":          testWorkbook,
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="12"><v>1</v></c></row></sheetData></worksheet>`,
+		}), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadXLSX(tt.data)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{"A1", 0},
+		{"C12", 2},
+		{"Z3", 25},
+		{"AA10", 26},
+		{"AB1", 27},
+	}
+	for _, tt := range tests {
+		got, err := columnIndex(tt.ref)
+		if err != nil || got != tt.want {
+			t.Errorf("columnIndex(%q) = %d, %v, want %d", tt.ref, got, err, tt.want)
+		}
+	}
+}
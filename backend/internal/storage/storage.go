@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound este întoarsă când obiectul nu există în stocare
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage - interfața pentru stocarea fișierelor (blob-uri).
+// Cheile sunt căi relative de forma "product/12/<uuid>.jpg".
+type Storage interface {
+	Save(key string, r io.Reader) (int64, error)
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+// LocalStorage păstrează fișierele pe discul local, sub directorul Root
+type LocalStorage struct {
+	Root string
+}
+
+// Creează o nouă instanță de LocalStorage și directorul rădăcină, dacă lipsește
+func NewLocalStorage(root string) (*LocalStorage, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("storage: cannot create root %s: %w", root, err)
+	}
+	return &LocalStorage{Root: root}, nil
+}
+
+// path transformă cheia în cale absolută și refuză cheile care ies din Root
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := filepath.Clean("/" + filepath.FromSlash(key))
+	if clean == string(filepath.Separator) {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	full := filepath.Join(s.Root, clean)
+	if !strings.HasPrefix(full, filepath.Clean(s.Root)+string(filepath.Separator)) {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	return full, nil
+}
+
+// Save scrie conținutul într-un fișier temporar și îl redenumește la final,
+// astfel încât un upload întrerupt să nu lase fișiere incomplete.
+func (s *LocalStorage) Save(key string, r io.Reader) (int64, error) {
+	full, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(full), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if err != nil {
+		tmp.Close()
+		return n, err
+	}
+	if err := tmp.Close(); err != nil {
+		return n, err
+	}
+	return n, os.Rename(tmp.Name(), full)
+}
+
+func (s *LocalStorage) Open(key string) (io.ReadCloser, error) {
+	full, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(full)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *LocalStorage) Delete(key string) error {
+	full, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(full); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
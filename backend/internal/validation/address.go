@@ -0,0 +1,51 @@
+package validation
+
+import "strings"
+
+// NormalizeCUATMCode aduce codul localității din clasificatorul CUATM la 7 cifre.
+// Excel pierde zerourile de la început ("100000" pentru "0100000"), de aceea codurile mai scurte se completează cu zerouri.
+// Întoarce ok = false dacă codul conține altceva decât cifre sau are mai mult de 7 cifre.
+func NormalizeCUATMCode(code string) (string, bool) {
+	code = strings.TrimSpace(code)
+	if code == "" || len(code) > 7 {
+		return "", false
+	}
+	for i := 0; i < len(code); i++ {
+		if !isDigit(code[i]) {
+			return "", false
+		}
+	}
+	return strings.Repeat("0", 7-len(code)) + code, true
+}
+
+// CUATMDistrictCode - codul raionului (municipiului, UTA) din care face parte localitatea: primele două cifre, urmate de zerouri
+func CUATMDistrictCode(code string) string {
+	if len(code) != 7 {
+		return ""
+	}
+	return code[:2] + "00000"
+}
+
+// NormalizePostalCode aduce codul poștal moldovenesc la forma "MD-2001" ("2001", "MD2001", "md 2001").
+// Întoarce "" pentru un cod gol sau ok = false dacă nu sunt exact 4 cifre.
+func NormalizePostalCode(code string) (string, bool) {
+	c := strings.ToUpper(strings.Join(strings.Fields(code), ""))
+	if c == "" {
+		return "", true
+	}
+	c = strings.TrimPrefix(strings.TrimPrefix(c, "MD"), "-")
+	if len(c) != 4 {
+		return "", false
+	}
+	for i := 0; i < len(c); i++ {
+		if !isDigit(c[i]) {
+			return "", false
+		}
+	}
+	return "MD-" + c, true
+}
+
+// IsValidCoordinates verifică latitudinea (-90..90) și longitudinea (-180..180)
+func IsValidCoordinates(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
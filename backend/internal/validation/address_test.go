@@ -0,0 +1,64 @@
+package validation
+
+import "testing"
+
+func TestNormalizeCUATMCode(t *testing.T) {
+	tests := []struct {
+		code   string
+		want   string
+		wantOK bool
+	}{
+		{"0100000", "0100000", true},
+		{"100000", "0100000", true}, // Excel a pierdut zeroul de la început
+		{" 3601000 ", "3601000", true},
+		{"1", "0000001", true},
+		{"", "", false},
+		{"12345678", "", false},
+		{"01A0000", "", false},
+		{"-100000", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := NormalizeCUATMCode(tt.code)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("NormalizeCUATMCode(%q) = %q, %v, want %q, %v", tt.code, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestCUATMDistrictCode(t *testing.T) {
+	tests := []struct {
+		code, want string
+	}{
+		{"3601234", "3600000"},
+		{"0100000", "0100000"},
+		{"360123", ""},
+	}
+	for _, tt := range tests {
+		if got := CUATMDistrictCode(tt.code); got != tt.want {
+			t.Errorf("CUATMDistrictCode(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizePostalCode(t *testing.T) {
+	tests := []struct {
+		code   string
+		want   string
+		wantOK bool
+	}{
+		{"2001", "MD-2001", true},
+		{"MD2001", "MD-2001", true},
+		{"md 2001", "MD-2001", true},
+		{"MD-2001", "MD-2001", true},
+		{"", "", true},
+		{"200", "", false},
+		{"MD-20011", "", false},
+		{"MD-20A1", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := NormalizePostalCode(tt.code)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("NormalizePostalCode(%q) = %q, %v, want %q, %v", tt.code, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
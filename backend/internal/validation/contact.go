@@ -0,0 +1,104 @@
+package validation
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// Regiunea implicită a numerelor de telefon scrise fără prefixul de țară
+const DefaultPhoneRegion = "MD"
+
+// phoneRegion - prefixul de țară și lungimea numărului național (fără 0 de la început)
+type phoneRegion struct {
+	countryCode    string
+	nationalLength int
+}
+
+var phoneRegions = map[string]phoneRegion{
+	"MD": {countryCode: "373", nationalLength: 8},
+}
+
+// Valorile scrise în loc de email când acesta lipsește
+var emailPlaceholders = map[string]bool{
+	"": true, "-": true, "n/a": true, "na": true, "none": true, "null": true,
+	"not inserted": true, "not_inserted": true, "no email": true, "fara email": true,
+}
+
+// IsEmailPlaceholder - valoarea înseamnă "fără email" (inclusiv placeholder-ele generate anterior, placeholder_...@local.invalid)
+func IsEmailPlaceholder(email string) bool {
+	e := strings.ToLower(strings.TrimSpace(email))
+	return emailPlaceholders[e] || strings.HasSuffix(e, "@local.invalid")
+}
+
+// NormalizeEmail întoarce adresa cu litere mici, "" pentru placeholder-e
+// sau ok = false dacă adresa nu este validă (ex: lipsește domeniul, conține spații sau un nume afișat).
+func NormalizeEmail(email string) (string, bool) {
+	if IsEmailPlaceholder(email) {
+		return "", true
+	}
+	e := strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(e)
+	if err != nil || addr.Address != e || addr.Name != "" || len(e) > 100 {
+		return "", false
+	}
+	at := strings.LastIndexByte(e, '@')
+	domain := e[at+1:]
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") || strings.Contains(domain, "..") {
+		return "", false
+	}
+	return e, true
+}
+
+// NormalizePhone aduce numărul la formatul E.164 (+37369123456). Numerele fără prefix de țară
+// se consideră din regiunea region ("MD": 069123456, 69123456). Spațiile, cratimele, punctele și parantezele se ignoră.
+// Întoarce "" pentru un număr gol sau ok = false dacă numărul nu poate fi recunoscut.
+func NormalizePhone(phone, region string) (string, bool) {
+	p := strings.TrimSpace(phone)
+	if p == "" {
+		return "", true
+	}
+
+	international := false
+	switch {
+	case strings.HasPrefix(p, "+"):
+		international, p = true, p[1:]
+	case strings.HasPrefix(p, "00"):
+		international, p = true, p[2:]
+	}
+
+	var b strings.Builder
+	for _, r := range p {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')' || r == '/':
+		default:
+			return "", false
+		}
+	}
+	digits := b.String()
+
+	reg, known := phoneRegions[region]
+	if !international && known {
+		switch {
+		case len(digits) == reg.nationalLength+1 && digits[0] == '0':
+			digits = reg.countryCode + digits[1:]
+		case len(digits) == reg.nationalLength && digits[0] != '0':
+			digits = reg.countryCode + digits
+		case len(digits) == len(reg.countryCode)+reg.nationalLength && strings.HasPrefix(digits, reg.countryCode):
+		default:
+			return "", false
+		}
+	}
+
+	// E.164: maximum 15 cifre, fără 0 la început; pentru țările cunoscute se verifică și lungimea
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return "", false
+	}
+	for _, r := range phoneRegions {
+		if strings.HasPrefix(digits, r.countryCode) && len(digits) != len(r.countryCode)+r.nationalLength {
+			return "", false
+		}
+	}
+	return "+" + digits, true
+}
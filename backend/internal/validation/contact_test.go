@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name   string
+		phone  string
+		want   string
+		wantOK bool
+	}{
+		{"national with 0", "069123456", "+37369123456", true},
+		{"national without 0", "69123456", "+37369123456", true},
+		{"separators", "(069) 12-34-56", "+37369123456", true},
+		{"e.164", "+37369123456", "+37369123456", true},
+		{"00 prefix", "0037369123456", "+37369123456", true},
+		{"country code without plus", "37369123456", "+37369123456", true},
+		{"landline", "022 123 456", "+37322123456", true},
+		{"other country", "+40 721 234 567", "+40721234567", true},
+		{"empty", "  ", "", true},
+		{"too short national", "0691234", "", false},
+		{"too long for moldova", "+373691234567", "", false},
+		{"letters", "069ABC456", "", false},
+		{"too long e.164", "+1234567890123456", "", false},
+		{"international starting with 0", "+0123456789", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizePhone(tt.phone, DefaultPhoneRegion)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("NormalizePhone(%q) = %q, %v, want %q, %v", tt.phone, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		name   string
+		email  string
+		want   string
+		wantOK bool
+	}{
+		{"plain", "office@example.md", "office@example.md", true},
+		{"upper case and spaces", "  Office@Example.MD ", "office@example.md", true},
+		{"subdomain", "a.b+tag@mail.example.com", "a.b+tag@mail.example.com", true},
+		{"placeholder n/a", "N/A", "", true},
+		{"generated placeholder", "placeholder_12@local.invalid", "", true},
+		{"empty", "", "", true},
+		{"missing domain", "office@", "", false},
+		{"domain without dot", "office@localhost", "", false},
+		{"domain ends with dot", "office@example.", "", false},
+		{"double dot", "office@example..md", "", false},
+		{"display name", "Office <office@example.md>", "", false},
+		{"space inside", "off ice@example.md", "", false},
+		{"too long", strings.Repeat("a", 95) + "@ex.md", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizeEmail(tt.email)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("NormalizeEmail(%q) = %q, %v, want %q, %v", tt.email, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package validation
+
+import "strings"
+
+// NormalizeBarcode elimină spațiile și cratimele introduse de scanere sau de operator.
+func NormalizeBarcode(code string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code))
+}
+
+// IsValidEAN verifică un cod EAN-8, UPC-A (12), EAN-13 sau GTIN-14 după cifra de control.
+func IsValidEAN(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(code)-1; i++ {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		digit := int(c - '0')
+		// Ponderile alternează 3,1,3,1... începând de la cifra din dreapta (fără cifra de control)
+		if (len(code)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	last := code[len(code)-1]
+	if last < '0' || last > '9' {
+		return false
+	}
+	return (10-sum%10)%10 == int(last-'0')
+}
@@ -0,0 +1,35 @@
+package validation
+
+import "testing"
+
+func TestIsValidEAN(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want bool
+	}{
+		{"ean-13", "4006381333931", true},
+		{"ean-13 moldova", "4840000000015", true},
+		{"ean-8", "96385074", true},
+		{"upc-a", "036000291452", true},
+		{"gtin-14", "10012345678902", true},
+		{"wrong check digit", "4006381333932", false},
+		{"wrong length", "400638133393", false},
+		{"empty", "", false},
+		{"letters", "40063813339A1", false},
+		{"letter as check digit", "400638133393X", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidEAN(tt.code); got != tt.want {
+				t.Errorf("IsValidEAN(%q) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeBarcode(t *testing.T) {
+	if got := NormalizeBarcode(" 4006-3813 33931 "); got != "4006381333931" {
+		t.Errorf("NormalizeBarcode = %q, want %q", got, "4006381333931")
+	}
+}
@@ -0,0 +1,66 @@
+package validation
+
+import "strings"
+
+// Tipurile codurilor fiscale din Republica Moldova
+const (
+	FiscalIDKindIDNO = "IDNO" // Numărul de identificare de stat al persoanei juridice
+	FiscalIDKindIDNP = "IDNP" // Numărul de identificare personal al persoanei fizice
+)
+
+// Motivele pentru care un cod fiscal nu este valid
+const (
+	FiscalIDInvalidFormat   = "invalid_format"   // Nu are exact 13 cifre
+	FiscalIDInvalidChecksum = "invalid_checksum" // Cifra de control nu corespunde
+	FiscalIDWrongKind       = "wrong_kind"       // Cod valid, dar de alt tip (ex: IDNP la o companie)
+)
+
+// NormalizeFiscalID elimină spațiile introduse la copierea codului din documente.
+func NormalizeFiscalID(code string) string {
+	return strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+}
+
+// FiscalIDKind determină tipul codului după prima cifră: IDNO începe cu 1, IDNP cu 0 sau 2.
+// Întoarce "" dacă prima cifră nu corespunde niciunui tip.
+func FiscalIDKind(code string) string {
+	if code == "" {
+		return ""
+	}
+	switch code[0] {
+	case '1':
+		return FiscalIDKindIDNO
+	case '0', '2':
+		return FiscalIDKindIDNP
+	}
+	return ""
+}
+
+// CheckFiscalID verifică un IDNO / IDNP: 13 cifre, cifra de control și tipul așteptat.
+// Cifra de control este suma primelor 12 cifre înmulțite cu ponderile 7,3,1,7,3,1... modulo 10.
+// Dacă expectedKind este "", se acceptă ambele tipuri. Întoarce "" pentru un cod valid sau motivul erorii.
+func CheckFiscalID(code, expectedKind string) string {
+	if len(code) != 13 {
+		return FiscalIDInvalidFormat
+	}
+
+	weights := [3]int{7, 3, 1}
+	sum := 0
+	for i := 0; i < 13; i++ {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return FiscalIDInvalidFormat
+		}
+		if i < 12 {
+			sum += int(c-'0') * weights[i%3]
+		}
+	}
+	if sum%10 != int(code[12]-'0') {
+		return FiscalIDInvalidChecksum
+	}
+
+	kind := FiscalIDKind(code)
+	if kind == "" || (expectedKind != "" && kind != expectedKind) {
+		return FiscalIDWrongKind
+	}
+	return ""
+}
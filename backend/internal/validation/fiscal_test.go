@@ -0,0 +1,38 @@
+package validation
+
+import "testing"
+
+func TestCheckFiscalID(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected string
+		want     string
+	}{
+		{"idno", "1003600001232", FiscalIDKindIDNO, ""},
+		{"idnp starting with 2", "2001004012347", FiscalIDKindIDNP, ""},
+		{"idnp starting with 0", "0987654321092", FiscalIDKindIDNP, ""},
+		{"any kind", "1003600001232", "", ""},
+		{"idno where idnp expected", "1003600001232", FiscalIDKindIDNP, FiscalIDWrongKind},
+		{"idnp where idno expected", "2001004012347", FiscalIDKindIDNO, FiscalIDWrongKind},
+		{"unknown first digit", "3000000000012", "", FiscalIDWrongKind},
+		{"wrong check digit", "1003600001233", "", FiscalIDInvalidChecksum},
+		{"12 digits", "100360000123", "", FiscalIDInvalidFormat},
+		{"14 digits", "10036000012320", "", FiscalIDInvalidFormat},
+		{"letters", "10036000O1232", "", FiscalIDInvalidFormat},
+		{"empty", "", "", FiscalIDInvalidFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckFiscalID(tt.code, tt.expected); got != tt.want {
+				t.Errorf("CheckFiscalID(%q, %q) = %q, want %q", tt.code, tt.expected, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeFiscalID(t *testing.T) {
+	if got := NormalizeFiscalID(" 1003 6000 01232 "); got != "1003600001232" {
+		t.Errorf("NormalizeFiscalID = %q, want %q", got, "1003600001232")
+	}
+}
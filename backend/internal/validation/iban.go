@@ -0,0 +1,94 @@
+package validation
+
+import "strings"
+
+// Motivele pentru care un IBAN nu este valid
+const (
+	IBANInvalidFormat   = "invalid_format"   // Caractere nepermise sau structura nu este "țară + cifre de control + cont"
+	IBANInvalidLength   = "invalid_length"   // Lungimea nu corespunde țării
+	IBANInvalidChecksum = "invalid_checksum" // Cifrele de control nu corespund (modulo 97)
+)
+
+// Lungimea IBAN-ului pe țări (registrul SWIFT); pentru celelalte țări se acceptă 15..34 caractere
+var ibanLengths = map[string]int{
+	"MD": 24, "RO": 24, "UA": 29, "BG": 22, "PL": 28, "HU": 28, "CZ": 24, "SK": 24, "LT": 20, "LV": 21, "EE": 20,
+	"DE": 22, "AT": 20, "CH": 21, "FR": 27, "IT": 27, "ES": 24, "PT": 25, "NL": 18, "BE": 16, "LU": 20, "IE": 22,
+	"GB": 22, "DK": 18, "SE": 24, "NO": 15, "FI": 18, "GR": 27, "CY": 28, "TR": 26, "GE": 22, "AZ": 28, "BY": 28,
+	"IL": 23, "AE": 23, "RS": 22, "HR": 21, "SI": 19, "AL": 28, "ME": 22, "MK": 19, "BA": 20, "MT": 31,
+}
+
+// NormalizeIBAN elimină spațiile (IBAN-ul se scrie adesea în grupe de câte 4) și trece literele în majuscule
+func NormalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(iban), ""))
+}
+
+// CheckIBAN verifică un IBAN deja normalizat: structura, lungimea pentru țara lui și cifrele de control
+// (ISO 13616: primele 4 caractere se mută la sfârșit, literele devin numere A=10..Z=35, restul împărțirii la 97 este 1).
+// Întoarce "" pentru un IBAN valid sau motivul erorii.
+func CheckIBAN(iban string) string {
+	if len(iban) < 4 || !isUpperLetter(iban[0]) || !isUpperLetter(iban[1]) || !isDigit(iban[2]) || !isDigit(iban[3]) {
+		return IBANInvalidFormat
+	}
+	for i := 4; i < len(iban); i++ {
+		if !isUpperLetter(iban[i]) && !isDigit(iban[i]) {
+			return IBANInvalidFormat
+		}
+	}
+	if n, ok := ibanLengths[iban[:2]]; (ok && len(iban) != n) || (!ok && (len(iban) < 15 || len(iban) > 34)) {
+		return IBANInvalidLength
+	}
+
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+	for i := 0; i < len(rearranged); i++ {
+		c := rearranged[i]
+		if isDigit(c) {
+			remainder = (remainder*10 + int(c-'0')) % 97
+		} else {
+			remainder = (remainder*100 + int(c-'A'+10)) % 97
+		}
+	}
+	if remainder != 1 {
+		return IBANInvalidChecksum
+	}
+	return ""
+}
+
+// IBANCountry întoarce codul țării din IBAN ("MD")
+func IBANCountry(iban string) string {
+	if len(iban) < 2 {
+		return ""
+	}
+	return iban[:2]
+}
+
+// NormalizeBIC elimină spațiile și trece codul în majuscule
+func NormalizeBIC(bic string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(bic), ""))
+}
+
+// IsValidBIC verifică forma codului BIC / SWIFT: 4 litere (banca), 2 litere (țara), 2 caractere (localitatea)
+// și, opțional, 3 caractere (filiala). Exemplu: "AGRNMD2X".
+func IsValidBIC(bic string) bool {
+	if len(bic) != 8 && len(bic) != 11 {
+		return false
+	}
+	for i := 0; i < len(bic); i++ {
+		c := bic[i]
+		switch {
+		case i < 6 && !isUpperLetter(c):
+			return false
+		case i >= 6 && !isUpperLetter(c) && !isDigit(c):
+			return false
+		}
+	}
+	return true
+}
+
+func isUpperLetter(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,66 @@
+package validation
+
+import "testing"
+
+func TestCheckIBAN(t *testing.T) {
+	tests := []struct {
+		name string
+		iban string
+		want string
+	}{
+		{"moldova", "MD24AG000225100013104168", ""},
+		{"germany", "DE89370400440532013000", ""},
+		{"united kingdom", "GB82WEST12345698765432", ""},
+		{"unknown country in range", "XK051212012345678906", ""},
+		{"empty", "", IBANInvalidFormat},
+		{"lowercase country", "md24AG000225100013104168", IBANInvalidFormat},
+		{"letters instead of check digits", "MDXXAG000225100013104168", IBANInvalidFormat},
+		{"punctuation", "MD24AG00-225100013104168", IBANInvalidFormat},
+		{"too short for country", "MD24AG00022510001310416", IBANInvalidLength},
+		{"too long for country", "DE893704004405320130001", IBANInvalidLength},
+		{"unknown country too short", "XK0512120123", IBANInvalidLength},
+		{"wrong check digits", "MD25AG000225100013104168", IBANInvalidChecksum},
+		{"swapped digits", "DE89370400440532031000", IBANInvalidChecksum},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckIBAN(tt.iban); got != tt.want {
+				t.Errorf("CheckIBAN(%q) = %q, want %q", tt.iban, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeIBAN(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"MD24 AG00 0225 1000 1310 4168", "MD24AG000225100013104168"},
+		{" md24ag000225100013104168\t", "MD24AG000225100013104168"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeIBAN(tt.in); got != tt.want {
+			t.Errorf("NormalizeIBAN(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsValidBIC(t *testing.T) {
+	tests := []struct {
+		bic  string
+		want bool
+	}{
+		{"AGRNMD2X", true},
+		{"DEUTDEFF500", true},
+		{"AGRNMD2", false},
+		{"AGRNMD2X5", false},
+		{"AGR1MD2X", false},
+		{"agrnmd2x", false},
+	}
+	for _, tt := range tests {
+		if got := IsValidBIC(tt.bic); got != tt.want {
+			t.Errorf("IsValidBIC(%q) = %v, want %v", tt.bic, got, tt.want)
+		}
+	}
+}